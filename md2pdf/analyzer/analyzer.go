@@ -5,11 +5,14 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
 
 	"github.com/ledongthuc/pdf"
+
+	"md2pdf/logging"
 )
 
 // SectionInput는 converter에서 출력한 JSON 형식
@@ -30,44 +33,69 @@ type SubHeading struct {
 
 // SectionPage는 섹션 ID와 페이지 번호 매핑
 type SectionPage struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	Page  int    `json:"page"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Page   int    `json:"page"`
+	Method string `json:"method"` // destination(앵커), text(제목 검색), unresolved(찾지 못함)
 }
 
 // Result는 PDF 분석 결과
 type Result struct {
 	TotalPages int           `json:"total_pages"`
+	SkipPages  int           `json:"skip_pages"`
 	Sections   []SectionPage `json:"sections"`
 }
 
+// Options는 Analyze 옵션
+type Options struct {
+	SkipPages int            // 0 이하면 자동 감지, 양수면 지정한 값 사용
+	Logger    logging.Logger // 기본값: logging.Default
+}
+
 // AnalyzePDF analyzes a PDF to find which page each section starts on.
 // sectionsJSONPath is the path to sections JSON from converter.
 // skipPages: 0 or negative for auto-detect, positive for manual.
 func AnalyzePDF(pdfPath, sectionsJSONPath string, skipPages int) (*Result, error) {
-	// Open PDF
-	f, r, err := pdf.Open(pdfPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open PDF: %w", err)
-	}
-	defer f.Close()
-
-	totalPages := r.NumPage()
-	fmt.Fprintf(os.Stderr, "[INFO] PDF has %d pages\n", totalPages)
-
-	// Parse sections JSON
-	var sections []SectionPage
+	var sectionInputs []SectionInput
 	if sectionsJSONPath != "" {
 		jsonData, err := os.ReadFile(sectionsJSONPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read sections file: %w", err)
 		}
-
-		var sectionInputs []SectionInput
 		if err := json.Unmarshal(jsonData, &sectionInputs); err != nil {
 			return nil, fmt.Errorf("failed to parse sections JSON: %w", err)
 		}
+	}
+
+	f, err := os.Open(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	return Analyze(f, stat.Size(), sectionInputs, Options{SkipPages: skipPages})
+}
+
+// Analyze는 src에서 읽은 PDF에서 각 섹션이 시작하는 쪽을 찾는다.
+// 쪽 번호는 PDF의 Named Destination(섹션·하위 제목 앵커마다 하나)으로 정하고,
+// 제목 텍스트 검색은 Destination이 없는 항목에만 쓴다.
+func Analyze(src io.ReaderAt, size int64, sectionInputs []SectionInput, opts Options) (*Result, error) {
+	log := logging.Use(opts.Logger)
+	skipPages := opts.SkipPages
 
+	r, err := pdf.NewReader(src, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	totalPages := r.NumPage()
+	log.Infof("PDF has %d pages", totalPages)
+
+	var sections []SectionPage
+	if len(sectionInputs) > 0 {
 		for _, input := range sectionInputs {
 			sections = append(sections, SectionPage{
 				ID:    input.ID,
@@ -82,28 +110,58 @@ func AnalyzePDF(pdfPath, sectionsJSONPath string, skipPages int) (*Result, error
 				})
 			}
 		}
-		fmt.Fprintf(os.Stderr, "[INFO] Loaded %d sections (including subheadings)\n", len(sections))
+		log.Infof("Loaded %d sections (including subheadings)", len(sections))
 	}
 
+	dests := namedDestinations(r)
+	log.Infof("Found %d named destinations", len(dests))
+
 	// Detect or use provided skip pages
 	var actualSkipPages int
 	if skipPages <= 0 {
-		fmt.Fprintf(os.Stderr, "[INFO] Auto-detecting TOC end page...\n")
-		detectedSkip := detectTocEndPage(sections, r)
+		log.Infof("Auto-detecting TOC end page...")
+		detectedSkip := 0
+		if len(sections) > 0 {
+			if physical, ok := dests[sections[0].ID]; ok {
+				// 본문 쪽 번호는 첫 섹션의 앵커가 있는 쪽에서 시작한다
+				detectedSkip = physical - 1
+				log.Tagf(logging.Info, "AUTO-DETECT", "First section '%s' anchored on page %d (pages to skip: %d)",
+					sections[0].Title, physical, detectedSkip)
+			}
+		}
+		if detectedSkip <= 0 {
+			detectedSkip = detectTocEndPage(sections, r, log)
+		}
 		if detectedSkip > 0 {
 			actualSkipPages = detectedSkip
 		} else {
 			actualSkipPages = 3
-			fmt.Fprintf(os.Stderr, "[INFO] Using default skip pages: %d\n", actualSkipPages)
+			log.Infof("Using default skip pages: %d", actualSkipPages)
 		}
 	} else {
 		actualSkipPages = skipPages
-		fmt.Fprintf(os.Stderr, "[INFO] Using manual skip pages: %d\n", actualSkipPages)
+		log.Infof("Using manual skip pages: %d", actualSkipPages)
 	}
 
-	// Search from body pages
+	// Named Destination(앵커)으로 쪽 번호를 먼저 결정
+	for i := range sections {
+		physical, ok := dests[sections[i].ID]
+		if !ok || physical <= actualSkipPages {
+			continue
+		}
+		sections[i].Page = physical - actualSkipPages
+		sections[i].Method = MethodDestination
+		log.Tagf(logging.Debug, "FOUND", "'%s' on page %d (physical: %d, anchor: #%s)",
+			sections[i].Title, sections[i].Page, physical, sections[i].ID)
+	}
+
+	// 폴백: 앵커가 없는 항목만 본문 쪽에서 제목 텍스트로 검색
 	startPage := actualSkipPages + 1
-	fmt.Fprintf(os.Stderr, "[INFO] Searching from page %d (skipping %d pages)\n", startPage, actualSkipPages)
+	if pending := countUnresolved(sections); pending > 0 {
+		log.Infof("Searching %d unresolved titles from page %d (skipping %d pages)", pending, startPage, actualSkipPages)
+	} else {
+		startPage = totalPages + 1
+	}
 
 	for pageNum := startPage; pageNum <= totalPages; pageNum++ {
 		page := r.Page(pageNum)
@@ -121,15 +179,24 @@ func AnalyzePDF(pdfPath, sectionsJSONPath string, skipPages int) (*Result, error
 				if containsTitle(text, sections[i].Title) {
 					docPageNum := pageNum - actualSkipPages
 					sections[i].Page = docPageNum
-					fmt.Fprintf(os.Stderr, "[FOUND] '%s' on page %d (physical: %d, skipped: %d)\n",
+					sections[i].Method = MethodText
+					log.Tagf(logging.Debug, "FOUND", "'%s' on page %d by text search (physical: %d, skipped: %d)",
 						sections[i].Title, docPageNum, pageNum, actualSkipPages)
 				}
 			}
 		}
 	}
 
+	for i := range sections {
+		if sections[i].Method == "" {
+			sections[i].Method = MethodUnresolved
+			log.Warnf("Could not resolve page for '%s' (#%s)", sections[i].Title, sections[i].ID)
+		}
+	}
+
 	result := &Result{
 		TotalPages: totalPages,
+		SkipPages:  actualSkipPages,
 		Sections:   sections,
 	}
 	return result, nil
@@ -144,11 +211,21 @@ func SaveResult(result *Result, outputPath string) error {
 	if err := os.WriteFile(outputPath, jsonOutput, 0644); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
-	fmt.Fprintf(os.Stderr, "[SUCCESS] Analysis saved to %s\n", outputPath)
+	logging.Use(nil).Successf("Analysis saved to %s", outputPath)
 	return nil
 }
 
-func detectTocEndPage(sections []SectionPage, r *pdf.Reader) int {
+func countUnresolved(sections []SectionPage) int {
+	n := 0
+	for _, sec := range sections {
+		if sec.Page == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func detectTocEndPage(sections []SectionPage, r *pdf.Reader, log logging.Printer) int {
 	if len(sections) == 0 {
 		return 0
 	}
@@ -173,15 +250,15 @@ func detectTocEndPage(sections []SectionPage, r *pdf.Reader) int {
 
 		if isBodyPage(text, sections) {
 			tocEndPage := pageNum - 1
-			fmt.Fprintf(os.Stderr, "[AUTO-DETECT] Content starts at page %d (first section: '%s')\n", pageNum, firstSectionTitle)
-			fmt.Fprintf(os.Stderr, "[AUTO-DETECT] TOC ends at page %d (pages to skip: %d)\n", tocEndPage, tocEndPage)
+			log.Tagf(logging.Info, "AUTO-DETECT", "Content starts at page %d (first section: '%s')", pageNum, firstSectionTitle)
+			log.Tagf(logging.Info, "AUTO-DETECT", "TOC ends at page %d (pages to skip: %d)", tocEndPage, tocEndPage)
 			return tocEndPage
 		}
 
-		fmt.Fprintf(os.Stderr, "[AUTO-DETECT] Page %d appears to be TOC (contains '%s' but no body text)\n", pageNum, firstSectionTitle)
+		log.Tagf(logging.Debug, "AUTO-DETECT", "Page %d appears to be TOC (contains '%s' but no body text)", pageNum, firstSectionTitle)
 	}
 
-	fmt.Fprintf(os.Stderr, "[WARN] Could not detect TOC end page (content start not found)\n")
+	log.Warnf("Could not detect TOC end page (content start not found)")
 	return 0
 }
 
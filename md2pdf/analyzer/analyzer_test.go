@@ -0,0 +1,122 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"md2pdf/analyzer"
+	"md2pdf/logging"
+)
+
+// buildPDF는 pages의 텍스트를 한 쪽씩 담은 PDF를 만든다. catalog는 카탈로그에
+// 덧붙일 항목이고, extra는 그 뒤에 이어지는 객체(번호 2*len(pages)+4부터)다.
+// 쪽 객체 번호는 4, 6, 8, ... 이다.
+func buildPDF(pages []string, catalog string, extra ...string) []byte {
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R " + catalog + " >>",
+		"", // 쪽 트리, 아래에서 채움
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	var kids []string
+	for _, text := range pages {
+		num := len(objs) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", num))
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 700 Td (%s) Tj ET", text)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", num+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+	objs[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	objs = append(objs, extra...)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+// 표지, 목차, 소개, 사용법 네 쪽. 목차 쪽에도 제목이 나오므로 텍스트 검색만으로는
+// 목차 쪽을 본문으로 오인할 수 있다.
+var testPages = []string{"Cover", "Contents: Intro .... 1 Usage .... 2", "Intro", "Usage"}
+
+var testSections = []analyzer.SectionInput{
+	{ID: "intro", Title: "Intro", Level: 1},
+	{ID: "usage", Title: "Usage", Level: 1, SubHeadings: []analyzer.SubHeading{{ID: "usage-cli", Title: "CLI", Level: 2}}},
+}
+
+func TestAnalyze(t *testing.T) {
+	// 쪽 객체: 4(표지), 6(목차), 8(소개), 10(사용법); 추가 객체는 12번부터
+	tests := []struct {
+		name      string
+		pdf       []byte
+		skip      int
+		wantSkip  int
+		wantPages []int
+		wantMeth  []string
+	}{
+		{
+			name:      "catalog /Dests",
+			pdf:       buildPDF(testPages, "/Dests 12 0 R", "<< /intro [8 0 R /XYZ 0 792 0] /usage [10 0 R /XYZ 0 792 0] /usage-cli << /D [10 0 R /Fit] >> >>"),
+			wantSkip:  2,
+			wantPages: []int{1, 2, 2},
+			wantMeth:  []string{analyzer.MethodDestination, analyzer.MethodDestination, analyzer.MethodDestination},
+		},
+		{
+			name: "/Names name tree",
+			pdf: buildPDF(testPages, "/Names << /Dests 12 0 R >>",
+				"<< /Kids [13 0 R 14 0 R] >>",
+				"<< /Limits [(intro) (intro)] /Names [(intro) [8 0 R /XYZ 0 792 0]] >>",
+				"<< /Limits [(usage) (usage-cli)] /Names [(usage) [10 0 R /XYZ 0 792 0] (usage-cli) [10 0 R /Fit]] >>"),
+			wantSkip:  2,
+			wantPages: []int{1, 2, 2},
+			wantMeth:  []string{analyzer.MethodDestination, analyzer.MethodDestination, analyzer.MethodDestination},
+		},
+		{
+			name:      "text fallback for entries without a destination",
+			pdf:       buildPDF(testPages, "/Dests 12 0 R", "<< /intro [8 0 R /XYZ 0 792 0] >>"),
+			wantSkip:  2,
+			wantPages: []int{1, 2, 0},
+			wantMeth:  []string{analyzer.MethodDestination, analyzer.MethodText, analyzer.MethodUnresolved},
+		},
+		{
+			name:      "text only with manual skip",
+			pdf:       buildPDF(testPages, ""),
+			skip:      2,
+			wantSkip:  2,
+			wantPages: []int{1, 2, 0},
+			wantMeth:  []string{analyzer.MethodText, analyzer.MethodText, analyzer.MethodUnresolved},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := analyzer.Analyze(bytes.NewReader(tt.pdf), int64(len(tt.pdf)), testSections,
+				analyzer.Options{SkipPages: tt.skip, Logger: logging.Discard})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.TotalPages != 4 || result.SkipPages != tt.wantSkip {
+				t.Errorf("pages = %d, skipped = %d, want 4, %d", result.TotalPages, result.SkipPages, tt.wantSkip)
+			}
+			if len(result.Sections) != len(tt.wantPages) {
+				t.Fatalf("got %d sections, want %d", len(result.Sections), len(tt.wantPages))
+			}
+			for i, s := range result.Sections {
+				if s.Page != tt.wantPages[i] || s.Method != tt.wantMeth[i] {
+					t.Errorf("%s: page %d (%s), want %d (%s)", s.ID, s.Page, s.Method, tt.wantPages[i], tt.wantMeth[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"github.com/ledongthuc/pdf"
+)
+
+// SectionPage.Method에 기록하는 쪽 번호 결정 방법
+const (
+	MethodDestination = "destination" // PDF의 Named Destination(앵커)
+	MethodText        = "text"        // 폴백: 제목 텍스트 검색
+	MethodUnresolved  = "unresolved"  // 찾지 못함
+)
+
+// namedDestinations는 PDF의 모든 Named Destination을 물리 쪽 번호(1부터)에
+// 매핑한다. Chrome은 내부 링크(목차 항목 등)의 대상이 되는 요소 id마다
+// Named Destination을 하나씩 기록하므로, 목차에서 링크한 Section.ID와
+// SubHeading.ID는 모두 들어 있다.
+func namedDestinations(r *pdf.Reader) map[string]int {
+	pageIndex := make(map[string]int)
+	collectPages(r.Trailer().Key("Root").Key("Pages"), pageIndex)
+
+	dests := make(map[string]int)
+	root := r.Trailer().Key("Root")
+
+	// PDF 1.1 방식: 카탈로그의 /Dests 딕셔너리 (Chrome/Skia가 사용)
+	oldDests := root.Key("Dests")
+	for _, key := range oldDests.Keys() {
+		if page := destPage(oldDests.Key(key), pageIndex); page > 0 {
+			dests[key] = page
+		}
+	}
+
+	// PDF 1.2 방식: /Names /Dests 이름 트리
+	walkNameTree(root.Key("Names").Key("Dests"), func(key string, v pdf.Value) {
+		if page := destPage(v, pageIndex); page > 0 {
+			dests[key] = page
+		}
+	}, 0)
+
+	return dests
+}
+
+// collectPages는 쪽 트리를 순회하며 각 쪽 딕셔너리의 직렬화 문자열과 물리 쪽
+// 번호를 기록한다. 쪽 딕셔너리는 적어도 /Contents 참조가 서로 다르므로
+// 직렬화 문자열이 쪽마다 고유하다.
+func collectPages(node pdf.Value, index map[string]int) {
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		kid := kids.Index(i)
+		switch kid.Key("Type").Name() {
+		case "Pages":
+			collectPages(kid, index)
+		case "Page":
+			index[kid.String()] = len(index) + 1
+		}
+	}
+}
+
+// destPage는 명시적 Destination 배열([page /XYZ left top zoom]) 또는 이를
+// /D로 감싼 딕셔너리가 가리키는 물리 쪽 번호를 반환한다.
+func destPage(dest pdf.Value, pageIndex map[string]int) int {
+	if dest.Kind() == pdf.Dict {
+		dest = dest.Key("D")
+	}
+	if dest.Kind() != pdf.Array || dest.Len() == 0 {
+		return 0
+	}
+	target := dest.Index(0)
+	if target.Kind() == pdf.Integer {
+		// 원격 방식 Destination: 0부터 시작하는 쪽 인덱스
+		return int(target.Int64()) + 1
+	}
+	return pageIndex[target.String()]
+}
+
+// walkNameTree는 PDF 이름 트리의 모든 키/값 쌍을 방문한다.
+func walkNameTree(node pdf.Value, visit func(key string, v pdf.Value), depth int) {
+	if node.IsNull() || depth > 32 {
+		return
+	}
+	names := node.Key("Names")
+	for i := 0; i+1 < names.Len(); i += 2 {
+		visit(names.Index(i).RawString(), names.Index(i+1))
+	}
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		walkNameTree(kids.Index(i), visit, depth+1)
+	}
+}
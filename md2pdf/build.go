@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"md2pdf/logging"
+	"md2pdf/pipeline"
+)
+
+// runBuild는 `md2pdf build`(와 기존 플래그 전용 형식)를 구현한다:
+// 목차 쪽 번호를 넣는 2-Pass PDF 생성
+func runBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	doc := addDocFlags(fs, "Output PDF file path (required)")
+
+	// 출력 모드 (`md2pdf html`의 기존 별칭)
+	htmlOnly := fs.Bool("html-only", false, "Generate HTML only (same as 'md2pdf html')")
+
+	// PDF 옵션
+	skipPages := fs.Int("skip", 0, "Number of pages to skip for TOC analysis (0 = auto-detect)")
+	// offset은 호환을 위해 받기만 하고 내부에서는 skip을 사용
+	_ = fs.Int("offset", 0, "Page number offset (for compatibility)")
+
+	// 렌더링
+	readyTimeout := fs.Int("ready-timeout", 30, "Max seconds to wait for fonts, images and diagrams before printing")
+
+	// 문서 개요 (PDF 북마크)
+	outlineDepth := fs.Int("outline-depth", 2, "Bookmark depth: 1=sections, 2=+H2, 3=+H3, 4=+H4 (0 = no bookmarks; up to -toc-depth)")
+	outlineCover := fs.Bool("outline-cover", false, "Add a bookmark for the cover page")
+	outlineTOC := fs.Bool("outline-toc", false, "Add a bookmark for the table of contents")
+
+	// 기존 플래그 전용 형식(md2pdf_v2.sh)에서 -v는 예전처럼 버전 출력 후 종료
+	// (상세 출력은 -verbose). 서브커맨드 형식에서는 -v가 상세 출력이다.
+	showVersion := new(bool)
+	if legacyInvocation {
+		fs.BoolVar(showVersion, "v", false, "Show version (legacy form; use -verbose for debug output)")
+	}
+	logs := addLogFlags(fs, os.Stdout)
+
+	fs.Usage = commandUsage(fs, "2-Pass PDF generation with accurate TOC page numbers",
+		"md2pdf build -i <input_dir> -o <output.pdf> [options]")
+	_ = fs.Parse(args)
+
+	if *showVersion {
+		printVersion()
+		return
+	}
+	if *doc.inputDir == "" || *doc.outputFile == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	logs.setup()
+
+	if *htmlOnly {
+		generateHTMLOnly(doc, logs, "", false)
+		return
+	}
+
+	outputFile := *doc.outputFile
+	if !strings.HasSuffix(strings.ToLower(outputFile), ".pdf") {
+		outputFile += ".pdf"
+	}
+
+	logs.banner(" md2pdf - 2-Pass PDF Generation with Accurate TOC Page Numbers")
+	if logs.console() {
+		fmt.Println()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var pdf bytes.Buffer
+	_, err := pipeline.Build(ctx, pipeline.Options{
+		Document:     doc.converterOptions(),
+		PDF:          &pdf,
+		SkipPages:    *skipPages,
+		ReadyTimeout: *readyTimeout,
+		Outline: pipeline.OutlineOptions{
+			Depth: *outlineDepth,
+			Cover: *outlineCover,
+			TOC:   *outlineTOC,
+		},
+		Producer: fmt.Sprintf("md2pdf v%s (Chrome/Skia)", BuildVersion),
+	})
+	if err != nil {
+		logs.fail("%v", err)
+	}
+
+	if dir := filepath.Dir(outputFile); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			logs.fail("Failed to create output directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(outputFile, pdf.Bytes(), 0644); err != nil {
+		logs.fail("Failed to write PDF: %v", err)
+	}
+
+	// ======================================================================
+	// SUCCESS
+	// ======================================================================
+	if logs.console() {
+		fmt.Println()
+	}
+	summary := fmt.Sprintf("[SUCCESS] PDF generated: %s (%.1f MB)", outputFile, float64(pdf.Len())/(1024*1024))
+	if n := logs.counter.Warnings(); n > 0 {
+		summary += fmt.Sprintf(", %d warnings", n)
+	}
+	logs.banner(summary)
+	if !logs.console() {
+		logging.Use(nil).Successf("PDF generated: %s", outputFile)
+	}
+	logs.exit()
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"md2pdf/analyzer"
+	"md2pdf/converter"
+	"md2pdf/logging"
+	"md2pdf/pipeline"
+	"md2pdf/renderer"
+)
+
+// runHTML은 `md2pdf html`을 구현한다: PDF 변환 없이 HTML만 생성
+func runHTML(args []string) {
+	fs := flag.NewFlagSet("html", flag.ExitOnError)
+	doc := addDocFlags(fs, "Output HTML file path (required)")
+	sectionsJSON := fs.String("sections", "", "Write sections JSON for 'md2pdf analyze' to this path")
+	pdfMode := fs.Bool("pdf-mode", false, "Rewrite internal .md links to in-document anchors (for 'md2pdf render')")
+	logs := addLogFlags(fs, os.Stdout)
+	fs.Usage = commandUsage(fs, "HTML generation", "md2pdf html -i <input_dir> -o <output.html> [options]")
+	_ = fs.Parse(args)
+
+	if *doc.inputDir == "" || *doc.outputFile == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	logs.setup()
+
+	generateHTMLOnly(doc, logs, *sectionsJSON, *pdfMode)
+}
+
+// generateHTMLOnly는 변환기만 실행한다 (html 명령과 -html-only).
+func generateHTMLOnly(doc *docFlags, logs *logFlags, sectionsJSON string, pdfMode bool) {
+	opts := doc.converterOptions()
+	opts.SectionsJSON = sectionsJSON
+	opts.PDFMode = pdfMode
+	if !strings.HasSuffix(strings.ToLower(opts.OutputFile), ".html") {
+		opts.OutputFile = strings.TrimSuffix(opts.OutputFile, filepath.Ext(opts.OutputFile)) + ".html"
+	}
+
+	logs.banner(" md2pdf - HTML Generation (html-only mode)")
+	if logs.console() {
+		fmt.Println()
+	}
+
+	// Mermaid 다이어그램은 필요할 때만 띄우는 Chrome에서 미리 렌더링
+	diagrams := pipeline.NewDiagrams(context.Background(), nil, nil)
+	opts.Diagrams = diagrams
+
+	end := logging.Use(nil).Phase("html")
+	_, err := converter.ConvertToHTML(opts)
+	diagrams.Close()
+	if err != nil {
+		logs.fail("HTML generation failed: %v", err)
+	}
+	end()
+
+	if logs.console() {
+		fmt.Println()
+	}
+	logs.banner(fmt.Sprintf("[SUCCESS] HTML generated: %s", opts.OutputFile))
+	logs.exit()
+}
+
+// runRender는 `md2pdf render <html>`을 구현한다: HTML 하나를 PDF로 변환
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	inputHTML := fs.String("i", "", "Input HTML file path (or first argument)")
+	outputPDF := fs.String("o", "", "Output PDF file path (default: input with .pdf)")
+	landscape := fs.Bool("landscape", false, "Landscape orientation")
+	scale := fs.Float64("scale", 1.0, "Rendering scale")
+	timeout := fs.Int("timeout", 300, "Overall timeout in seconds")
+	readyTimeout := fs.Int("ready-timeout", 30, "Max seconds to wait for fonts, images and diagrams before printing")
+	offline := fs.Bool("offline", false, "Fail if any network request is attempted")
+	logs := addLogFlags(fs, os.Stdout)
+	fs.Usage = commandUsage(fs, "HTML to PDF conversion", "md2pdf render <input.html> [-o output.pdf] [options]")
+
+	positional := parseArgs(fs, args)
+	if *inputHTML == "" && len(positional) > 0 {
+		*inputHTML = positional[0]
+	}
+	if *inputHTML == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	logs.setup()
+
+	end := logging.Use(nil).Phase("render")
+	err := renderer.RenderToPDF(*inputHTML, *outputPDF, renderer.Options{
+		Landscape:    *landscape,
+		Scale:        *scale,
+		Timeout:      *timeout,
+		ReadyTimeout: *readyTimeout,
+		Offline:      *offline,
+	})
+	if err != nil {
+		logs.fail("PDF generation failed: %v", err)
+	}
+	end()
+	logs.exit()
+}
+
+// analyzeFlags는 analyze와 verify가 함께 쓰는 플래그
+type analyzeFlags struct {
+	pdfPath      *string
+	sectionsJSON *string
+	skipPages    *int
+	logs         *logFlags
+}
+
+// addAnalyzeFlags는 analyzer 플래그를 등록한다. 진행 상황은 stderr에 기록해
+// stdout에는 보고서만 남는다.
+func addAnalyzeFlags(fs *flag.FlagSet) *analyzeFlags {
+	return &analyzeFlags{
+		pdfPath:      fs.String("i", "", "Input PDF file path (or first argument)"),
+		sectionsJSON: fs.String("sections", "", "Sections JSON from 'md2pdf html -sections' (required)"),
+		skipPages:    fs.Int("skip", 0, "Number of pages to skip (0 = auto-detect)"),
+		logs:         addLogFlags(fs, os.Stderr),
+	}
+}
+
+// analyze는 인자를 해석하고 분석기를 실행한다.
+func (a *analyzeFlags) analyze(fs *flag.FlagSet, args []string) *analyzer.Result {
+	positional := parseArgs(fs, args)
+	if *a.pdfPath == "" && len(positional) > 0 {
+		*a.pdfPath = positional[0]
+	}
+	if *a.pdfPath == "" || *a.sectionsJSON == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	a.logs.setup()
+
+	result, err := analyzer.AnalyzePDF(*a.pdfPath, *a.sectionsJSON, *a.skipPages)
+	if err != nil {
+		a.logs.fail("PDF analysis failed: %v", err)
+	}
+	return result
+}
+
+// runAnalyze는 `md2pdf analyze <pdf> --sections <json>`을 구현한다
+// (예전의 독립 실행형 pdf_analyzer).
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	a := addAnalyzeFlags(fs)
+	outputJSON := fs.String("o", "", "Output JSON file (default: stdout)")
+	fs.Usage = commandUsage(fs, "Section page number analysis", "md2pdf analyze <input.pdf> --sections <sections.json> [-o pages.json]")
+
+	result := a.analyze(fs, args)
+
+	if *outputJSON != "" {
+		if err := analyzer.SaveResult(result, *outputJSON); err != nil {
+			a.logs.fail("%v", err)
+		}
+	} else {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			a.logs.fail("%v", err)
+		}
+		fmt.Println(string(data))
+	}
+	a.logs.exit()
+}
+
+// runVerify는 `md2pdf verify <pdf> --sections <json>`을 구현한다: 모든 목차
+// 항목이 Named Destination으로 해석되어야 하고, 기대 쪽 번호 JSON을 주면
+// 쪽 번호도 일치해야 한다.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	a := addAnalyzeFlags(fs)
+	pagesJSON := fs.String("pages", "", "Expected pages JSON (e.g. from 'md2pdf analyze') to compare against")
+	fs.Usage = commandUsage(fs, "TOC anchor and page number check", "md2pdf verify <input.pdf> --sections <sections.json> [--pages pages.json]")
+
+	result := a.analyze(fs, args)
+	log := logging.Use(nil)
+
+	expected := make(map[string]int)
+	if *pagesJSON != "" {
+		data, err := os.ReadFile(*pagesJSON)
+		if err != nil {
+			a.logs.fail("Could not read pages JSON: %v", err)
+		}
+		var pages analyzer.Result
+		if err := json.Unmarshal(data, &pages); err != nil {
+			a.logs.fail("Could not parse pages JSON: %v", err)
+		}
+		for _, p := range pages.Sections {
+			expected[p.ID] = p.Page
+		}
+	}
+
+	problems := 0
+	for _, sec := range result.Sections {
+		switch sec.Method {
+		case analyzer.MethodUnresolved:
+			log.Errorf("'%s' (#%s): page not found", sec.Title, sec.ID)
+			problems++
+			continue
+		case analyzer.MethodText:
+			log.Warnf("'%s' (#%s): no anchor, resolved by text search", sec.Title, sec.ID)
+		}
+		if want, ok := expected[sec.ID]; ok && want != sec.Page {
+			log.Errorf("'%s' (#%s): page %d, expected %d", sec.Title, sec.ID, sec.Page, want)
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		a.logs.fail("%d of %d entries failed verification", problems, len(result.Sections))
+	}
+	log.Successf("All %d entries verified", len(result.Sections))
+	a.logs.exit()
+}
+
+// runTemplates는 `md2pdf templates list`를 구현한다.
+func runTemplates(args []string) {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "Usage: md2pdf templates list\n")
+		os.Exit(1)
+	}
+	for _, name := range converter.Templates() {
+		fmt.Println(name)
+	}
+}
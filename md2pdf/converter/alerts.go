@@ -0,0 +1,515 @@
+package converter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"md2pdf/logging"
+)
+
+// alertExtension은 다음 형식의 알림(admonition)을 알림 상자로 렌더링한다:
+//
+//	> [!NOTE]            GitHub / Obsidian (종류 표시가 있는 인용문,
+//	> [!faq]- Title      +/-는 접기 가능, 표시 뒤의 텍스트는 제목)
+//	:::tip[Title] ... ::: Docusaurus (더 긴 펜스로 중첩: ::::)
+//	!> text / ?> text    Docsify (IMPORTANT / TIP, 빈 줄까지)
+//
+// 첫 문단 앞의 "**제목**: 본문"은 제목이 된다. 접을 수 있는 알림은 HTML에서
+// <details>이고 PDF 모드에서는 항상 펼친다.
+type alertExtension struct {
+	pdf   bool
+	types map[string]alertType // newAlertTypes 참고
+}
+
+func (e *alertExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(&docusaurusAlertParser{types: e.types}, 90),
+			util.Prioritized(&docsifyAlertParser{}, 90),
+		),
+		parser.WithASTTransformers(util.Prioritized(&alertTransformer{types: e.types}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&alertRenderer{pdf: e.pdf, types: e.types}, 500)))
+}
+
+type alertType struct {
+	class, icon string
+	label       string // 기본 제목 (GitHub 타입은 없음)
+	color       string // 사용자 정의 타입의 CSS 색
+}
+
+var alertTypes = map[string]alertType{
+	"NOTE":      {"alert-note", "fa-info-circle", "", ""},
+	"TIP":       {"alert-tip", "fa-lightbulb", "", ""},
+	"IMPORTANT": {"alert-important", "fa-exclamation-circle", "", ""},
+	"WARNING":   {"alert-warning", "fa-triangle-exclamation", "", ""},
+	"CAUTION":   {"alert-caution", "fa-radiation", "", ""},
+
+	// Obsidian 타입
+	"ABSTRACT": {"alert-abstract", "fa-clipboard-list", "Abstract", ""},
+	"INFO":     {"alert-info", "fa-circle-info", "Info", ""},
+	"TODO":     {"alert-todo", "fa-circle-check", "Todo", ""},
+	"SUCCESS":  {"alert-success", "fa-check", "Success", ""},
+	"QUESTION": {"alert-question", "fa-circle-question", "Question", ""},
+	"FAILURE":  {"alert-failure", "fa-xmark", "Failure", ""},
+	"DANGER":   {"alert-danger", "fa-bolt", "Danger", ""},
+	"BUG":      {"alert-bug", "fa-bug", "Bug", ""},
+	"EXAMPLE":  {"alert-example", "fa-list", "Example", ""},
+	"QUOTE":    {"alert-quote", "fa-quote-left", "Quote", ""},
+}
+
+// Obsidian 타입 별칭
+var alertAliases = map[string]string{
+	"SUMMARY": "ABSTRACT", "TLDR": "ABSTRACT", "HINT": "TIP", "CHECK": "SUCCESS",
+	"DONE": "SUCCESS", "HELP": "QUESTION", "FAQ": "QUESTION", "ATTENTION": "WARNING",
+	"FAIL": "FAILURE", "MISSING": "FAILURE", "ERROR": "DANGER", "CITE": "QUOTE",
+}
+
+// Docusaurus 알림 종류
+var docusaurusAlertTypes = map[string]string{
+	"note": "NOTE", "tip": "TIP", "info": "NOTE",
+	"warning": "WARNING", "danger": "CAUTION", "caution": "CAUTION",
+}
+
+var reAlertTypeName = regexp.MustCompile(`^[A-Za-z][\w-]*$`)
+
+// newAlertTypes는 기본 알림 타입에 설정 파일의 사용자 정의 콜아웃 타입을
+// 더해 반환한다. 기본 타입과 이름이 같은 설정 항목은 기본 타입을 덮어쓴다.
+func newAlertTypes(cfg AuthorsConfig, log logging.Printer) map[string]alertType {
+	types := make(map[string]alertType, len(alertTypes)+len(cfg.Callouts))
+	for k, t := range alertTypes {
+		types[k] = t
+	}
+	for name, c := range cfg.Callouts {
+		if !reAlertTypeName.MatchString(name) {
+			log.Warnf("Invalid callout type name %q", name)
+			continue
+		}
+		key := strings.ToUpper(name)
+		t, ok := types[key]
+		if !ok {
+			t = alertType{class: "alert-custom alert-" + strings.ToLower(name), icon: "fa-circle-info", label: capitalize(name)}
+		}
+		if c.Icon != "" {
+			t.icon = c.Icon
+		}
+		if c.Label != "" {
+			t.label = c.Label
+		}
+		if c.Color != "" {
+			if !strings.Contains(t.class, "alert-custom") {
+				t.class += " alert-custom"
+			}
+			t.color = c.Color
+		}
+		types[key] = t
+	}
+	return types
+}
+
+// lookupAlertType은 타입 이름이나 별칭에 해당하는 types의 키를 반환한다.
+func lookupAlertType(types map[string]alertType, name string) (string, bool) {
+	key := strings.ToUpper(name)
+	if _, ok := types[key]; ok {
+		return key, true
+	}
+	key, ok := alertAliases[key]
+	return key, ok
+}
+
+// capitalize는 s의 첫 글자를 대문자로, 나머지를 소문자로 바꿔 반환한다.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
+
+var (
+	kindAlert      = ast.NewNodeKind("Alert")
+	kindAlertTitle = ast.NewNodeKind("AlertTitle")
+)
+
+// alert는 알림 하나. 자식은 본문 블록이고, 앞에 alertTitle이 올 수 있다.
+type alert struct {
+	ast.BaseBlock
+	typ    string // 알림 타입의 키
+	fence  int    // Docusaurus: 여는 펜스의 콜론 수
+	titled bool   // alertTitle이 있음
+	fold   byte   // 접는 콜아웃이면 '+'(펼침) 또는 '-'(접힘)
+}
+
+func (n *alert) Kind() ast.NodeKind { return kindAlert }
+
+func (n *alert) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.typ}, nil)
+}
+
+// alertTitle은 알림 제목의 인라인을 담는다.
+type alertTitle struct {
+	ast.BaseBlock
+}
+
+func (n *alertTitle) Kind() ast.NodeKind { return kindAlertTitle }
+
+func (n *alertTitle) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var reGFMAlertMarker = regexp.MustCompile(`^\s*\[!([A-Za-z][\w-]*)\]([+-]?)[ \t]*`)
+
+// alertTransformer는 [!TYPE]으로 시작하는 인용문을 알림으로 바꾸고 제목을
+// 추출한다.
+type alertTransformer struct {
+	types map[string]alertType
+}
+
+func (t *alertTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var quotes []*ast.Blockquote
+	var alerts []*alert
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Blockquote:
+			quotes = append(quotes, n)
+		case *alert:
+			alerts = append(alerts, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, q := range quotes {
+		para, ok := q.FirstChild().(*ast.Paragraph)
+		if !ok || para.Lines().Len() == 0 {
+			continue
+		}
+		first := para.Lines().At(0)
+		m := reGFMAlertMarker.FindSubmatchIndex(first.Value(source))
+		if m == nil {
+			continue
+		}
+		typ, ok := lookupAlertType(t.types, string(first.Value(source)[m[2]:m[3]]))
+		if !ok {
+			continue
+		}
+
+		// 종류 표시에 해당하는 인라인 제거
+		markerEnd := first.Start + m[1]
+		for c := para.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			txt, ok := c.(*ast.Text)
+			if !ok || txt.Segment.Start >= markerEnd {
+				break
+			}
+			if txt.Segment.Stop <= markerEnd {
+				para.RemoveChild(para, c)
+			} else {
+				txt.Segment = txt.Segment.WithStart(markerEnd)
+			}
+			c = next
+		}
+
+		node := &alert{typ: typ}
+		if m[5] > m[4] {
+			node.fold = first.Value(source)[m[4]]
+		}
+		node.SetBlankPreviousLines(q.HasBlankPreviousLines())
+		for c := q.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			node.AppendChild(node, c)
+			c = next
+		}
+		q.Parent().ReplaceChild(q.Parent(), q, node)
+
+		if para.ChildCount() == 0 {
+			node.RemoveChild(node, para)
+		} else if extractAlertTitle(node, source); !node.titled {
+			extractFirstLineTitle(node, para, first.Stop)
+		}
+		alerts = append(alerts, node)
+	}
+
+	for _, node := range alerts {
+		if !node.titled {
+			extractAlertTitle(node, source)
+		}
+		if !node.titled {
+			label := t.types[node.typ].label
+			if label == "" && node.fold != 0 {
+				label = capitalize(node.typ)
+			}
+			if label != "" {
+				title := &alertTitle{}
+				title.AppendChild(title, ast.NewString([]byte(label)))
+				node.InsertBefore(node, node.FirstChild(), title)
+				node.titled = true
+			}
+		}
+	}
+}
+
+// extractFirstLineTitle은 첫 줄(stop이 줄 끝)의 표시 뒤 인라인을
+// alertTitle로 옮긴다. 그 인라인이 내용 전부이면 옮기지 않는다:
+// "> [!IMPORTANT] text"는 본문으로 남는다.
+func extractFirstLineTitle(node *alert, para *ast.Paragraph, stop int) {
+	if start := inlineStart(para.FirstChild()); start < 0 || start >= stop {
+		return
+	}
+	end := ast.Node(nil) // 첫 줄의 마지막 인라인
+	for c := para.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok && (t.SoftLineBreak() || t.HardLineBreak()) {
+			end = c
+			break
+		}
+	}
+	if end == nil && para.NextSibling() == nil {
+		return
+	}
+
+	title := &alertTitle{}
+	for c := para.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		title.AppendChild(title, c)
+		if c == end {
+			c.(*ast.Text).SetSoftLineBreak(false)
+			c.(*ast.Text).SetHardLineBreak(false)
+			break
+		}
+		c = next
+	}
+	node.InsertBefore(node, node.FirstChild(), title)
+	node.titled = true
+	if para.ChildCount() == 0 {
+		node.RemoveChild(node, para)
+	}
+}
+
+// inlineStart는 인라인 안 첫 텍스트의 원본 위치를 반환한다. 없으면 -1.
+func inlineStart(n ast.Node) int {
+	start := -1
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			start = t.Segment.Start
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return start
+}
+
+// extractAlertTitle은 첫 문단 앞의 "**제목**:"을 alertTitle로 옮긴다.
+func extractAlertTitle(node *alert, source []byte) {
+	para, ok := node.FirstChild().(*ast.Paragraph)
+	if !ok {
+		return
+	}
+	strong, ok := para.FirstChild().(*ast.Emphasis)
+	if !ok || strong.Level != 2 {
+		return
+	}
+	rest, ok := strong.NextSibling().(*ast.Text)
+	if !ok {
+		return
+	}
+	trimmed := bytes.TrimLeft(rest.Segment.Value(source), " \t")
+	if len(trimmed) == 0 || trimmed[0] != ':' {
+		return
+	}
+	after := bytes.TrimLeft(trimmed[1:], " \t")
+	rest.Segment = rest.Segment.WithStart(rest.Segment.Stop - len(after))
+	if rest.Segment.Len() == 0 && !rest.SoftLineBreak() && !rest.HardLineBreak() {
+		para.RemoveChild(para, rest)
+	}
+
+	title := &alertTitle{}
+	for c := strong.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		title.AppendChild(title, c)
+		c = next
+	}
+	para.RemoveChild(para, strong)
+	node.InsertBefore(node, para, title)
+	node.titled = true
+	if para.ChildCount() == 0 {
+		node.RemoveChild(node, para)
+	} else {
+		para.SetAttributeString("class", []byte("alert-body"))
+	}
+}
+
+type docusaurusAlertParser struct {
+	types map[string]alertType
+}
+
+func (b *docusaurusAlertParser) Trigger() []byte { return []byte{':'} }
+
+func (b *docusaurusAlertParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+	rest := line[pos:]
+	colons := 0
+	for colons < len(rest) && rest[colons] == ':' {
+		colons++
+	}
+	if colons < 3 {
+		return nil, parser.NoChildren
+	}
+	rest = rest[colons:]
+	name := 0
+	for name < len(rest) && (rest[name] >= 'a' && rest[name] <= 'z' || rest[name] >= 'A' && rest[name] <= 'Z') {
+		name++
+	}
+	typ, ok := docusaurusAlertTypes[strings.ToLower(string(rest[:name]))]
+	if !ok && name > 0 {
+		typ, ok = lookupAlertType(b.types, string(rest[:name]))
+	}
+	if !ok {
+		return nil, parser.NoChildren
+	}
+
+	// 제목: :::note[Title] 또는 :::note Title
+	start := segment.Start + pos + colons + name
+	title := util.TrimRightSpace(util.TrimLeftSpace(rest[name:]))
+	if len(title) > 0 {
+		start += bytes.Index(rest[name:], title)
+		if title[0] == '[' && title[len(title)-1] == ']' {
+			title = title[1 : len(title)-1]
+			start++
+		}
+	}
+
+	node := &alert{typ: typ, fence: colons}
+	if len(title) > 0 {
+		// 다른 블록 줄처럼 인라인으로 해석
+		t := &alertTitle{}
+		t.Lines().Append(text.NewSegment(start, start+len(title)))
+		node.AppendChild(node, t)
+		node.titled = true
+	}
+	advanceLine(reader, line, segment)
+	return node, parser.HasChildren
+}
+
+func (b *docusaurusAlertParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	fence := node.(*alert).fence
+	trimmed := util.TrimRightSpace(util.TrimLeftSpace(line))
+	if len(trimmed) == fence && bytes.Count(trimmed, []byte{':'}) == fence {
+		advanceLine(reader, line, segment)
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *docusaurusAlertParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *docusaurusAlertParser) CanInterruptParagraph() bool { return true }
+
+func (b *docusaurusAlertParser) CanAcceptIndentedLine() bool { return false }
+
+type docsifyAlertParser struct{}
+
+func (b *docsifyAlertParser) Trigger() []byte { return []byte{'!', '?'} }
+
+func (b *docsifyAlertParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || pos+2 >= len(line) || line[pos+1] != '>' || line[pos+2] != ' ' {
+		return nil, parser.NoChildren
+	}
+	typ := "IMPORTANT"
+	if line[pos] == '?' {
+		typ = "TIP"
+	}
+	reader.Advance(pos + 3)
+	return &alert{typ: typ}, parser.HasChildren
+}
+
+func (b *docsifyAlertParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if util.IsBlank(line) {
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *docsifyAlertParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *docsifyAlertParser) CanInterruptParagraph() bool { return false }
+
+func (b *docsifyAlertParser) CanAcceptIndentedLine() bool { return false }
+
+type alertRenderer struct {
+	pdf   bool
+	types map[string]alertType
+}
+
+func (r *alertRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindAlert, r.renderAlert)
+	reg.Register(kindAlertTitle, r.renderAlertTitle)
+}
+
+// folded는 알림을 <details>로 렌더링하는지 보고한다.
+func (r *alertRenderer) folded(n *alert) bool {
+	return n.fold != 0 && n.titled && !r.pdf
+}
+
+func (r *alertRenderer) renderAlert(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*alert)
+	if !entering {
+		if r.folded(n) {
+			_, _ = w.WriteString("</div></details>\n")
+		} else {
+			_, _ = w.WriteString("</div></div>\n")
+		}
+		return ast.WalkContinue, nil
+	}
+	t := r.types[n.typ]
+	attrs := `class="alert ` + t.class + `"`
+	if t.color != "" {
+		attrs += ` style="--alert-color: ` + string(util.EscapeHTML([]byte(t.color))) + `"`
+	}
+	switch {
+	case r.folded(n) && n.fold == '+':
+		_, _ = w.WriteString(`<details ` + attrs + " open>\n")
+	case r.folded(n):
+		_, _ = w.WriteString(`<details ` + attrs + ">\n")
+	default:
+		_, _ = w.WriteString(`<div ` + attrs + `>` + alertIcon(t, "div") + `<div class="alert-content">` + "\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *alertRenderer) renderAlertTitle(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.Parent().(*alert)
+	switch {
+	case !r.folded(n) && entering:
+		_, _ = w.WriteString(`<div class="alert-title">`)
+	case !r.folded(n):
+		_, _ = w.WriteString("</div>\n")
+	case entering:
+		_, _ = w.WriteString(`<summary class="alert-title">` + alertIcon(r.types[n.typ], "span"))
+	default:
+		_, _ = w.WriteString("</summary>\n" + `<div class="alert-content">` + "\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// alertIcon은 알림 타입의 아이콘 요소를 반환한다.
+func alertIcon(t alertType, tag string) string {
+	return `<` + tag + ` class="alert-icon"><i class="fas ` + string(util.EscapeHTML([]byte(t.icon))) + `"></i></` + tag + `>`
+}
@@ -0,0 +1,173 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+
+	"md2pdf/logging"
+)
+
+func TestDocusaurusAdmonitions(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+		not  []string
+	}{
+		{"basic", ":::tip\nbody\n:::\n\nafter\n",
+			[]string{`<div class="alert alert-tip">`, "<p>body</p>", "</div></div>\n<p>after</p>"}, nil},
+		{"closing fence at end of file", ":::tip\nbody\n:::",
+			[]string{"<p>body</p>"}, []string{":</p>"}},
+		{"nested fences", "::::note\n:::warning\ninner\n:::\nouter\n::::\n",
+			[]string{`<div class="alert alert-warning">`, "<p>inner</p>", "<p>outer</p>"}, []string{":::"}},
+		{"unknown type", ":::nope\nbody\n:::\n",
+			[]string{"<p>:::nope\nbody\n:::</p>"}, []string{"alert"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkAlerts(t, tt.in, tt.want, tt.not)
+		})
+	}
+}
+
+// checkAlerts는 in을 알림 확장으로 변환한 HTML에 want가 모두 있고 not은
+// 하나도 없는지 확인한다.
+func checkAlerts(t *testing.T, in string, want, not []string) {
+	t.Helper()
+	md := goldmark.New(goldmark.WithExtensions(&alertExtension{types: newAlertTypes(AuthorsConfig{}, logging.Use(logging.Discard))}))
+	var b bytes.Buffer
+	if err := md.Convert([]byte(in), &b); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range want {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("output lacks %q:\n%s", s, b.String())
+		}
+	}
+	for _, s := range not {
+		if strings.Contains(b.String(), s) {
+			t.Errorf("output contains %q:\n%s", s, b.String())
+		}
+	}
+}
+
+func TestBlockquoteAlerts(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+		not  []string
+	}{
+		{"nested alert", "> [!NOTE]\n> outer\n>\n> > [!WARNING]\n> > inner\n",
+			[]string{`<div class="alert alert-note">`, "<p>outer</p>\n" + `<div class="alert alert-warning">`, "<p>inner</p>\n</div></div>\n</div></div>"},
+			[]string{"<blockquote>", "[!"}},
+		// 일반 인용문 안의 콜아웃과 콜아웃 안의 일반 인용문
+		{"alert in quote", "> quote\n>\n> > [!TIP]\n> > tip in quote\n",
+			[]string{"<blockquote>\n<p>quote</p>\n" + `<div class="alert alert-tip">`, "</div></div>\n</blockquote>"}, []string{"[!"}},
+		{"quote in alert", "> [!NOTE]\n> > plain nested quote\n",
+			[]string{`<div class="alert alert-note">`, "<blockquote>\n<p>plain nested quote</p>\n</blockquote>"}, nil},
+		{"foldable nested alert", "> [!faq]- Outer\n> > [!bug]+ Inner\n> > body\n",
+			[]string{`<details class="alert alert-question">`, "</span>Outer</summary>", `<details class="alert alert-bug" open>`, "</span>Inner</summary>", "<p>body</p>"}, []string{"[!"}},
+		{"unknown type", "> [!NOPE]\n> text\n", []string{"<blockquote>", "[!NOPE]"}, []string{"alert"}},
+		{"marker not first", "> text\n> [!NOTE]\n", []string{"<blockquote>"}, []string{"alert-note"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkAlerts(t, tt.in, tt.want, tt.not)
+		})
+	}
+}
+
+func TestDocsifyAlerts(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+		not  []string
+	}{
+		{"important", "!> important text\n",
+			[]string{`<div class="alert alert-important">`, "<p>important text</p>"}, []string{"!&gt;"}},
+		{"tip", "?> tip text\n", []string{`<div class="alert alert-tip">`, "<p>tip text</p>"}, []string{"?&gt;"}},
+		// 빈 줄까지 이어지고, 앞의 **제목**: 은 알림 제목
+		{"until blank line", "!> **Heads up**: first\nsecond\n\nafter\n",
+			[]string{`<div class="alert-title">Heads up</div>`, "first\nsecond</p>\n</div></div>\n<p>after</p>"}, nil},
+		{"in list item", "- ?> listed\n", []string{"<li>\n" + `<div class="alert alert-tip">`}, nil},
+		{"in blockquote", "> !> quoted\n", []string{"<blockquote>\n" + `<div class="alert alert-important">`}, nil},
+		// 줄 중간이나 코드 안의 !>, ?>는 텍스트
+		{"mid line", "a !> b ?> c\n", []string{"<p>a !&gt; b ?&gt; c</p>"}, []string{"alert"}},
+		{"code span", "`!> code`\n", []string{"<code>!&gt; code</code>"}, []string{"alert"}},
+		{"fenced code", "```\n?> code\n```\n", []string{"<pre><code>?&gt; code\n</code></pre>"}, []string{"alert"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkAlerts(t, tt.in, tt.want, tt.not)
+		})
+	}
+}
+
+func TestCalloutFolding(t *testing.T) {
+	icon := func(tag, name string) string {
+		return `<` + tag + ` class="alert-icon"><i class="fas ` + name + `"></i></` + tag + `>`
+	}
+	tests := []struct {
+		name      string
+		in        string
+		html, pdf string // 알림의 시작과 제목
+	}{
+		{"closed", "> [!faq]- Why?\n> Because.\n",
+			`<details class="alert alert-question">` + "\n" + `<summary class="alert-title">` + icon("span", "fa-circle-question") + `Why?</summary>` + "\n" + `<div class="alert-content">` + "\n<p>Because.</p>\n</div></details>",
+			`<div class="alert alert-question">` + icon("div", "fa-circle-question") + `<div class="alert-content">` + "\n" + `<div class="alert-title">Why?</div>` + "\n<p>Because.</p>\n</div></div>"},
+		{"open", "> [!tip]+ Open\n> body\n",
+			`<details class="alert alert-tip" open>` + "\n" + `<summary class="alert-title">` + icon("span", "fa-lightbulb") + `Open</summary>`,
+			`<div class="alert alert-tip">` + icon("div", "fa-lightbulb") + `<div class="alert-content">` + "\n" + `<div class="alert-title">Open</div>`},
+		// 제목이 없으면 종류 이름이 제목
+		{"default title", "> [!bug]-\n> body\n",
+			`<details class="alert alert-bug">` + "\n" + `<summary class="alert-title">` + icon("span", "fa-bug") + `Bug</summary>`,
+			`<div class="alert-title">Bug</div>`},
+		{"GitHub type", "> [!NOTE]-\n> body\n",
+			`<details class="alert alert-note">` + "\n" + `<summary class="alert-title">` + icon("span", "fa-info-circle") + `Note</summary>`,
+			`<div class="alert-title">Note</div>`},
+		{"custom type", "> [!experiment]- Lab\n> body\n",
+			`<details class="alert alert-custom alert-experiment" style="--alert-color: #8b5cf6">` + "\n" + `<summary class="alert-title">` + icon("span", "fa-flask") + `Lab</summary>`,
+			`<div class="alert alert-custom alert-experiment" style="--alert-color: #8b5cf6">` + icon("div", "fa-flask")},
+		{"inline title", "> [!NOTE]+ **Bold** title\n> body\n",
+			`<summary class="alert-title">` + icon("span", "fa-info-circle") + `<strong>Bold</strong> title</summary>`,
+			`<div class="alert-title"><strong>Bold</strong> title</div>`},
+		// 중첩된 알림도 각각 접음
+		{"nested", "> [!faq]- Outer\n> > [!bug]+ Inner\n> > body\n",
+			`</summary>` + "\n" + `<div class="alert-content">` + "\n" + `<details class="alert alert-bug" open>`,
+			`<div class="alert-title">Outer</div>` + "\n" + `<div class="alert alert-bug">`},
+		// 접을 수 없는 알림은 HTML에서도 펼친 상자
+		{"not foldable", "> [!faq] Why?\n> Because.\n",
+			`<div class="alert alert-question">` + icon("div", "fa-circle-question") + `<div class="alert-content">` + "\n" + `<div class="alert-title">Why?</div>`,
+			`<div class="alert alert-question">` + icon("div", "fa-circle-question") + `<div class="alert-content">` + "\n" + `<div class="alert-title">Why?</div>`},
+	}
+	for _, tt := range tests {
+		for _, pdf := range []bool{false, true} {
+			name, want := tt.name+"/html", tt.html
+			if pdf {
+				name, want = tt.name+"/pdf", tt.pdf
+			}
+			t.Run(name, func(t *testing.T) {
+				b, err := convertDocs(t, map[string]string{
+					"AUTHORS.yml": "callouts:\n  experiment:\n    icon: fa-flask\n    color: \"#8b5cf6\"\n",
+					"01-intro.md": "# Intro\n\n" + tt.in,
+				}, Options{PDFMode: pdf})
+				if err != nil {
+					t.Fatal(err)
+				}
+				content := b.sections[0].Content
+				if !strings.Contains(content, want) {
+					t.Errorf("missing %q in:\n%s", want, content)
+				}
+				// PDF에는 접힌 알림이 없음
+				if pdf && strings.Contains(content, "<details") {
+					t.Errorf("details in PDF:\n%s", content)
+				}
+			})
+		}
+	}
+}
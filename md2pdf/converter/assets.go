@@ -0,0 +1,199 @@
+package converter
+
+import (
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"md2pdf/logging"
+)
+
+// embeddedAssets는 템플릿이 쓰는 CDN 자산(글꼴, 아이콘, Mermaid)의 내장
+// 사본이다. 파일 경로는 CDN URL을 그대로 따른다: https://host/path는
+// assets/cdn/host/path에 저장한다. 갱신하려면 `go run ./scripts/fetch_assets.go`를
+// 실행한다.
+//
+//go:embed all:assets
+var embeddedAssets embed.FS
+
+// assetFS는 자산을 읽는 파일 시스템 (테스트에서 교체)
+var assetFS fs.FS = embeddedAssets
+
+const assetRoot = "assets/cdn"
+
+// styleRoot는 템플릿이 함께 쓰는 공통 스타일시트 디렉터리
+const styleRoot = "assets/css"
+
+var (
+	reAssetLink   = regexp.MustCompile(`<link\b[^>]*\bhref="(https?://[^"]+)"[^>]*>`)
+	reAssetScript = regexp.MustCompile(`<script\b[^>]*\bsrc="(https?://[^"]+)"[^>]*>\s*</script>`)
+	reCSSImport   = regexp.MustCompile(`@import\s+url\(\s*['"]?(https?://[^'")]+)['"]?\s*\)\s*;?`)
+	reCSSURL      = regexp.MustCompile(`url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+	reQueryChars  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	reStyleLink   = regexp.MustCompile(`<link rel="stylesheet" href="(` + styleRoot + `/[^"]+)">`)
+)
+
+// assetPath는 CDN URL을 내장 자산 트리의 경로로 바꾼다.
+func assetPath(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	p := path.Join(assetRoot, u.Host, u.Path)
+	if u.RawQuery != "" {
+		p += "_" + reQueryChars.ReplaceAllString(u.RawQuery, "_")
+	}
+	return p, true
+}
+
+// inlineAssets는 렌더링한 문서의 CDN 스타일시트, @import 규칙, 스크립트를
+// 내장 자산 트리로 만든 data: URI로 바꾼다. 스타일시트가 참조하는 글꼴도
+// 함께 인라인한다. 내장되지 않은 자산은 CDN 주소를 그대로 두며, strict(오프라인)
+// 모드에서는 대신 오류로 보고한다.
+func inlineAssets(htmlContent string, strict bool, log logging.Printer) (string, error) {
+	missing := make(map[string]bool)
+
+	htmlContent = reAssetLink.ReplaceAllStringFunc(htmlContent, func(tag string) string {
+		if !strings.Contains(tag, "stylesheet") {
+			return tag
+		}
+		href := reAssetLink.FindStringSubmatch(tag)[1]
+		css, ok := loadCSSAsset(href, missing)
+		if !ok {
+			return tag
+		}
+		return fmt.Sprintf(`<link rel="stylesheet" href="%s">`, dataURI("text/css", []byte(css)))
+	})
+
+	htmlContent = reCSSImport.ReplaceAllStringFunc(htmlContent, func(rule string) string {
+		href := reCSSImport.FindStringSubmatch(rule)[1]
+		css, ok := loadCSSAsset(href, missing)
+		if !ok {
+			return rule
+		}
+		return fmt.Sprintf(`@import url("%s");`, dataURI("text/css", []byte(css)))
+	})
+
+	htmlContent = reAssetScript.ReplaceAllStringFunc(htmlContent, func(tag string) string {
+		src := reAssetScript.FindStringSubmatch(tag)[1]
+		p, ok := assetPath(src)
+		if !ok {
+			missing[src] = true
+			return tag
+		}
+		data, err := fs.ReadFile(assetFS, p)
+		if err != nil {
+			missing[src] = true
+			return tag
+		}
+		return fmt.Sprintf(`<script src="%s"></script>`, dataURI("text/javascript", data))
+	})
+
+	if len(missing) == 0 {
+		return htmlContent, nil
+	}
+
+	var urls []string
+	for u := range missing {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	if strict {
+		return htmlContent, fmt.Errorf("offline build: %d assets are not vendored (run `go run ./scripts/fetch_assets.go`):\n  %s",
+			len(urls), strings.Join(urls, "\n  "))
+	}
+	for _, u := range urls {
+		log.Infof("Asset not vendored, using CDN: %s", u)
+	}
+	return htmlContent, nil
+}
+
+// inlineStyles는 템플릿이 링크한 공통 스타일시트(assets/css)를 <style> 요소로
+// 바꾼다. 이 스타일시트는 CDN 자산이 아니라 md2pdf의 일부이므로, 출력 HTML이
+// 외부 파일 없이 열리도록 인라인 설정과 관계없이 항상 내장 사본에서 읽는다.
+func inlineStyles(tmpl string) (string, error) {
+	var err error
+	tmpl = reStyleLink.ReplaceAllStringFunc(tmpl, func(tag string) string {
+		p := reStyleLink.FindStringSubmatch(tag)[1]
+		css, readErr := fs.ReadFile(embeddedAssets, p)
+		if readErr != nil {
+			err = fmt.Errorf("template stylesheet: %w", readErr)
+			return tag
+		}
+		return "<style>\n" + string(css) + "    </style>"
+	})
+	return tmpl, err
+}
+
+// loadCSSAsset은 내장 스타일시트를 읽고 참조하는 글꼴과 이미지를 인라인한다.
+// 상대 url() 참조는 브라우저와 마찬가지로 스타일시트 자신의 위치를 기준으로
+// 해석한다.
+func loadCSSAsset(href string, missing map[string]bool) (string, bool) {
+	p, ok := assetPath(href)
+	if !ok {
+		missing[href] = true
+		return "", false
+	}
+	data, err := fs.ReadFile(assetFS, p)
+	if err != nil {
+		missing[href] = true
+		return "", false
+	}
+
+	base, _ := url.Parse(href)
+	css := reCSSURL.ReplaceAllStringFunc(string(data), func(ref string) string {
+		target := strings.TrimSpace(reCSSURL.FindStringSubmatch(ref)[1])
+		if strings.HasPrefix(target, "data:") || strings.HasPrefix(target, "#") {
+			return ref
+		}
+		rel, err := url.Parse(target)
+		if err != nil {
+			return ref
+		}
+		abs := base.ResolveReference(rel)
+		abs.Fragment = ""
+		assetURL := abs.String()
+		fontPath, ok := assetPath(assetURL)
+		if !ok {
+			missing[assetURL] = true
+			return ref
+		}
+		font, err := fs.ReadFile(assetFS, fontPath)
+		if err != nil {
+			missing[assetURL] = true
+			return ref
+		}
+		return fmt.Sprintf(`url("%s")`, dataURI(assetMimeType(abs.Path), font))
+	})
+	return css, true
+}
+
+func assetMimeType(p string) string {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".woff2":
+		return "font/woff2"
+	case ".woff":
+		return "font/woff"
+	case ".ttf":
+		return "font/ttf"
+	case ".otf":
+		return "font/otf"
+	case ".eot":
+		return "application/vnd.ms-fontobject"
+	}
+	if t := mime.TypeByExtension(path.Ext(p)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+func dataURI(mimeType string, data []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data))
+}
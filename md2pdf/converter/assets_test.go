@@ -0,0 +1,175 @@
+package converter
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"md2pdf/logging"
+)
+
+// useAssets는 테스트 동안 자산 파일 시스템을 files로 바꾼다.
+func useAssets(t *testing.T, files fstest.MapFS) {
+	t.Helper()
+	saved := assetFS
+	assetFS = files
+	t.Cleanup(func() { assetFS = saved })
+}
+
+func TestAssetPath(t *testing.T) {
+	tests := []struct {
+		url, want string
+		ok        bool
+	}{
+		{"https://cdn.jsdelivr.net/npm/mermaid@10.9.1/dist/mermaid.min.js", "assets/cdn/cdn.jsdelivr.net/npm/mermaid@10.9.1/dist/mermaid.min.js", true},
+		{"https://fonts.googleapis.com/css2?family=Noto+Sans+KR:wght@400;700&display=swap", "assets/cdn/fonts.googleapis.com/css2_family_Noto_Sans_KR_wght_400_700_display_swap", true},
+		{"relative/path.css", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := assetPath(tt.url)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("assetPath(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInlineAssets(t *testing.T) {
+	useAssets(t, fstest.MapFS{
+		"assets/cdn/cdn.test/lib/font.css":      {Data: []byte(`@font-face { src: url(files/a.woff2) format("woff2"), url('data:font/woff;base64,AA'); }`)},
+		"assets/cdn/cdn.test/lib/files/a.woff2": {Data: []byte("woff2")},
+		"assets/cdn/cdn.test/lib/app.js":        {Data: []byte("app()")},
+		"assets/cdn/cdn.test/lib/broken.css":    {Data: []byte(`.x { background: url(missing.png); }`)},
+	})
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		not     []string
+		missing string // strict 모드 오류에 나와야 하는 URL
+	}{
+		{"stylesheet with font", `<link rel="stylesheet" href="https://cdn.test/lib/font.css">`,
+			[]string{`<link rel="stylesheet" href="data:text/css;base64,`}, []string{"https://"}, ""},
+		{"import rule", `<style>@import url('https://cdn.test/lib/font.css');</style>`,
+			[]string{`@import url("data:text/css;base64,`}, []string{"https://"}, ""},
+		{"script", `<script src="https://cdn.test/lib/app.js"></script>`,
+			[]string{`<script src="data:text/javascript;base64,YXBwKCk="></script>`}, nil, ""},
+		{"preconnect link kept", `<link rel="preconnect" href="https://cdn.test">`,
+			[]string{`<link rel="preconnect" href="https://cdn.test">`}, nil, ""},
+		{"missing script", `<script src="https://cdn.test/lib/none.js"></script>`,
+			[]string{`src="https://cdn.test/lib/none.js"`}, nil, "https://cdn.test/lib/none.js"},
+		{"missing font", `<link rel="stylesheet" href="https://cdn.test/lib/broken.css">`,
+			nil, nil, "https://cdn.test/lib/missing.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inlineAssets(tt.in, false, logging.Use(logging.Discard))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("output lacks %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.not {
+				if strings.Contains(got, s) {
+					t.Errorf("output contains %q:\n%s", s, got)
+				}
+			}
+			_, err = inlineAssets(tt.in, true, logging.Use(logging.Discard))
+			switch {
+			case tt.missing == "" && err != nil:
+				t.Errorf("strict: unexpected error: %v", err)
+			case tt.missing != "" && (err == nil || !strings.Contains(err.Error(), tt.missing)):
+				t.Errorf("strict: error = %v, want %q", err, tt.missing)
+			}
+		})
+	}
+}
+
+// reExternalAsset는 인라인 후에도 네트워크에서 읽는 자산 참조
+var reExternalAsset = regexp.MustCompile(`(<link\b[^>]*\bstylesheet\b[^>]*\bhref|<script\b[^>]*\bsrc)="https?://[^"]+"|@import\s+url\(\s*['"]?https?://|url\(\s*['"]?https?://`)
+
+func TestInlineAssetsTemplates(t *testing.T) {
+	if _, err := fs.Stat(embeddedAssets, assetRoot); err != nil {
+		t.Skipf("CDN assets are not vendored (%s missing); run `go run ./scripts/fetch_assets.go`", assetRoot)
+	}
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Run(e.Name(), func(t *testing.T) {
+			data, err := templateFS.ReadFile("templates/" + e.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := inlineAssets(string(data), true, logging.Use(logging.Discard))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m := reExternalAsset.FindString(got); m != "" {
+				t.Errorf("template still loads %s", m)
+			}
+		})
+	}
+}
+
+// sharedRules는 common.css로 옮긴 규칙이다. 템플릿에 다시 쓰면 안 된다.
+var sharedRules = []string{
+	".math-display {",
+	".toc-sub-l4 {",
+	".toc-sub-item-l4 {",
+	"figcaption {",
+	".caption-label {",
+	".book-index {",
+	".glossary-term {",
+	"dl.glossary dt {",
+	".page-break {",
+	".landscape {",
+	".tab-labels {",
+	".tabs-stacked > .tab-panel {",
+	".alert-bug {",
+	".alert-custom {",
+	"details.alert > summary {",
+}
+
+func TestInlineStyles(t *testing.T) {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Run(e.Name(), func(t *testing.T) {
+			data, err := templateFS.ReadFile("templates/" + e.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 공통 스타일은 템플릿에 직접 쓰지 않고 링크한 뒤 인라인
+			if !strings.Contains(string(data), `<link rel="stylesheet" href="assets/css/common.css">`) {
+				t.Fatal("template does not link assets/css/common.css")
+			}
+			got, err := inlineStyles(string(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(got, `href="assets/css/`) {
+				t.Error("stylesheet link left in the output")
+			}
+			for _, rule := range sharedRules {
+				if strings.Contains(string(data), rule) {
+					t.Errorf("template defines %q, which belongs in common.css", rule)
+				}
+				if !strings.Contains(got, rule) {
+					t.Errorf("common rule %q is not inlined", rule)
+				}
+			}
+		})
+	}
+
+	if _, err := inlineStyles(`<link rel="stylesheet" href="assets/css/missing.css">`); err == nil {
+		t.Error("missing stylesheet: no error")
+	}
+}
@@ -0,0 +1,129 @@
+package converter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"md2pdf/logging"
+)
+
+// 조건부 콘텐츠는 빌드 프로필(Options.Profiles)과 출력 형식("pdf" 또는
+// "html")에 따라 파싱 전에 남기거나 버린다:
+//
+//	::: only admin                    블록 (중첩은 더 긴 울타리: ::::)
+//	...
+//	:::
+//	{{< only pdf >}}text{{< /only >}}  인라인, 한 줄 안에서
+//	only: [admin]                     (front matter) 파일 전체
+//
+// 조건은 공백이나 쉼표로 구분한 태그 목록이다. 활성인 !태그가 없고 태그
+// 중 하나가 활성이면 참이고, !태그만 있으면 활성인 !태그가 없는 것만으로
+// 참이다: "admin user", "!internal", "admin !pdf". 버린 줄은 빈 줄로 바꿔
+// 줄 번호가 바뀌지 않는다.
+
+var (
+	reOnlyOpen   = regexp.MustCompile(`^[ \t]*(:{3,})[ \t]*only[ \t]+(.*?)[ \t]*$`)
+	reOnlyClose  = regexp.MustCompile(`^[ \t]*(:{3,})[ \t]*$`)
+	reDivOpen    = regexp.MustCompile(`^[ \t]*(:{3,})[ \t]*[A-Za-z]`)
+	reOnlyInline = regexp.MustCompile(`\{\{<\s*only\s+([^>]*?)\s*>\}\}(.*?)\{\{<\s*/only\s*>\}\}`)
+)
+
+// profile은 활성 태그의 집합이다.
+type profile map[string]bool
+
+func newProfile(opts Options) profile {
+	p := make(profile)
+	for _, name := range opts.Profiles {
+		for _, tag := range strings.FieldsFunc(name, isTagSeparator) {
+			p[tag] = true
+		}
+	}
+	if opts.PDFMode {
+		p["pdf"] = true
+	} else {
+		p["html"] = true
+	}
+	return p
+}
+
+func isTagSeparator(r rune) bool { return r == ',' || r == ' ' || r == '\t' }
+
+// match는 조건이 참인지 알려준다.
+func (p profile) match(condition string) bool {
+	positive, matched := false, false
+	for _, tag := range strings.FieldsFunc(condition, isTagSeparator) {
+		if strings.HasPrefix(tag, "!") {
+			if p[tag[1:]] {
+				return false
+			}
+			continue
+		}
+		positive = true
+		matched = matched || p[tag]
+	}
+	return matched || !positive
+}
+
+// filter는 파일에서 조건이 거짓인 내용을 버린다. 다른 ":::name" 블록(주의
+// 상자)도 따라가서, 그 닫는 ":::"가 바깥 only 블록을 닫지 않게 한다.
+func (p profile) filter(file string, content []byte, log logging.Printer) []byte {
+	if !bytes.Contains(content, []byte("only")) {
+		return content
+	}
+	type block struct {
+		fence, line int
+		keep, only  bool
+	}
+	var open []block
+	var out bytes.Buffer
+	var fences fenceTracker
+	for i, line := range bytes.SplitAfter(content, []byte("\n")) {
+		keep := len(open) == 0 || open[len(open)-1].keep
+		if !fences.inCode(line) {
+			trimmed := bytes.TrimRight(line, "\r\n")
+			if m := reOnlyOpen.FindSubmatch(trimmed); m != nil {
+				open = append(open, block{fence: len(m[1]), line: i + 1, keep: keep && p.match(string(m[2])), only: true})
+				blankLine(&out, line)
+				continue
+			}
+			if m := reDivOpen.FindSubmatch(trimmed); m != nil {
+				open = append(open, block{fence: len(m[1]), line: i + 1, keep: keep})
+			} else if m := reOnlyClose.FindSubmatch(trimmed); m != nil && len(open) > 0 && open[len(open)-1].fence == len(m[1]) {
+				closed := open[len(open)-1]
+				open = open[:len(open)-1]
+				if closed.only {
+					blankLine(&out, line)
+					continue
+				}
+			} else if keep {
+				line = reOnlyInline.ReplaceAllFunc(line, func(b []byte) []byte {
+					m := reOnlyInline.FindSubmatch(b)
+					if p.match(string(m[1])) {
+						return m[2]
+					}
+					return nil
+				})
+			}
+		}
+		if keep {
+			out.Write(line)
+		} else {
+			blankLine(&out, line)
+		}
+	}
+	for _, b := range open {
+		if !b.only {
+			continue
+		}
+		log.At(file, b.line).Warnf("Unclosed '::: only' block")
+	}
+	return out.Bytes()
+}
+
+// blankLine은 line의 줄 끝만 쓴다.
+func blankLine(out *bytes.Buffer, line []byte) {
+	if bytes.HasSuffix(line, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"testing"
+
+	"md2pdf/logging"
+)
+
+func TestProfileFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []string
+		in, want string
+	}{
+		{
+			name:     "kept block",
+			profiles: []string{"admin"},
+			in:       "a\n::: only admin\nb\n:::\nc\n",
+			want:     "a\n\nb\n\nc\n",
+		},
+		{
+			name: "dropped block",
+			in:   "a\n::: only admin\nb\n:::\nc\n",
+			want: "a\n\n\n\nc\n",
+		},
+		{
+			name:     "negated tag",
+			profiles: []string{"internal"},
+			in:       "::: only !internal\nb\n:::\n",
+			want:     "\n\n\n",
+		},
+		{
+			name:     "nested fences",
+			profiles: []string{"admin"},
+			in:       ":::: only admin\na\n::: only user\nb\n:::\nc\n::::\nd\n",
+			want:     "\na\n\n\n\nc\n\nd\n",
+		},
+		{
+			name:     "kept admonition in block",
+			profiles: []string{"admin"},
+			in:       "::: only admin\n:::tip\nb\n:::\nc\n:::\nd\n",
+			want:     "\n:::tip\nb\n:::\nc\n\nd\n",
+		},
+		{
+			name: "dropped admonition in block",
+			in:   "::: only admin\n:::tip\nb\n:::\nc\n:::\nd\n",
+			want: "\n\n\n\n\n\nd\n",
+		},
+		{
+			name: "block in admonition",
+			in:   ":::note\n::: only admin\nb\n:::\n:::\n",
+			want: ":::note\n\n\n\n:::\n",
+		},
+		{
+			name: "inline",
+			in:   "a {{< only pdf >}}P{{< /only >}}{{< only html >}}H{{< /only >}}\n",
+			want: "a H\n",
+		},
+		{
+			name: "code fence",
+			in:   "```\n::: only admin\n```\n",
+			want: "```\n::: only admin\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProfile(Options{Profiles: tt.profiles})
+			got := string(p.filter("test.md", []byte(tt.in), logging.Use(logging.Discard)))
+			if got != tt.want {
+				t.Errorf("filter(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,14 +7,18 @@ import (
 	"embed"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	gohtml "html"
+	"io"
+	"io/fs"
 	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -23,7 +27,10 @@ import (
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
 	"gopkg.in/yaml.v3"
+
+	"md2pdf/logging"
 )
 
 // AuthorsConfig는 AUTHORS.yml 파일 구조
@@ -32,18 +39,53 @@ type AuthorsConfig struct {
 	Organization string `yaml:"organization"`
 	Copyright    string `yaml:"copyright"`
 	Document     struct {
-		Title    string `yaml:"title"`
-		Subtitle string `yaml:"subtitle"`
-		Author   string `yaml:"author"`
-		Header   string `yaml:"header"`
-		Footer   string `yaml:"footer"`
+		Title    string   `yaml:"title"`
+		Subtitle string   `yaml:"subtitle"`
+		Author   string   `yaml:"author"`
+		Date     string   `yaml:"date"` // 발행일 (YYYY-MM-DD)
+		Header   string   `yaml:"header"`
+		Footer   string   `yaml:"footer"`
+		Subject  string   `yaml:"subject"`
+		Keywords []string `yaml:"keywords"`
 	} `yaml:"document"`
+	CrossRef struct {
+		Figure  string `yaml:"figure"` // 참조 문구, 예: "그림" (기본값 "Figure")
+		Table   string `yaml:"table"`
+		Listing string `yaml:"listing"`
+		Section string `yaml:"section"`
+		Page    string `yaml:"page"` // PDF 모드의 쪽 번호 앞 문구 (기본값 "p.")
+	} `yaml:"crossref"`
+	TOC struct {
+		Depth   int      `yaml:"depth"`   // 목차에 넣는 가장 깊은 제목 수준 (2-4)
+		Exclude []string `yaml:"exclude"` // 목차에서 뺄 제목 패턴
+	} `yaml:"toc"`
+	Vars     map[string]string `yaml:"vars"` // {{ var.name }}의 값
+	Callouts map[string]struct {
+		Icon  string `yaml:"icon"`  // Font Awesome 아이콘 (예: fa-flask)
+		Color string `yaml:"color"` // 테두리와 아이콘의 CSS 색
+		Label string `yaml:"label"` // 기본 제목
+	} `yaml:"callouts"` // 이름별 사용자 정의 콜아웃 타입 ("> [!name]")
+}
+
+// DocumentInfo는 확정된 문서 메타데이터 (CLI 값이 설정 파일보다 우선)
+type DocumentInfo struct {
+	Title     string
+	Subtitle  string
+	Version   string
+	Author    string
+	Header    string
+	Footer    string
+	Copyright string
+	Subject   string
+	Keywords  []string
+	Date      time.Time // 발행일 (documentDate 참고)
 }
 
 // SubHeading represents a sub-heading within a section (H2, H3, etc.)
 type SubHeading struct {
 	Title      string `json:"title"`
 	ID         string `json:"id"`
+	Number     string `json:"number,omitempty"` // 제목 번호 (Title에 포함)
 	Level      int    `json:"level"`
 	PageNumber int    `json:"page,omitempty"`
 }
@@ -52,10 +94,36 @@ type SubHeading struct {
 type Section struct {
 	Title       string       `json:"title"`
 	ID          string       `json:"id"`
+	Number      string       `json:"number,omitempty"` // 제목 번호 (Title에 포함)
 	Content     string       `json:"-"`
 	Level       int          `json:"level"`
+	Unlisted    bool         `json:"unlisted,omitempty"` // 목차에서 제외
 	SubHeadings []SubHeading `json:"subheadings,omitempty"`
+	Labels      []Label      `json:"labels,omitempty"`  // 상호 참조 대상
+	Anchors     []string     `json:"anchors,omitempty"` // 찾아보기 항목 앵커
 	PageNumber  int          `json:"page,omitempty"`
+
+	// front matter에서 읽은 값
+	PageBreak *bool    `json:"pagebreak,omitempty"` // nil: 템플릿 기본값
+	Landscape bool     `json:"landscape,omitempty"`
+	Audience  []string `json:"audience,omitempty"`
+}
+
+// Classes는 템플릿에 넘길 섹션의 레이아웃 클래스를 반환한다
+// (page-break, no-page-break, landscape).
+func (s Section) Classes() string {
+	var classes []string
+	if s.PageBreak != nil {
+		if *s.PageBreak {
+			classes = append(classes, "page-break")
+		} else {
+			classes = append(classes, "no-page-break")
+		}
+	}
+	if s.Landscape {
+		classes = append(classes, "landscape")
+	}
+	return strings.Join(classes, " ")
 }
 
 // ManualConfig defines template data
@@ -69,6 +137,12 @@ type ManualConfig struct {
 	Footer    string
 	Copyright string
 	Sections  []Section
+
+	// 그림 목차 / 표 목차 (비어 있으면 표시하지 않음)
+	Figures    []Label
+	Tables     []Label
+	FigureName string // 캡션 이름, 예: "Figure"
+	TableName  string
 }
 
 // Options for HTML conversion
@@ -85,8 +159,63 @@ type Options struct {
 	Template     string
 	EmbedImages  bool
 	PDFMode      bool
-	SectionsJSON string // Output sections JSON path
-	PagesJSON    string // Input pages JSON path
+	SectionsJSON string         // 출력할 섹션 JSON 경로
+	PagesJSON    string         // 입력 쪽 번호 JSON 경로
+	Pages        map[string]int // 섹션/제목 ID별 쪽 번호 (PagesJSON보다 우선)
+	InlineAssets bool           // CDN 링크 대신 내장 글꼴·아이콘·스크립트를 인라인
+	Offline      bool           // 내장되지 않은 템플릿 자산이 있으면 실패 (InlineAssets 포함)
+	Output       io.Writer      // OutputFile 대신 HTML을 쓸 곳
+	Logger       logging.Logger // 기본값: logging.Default
+
+	// NumberHeadings는 문서 전체의 H1-H3에 번호를 붙인다(1, 1.1, 1.1.1).
+	// 파일별 제외와 부록 문자 번호는 numbering.go 참고.
+	NumberHeadings bool
+
+	// TOCDepth는 목차에 넣는 가장 깊은 제목 수준(2-4, 기본값: 설정 또는 2).
+	// TOCExclude는 목차에서 뺄 제목의 정규식(기본값: 설정 또는
+	// DefaultTOCExclude).
+	TOCDepth   int
+	TOCExclude []string
+
+	// FigureNumbering은 "global"(Figure 3, 기본값) 또는 "chapter"(Figure
+	// 2.1). ListOfFigures/ListOfTables는 지원하는 템플릿의 앞부분에 목록을
+	// 넣는다.
+	FigureNumbering string
+	ListOfFigures   bool
+	ListOfTables    bool
+
+	// Index는 표시한 항목의 찾아보기를 끝에 붙인다. IndexTerms는 자동으로
+	// 찾아보기에 넣을 용어의 YAML 파일이다(Index 포함).
+	Index      bool
+	IndexTerms string
+
+	// Drafts는 front matter에 draft로 표시한 파일도 포함한다.
+	Drafts bool
+
+	// Profiles는 남길 조건부 콘텐츠의 태그다. 출력 형식 태그(PDFMode이면
+	// "pdf", 아니면 "html")가 더해진다(conditions.go 참고).
+	Profiles []string
+
+	// Tab은 이 이름(대소문자 무시)의 탭이 있는 탭 묶음에서 그 탭만 남긴다
+	// (tabs.go 참고).
+	Tab string
+
+	// Vars는 {{ var.name }}의 값이다(설정 파일 "vars:"보다 우선). EnvVars는
+	// {{ env.NAME }}을 치환하고, VarsInCode는 코드 안의 자리 표시자도 치환하며,
+	// StrictVars는 정의되지 않은 변수를 오류로 만든다(vars.go 참고).
+	Vars       map[string]string
+	EnvVars    bool
+	VarsInCode bool
+	StrictVars bool
+
+	// Glossary는 용어의 YAML 또는 Markdown 정의 목록 파일이다. 용어는 장마다
+	// 처음 나올 때 링크하고 용어집 부록에 모은다.
+	Glossary string
+
+	// Diagrams는 Mermaid 다이어그램을 SVG로 미리 렌더링한다. 없으면 캐시된
+	// 다이어그램만 정적 SVG가 되고 나머지는 브라우저에서 렌더링한다.
+	Diagrams     DiagramRenderer
+	DiagramCache string // Mermaid SVG 캐시 디렉터리 (기본값: <사용자 캐시 디렉터리>/md2pdf/mermaid)
 }
 
 //go:embed templates/*.html
@@ -95,32 +224,11 @@ var templateFS embed.FS
 // ConvertToHTML converts markdown files to a single HTML document.
 // Returns the list of sections for PDF analysis.
 func ConvertToHTML(opts Options) ([]Section, error) {
-	// Load config file
-	var cfg AuthorsConfig
-	if opts.ConfigFile != "" {
-		data, err := os.ReadFile(opts.ConfigFile)
-		if err != nil {
-			fmt.Printf("[WARN] Config file not found: %s\n", opts.ConfigFile)
-		} else {
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				fmt.Printf("[WARN] Failed to parse config file: %v\n", err)
-			} else {
-				fmt.Printf("[INFO] Loaded config: %s\n", opts.ConfigFile)
-			}
-		}
-	}
-
-	// Resolve final values (CLI overrides config)
-	finalTitle := resolveValue(opts.Title, cfg.Document.Title, cfg.ProjectName, "Document")
-	finalSubtitle := resolveValue(opts.Subtitle, cfg.Document.Subtitle, "", "")
-	finalAuthor := resolveValue(opts.Author, cfg.Document.Author, cfg.Organization, "")
-	finalHeader := resolveValue(opts.Header, cfg.Document.Header, "", "")
-	finalFooter := resolveValue(opts.Footer, cfg.Document.Footer, "", "")
-	finalCopyright := resolveValue("", cfg.Copyright, cfg.Organization, "")
-	finalVersion := opts.Version
-	if finalVersion == "" {
-		finalVersion = "1.0.0"
-	}
+	srcMap := newSourceMap(logging.Use(opts.Logger).Logger())
+	log := logging.Use(srcMap)
+	cfg := loadConfig(opts.ConfigFile, log)
+	docInfo := resolveInfo(opts, cfg, log)
+	toc := newTOCRules(opts, cfg, log)
 	templateName := opts.Template
 	if templateName == "" {
 		templateName = "report"
@@ -136,91 +244,201 @@ func ConvertToHTML(opts Options) ([]Section, error) {
 	if info.IsDir() {
 		sidebarPath := filepath.Join(opts.InputDir, "_sidebar.md")
 		files, err = parseSidebar(sidebarPath, opts.InputDir)
-		if err != nil {
-			fmt.Printf("[WARN] Could not parse sidebar, scanning directory: %v\n", err)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Infof("No _sidebar.md, scanning directory")
+			files, _ = scanMarkdownFiles(opts.InputDir)
+		case err != nil:
+			log.At(sidebarPath, 0).Warnf("Could not parse sidebar, scanning directory: %v", err)
 			files, _ = scanMarkdownFiles(opts.InputDir)
 		}
 	} else {
 		files = []string{opts.InputDir}
 	}
 
-	fmt.Printf("[INFO] Found %d markdown files\n", len(files))
+	log.Infof("Found %d markdown files", len(files))
 
 	// Goldmark setup
+	mathExt := &mathExtension{log: log}
+	xrefs := newCrossrefs(opts, cfg, log)
+	withIndex := opts.Index || opts.IndexTerms != ""
+	index := newBookIndex(opts.IndexTerms, log)
+	gloss := newGlossary(opts.Glossary, log)
+	vars := newVariables(opts, cfg, docInfo)
+	prof := newProfile(opts)
+	if len(opts.Profiles) > 0 {
+		log.Infof("Build profile: %s", strings.Join(opts.Profiles, ", "))
+	}
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,
 			extension.Table,
 			extension.Footnote,
 			extension.DefinitionList,
+			&alertExtension{pdf: opts.PDFMode, types: newAlertTypes(cfg, log)},
+			&markExtension{},
+			&emojiExtension{},
+			mathExt,
+			&crossrefExtension{refs: xrefs},
+			&headingNumberExtension{},
+			&indexExtension{},
+			&glossaryExtension{},
+			&tabsExtension{pdf: opts.PDFMode, selected: opts.Tab},
 		),
-		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID(), parser.WithAttribute()),
 		goldmark.WithRendererOptions(html.WithUnsafe()),
 	)
 
-	// Convert each file
-	var sections []Section
+	// 파일과 front matter 읽기
+	var sources []sourceFile
 	for _, file := range files {
 		if len(files) > 1 && strings.EqualFold(filepath.Base(file), "readme.md") {
-			fmt.Printf("[INFO] Skipping %s (Web landing page)\n", filepath.Base(file))
+			log.Infof("Skipping %s (Web landing page)", filepath.Base(file))
+			continue
+		}
+		if gloss.isSource(file) {
+			log.Infof("Skipping %s (glossary source)", filepath.Base(file))
 			continue
 		}
 
 		content, err := os.ReadFile(file)
 		if err != nil {
-			fmt.Printf("[WARN] Could not read %s: %v\n", file, err)
+			log.At(file, 0).Warnf("Could not read file: %v", err)
+			continue
+		}
+		fm, content := parseFrontMatter(file, content, log)
+		if fm.Exclude {
+			log.Infof("Skipping %s (excluded)", filepath.Base(file))
+			continue
+		}
+		if fm.Draft && !opts.Drafts {
+			log.Infof("Skipping %s (draft)", filepath.Base(file))
 			continue
 		}
+		if len(fm.Only) > 0 && !prof.match(strings.Join(fm.Only, " ")) {
+			log.Infof("Skipping %s (only %s)", filepath.Base(file), strings.Join(fm.Only, ", "))
+			continue
+		}
+		content, lines := expandIncludes(file, content, prof, log)
+		srcMap.set(file, lines)
+		content = docusaurusTabs(vars.expand(file, content, log))
+		sources = append(sources, sourceFile{path: file, content: content, fm: fm})
+	}
+	if err := vars.check(); err != nil {
+		return nil, err
+	}
+	sortSources(sources)
+	ids := sectionIDs(sources, log)
 
-		// Pre-process
-		stringContent := string(content)
-		stringContent = preprocessAlerts(stringContent)
-		stringContent = preprocessHighlight(stringContent)
-		stringContent = preprocessEmoji(stringContent)
+	// 파일마다 변환
+	mermaid := newMermaidRenderer(opts, log)
+	numbering := newHeadingNumberer(ids)
+	var sections []Section
+	for _, src := range sources {
+		file, content, fm := src.path, src.content, src.fm
 
 		var buf bytes.Buffer
-		if err := md.Convert([]byte(stringContent), &buf); err != nil {
-			fmt.Printf("[WARN] Could not convert %s: %v\n", file, err)
+		mathExt.file = file
+		doc := md.Parser().Parse(text.NewReader(content))
+		id := fileSectionID(file, ids)
+		if opts.NumberHeadings {
+			numbering.apply(doc, content, id)
+		}
+		titleText, number, level := documentTitle(doc, content)
+		if fm.Title != "" {
+			titleText = strings.TrimSpace(number + " " + fm.Title)
+		}
+		merged := level == 2 && len(sections) > 0
+		if !merged {
+			xrefs.startChapter(number)
+			gloss.startChapter()
+		}
+		labels := xrefs.collect(doc, content, file, log)
+		gloss.link(doc, content)
+		var anchors []string
+		if withIndex {
+			sectionTitle := titleText
+			if merged {
+				sectionTitle = sections[len(sections)-1].Title
+			}
+			anchors = index.collect(doc, content, sectionTitle)
+		}
+		if err := md.Renderer().Render(&buf, content, doc); err != nil {
+			log.At(file, 0).Warnf("Could not convert: %v", err)
 			continue
 		}
 
 		// Post-process
-		htmlContent := buf.String()
-		htmlContent = convertMermaidBlocks(htmlContent)
-		htmlContent = postProcessAlerts(htmlContent)
+		htmlContent, err := mermaid.replace(buf.String(), file, collectMermaid(doc, content))
+		if err != nil {
+			return nil, err
+		}
 
 		if opts.EmbedImages {
-			htmlContent = embedImages(htmlContent, file)
-			htmlContent = embedStylesheets(htmlContent, file)
+			htmlContent = embedImages(htmlContent, file, string(content), log)
+			htmlContent = embedStylesheets(htmlContent, file, string(content), log)
 		}
 
-		htmlContent = processUIComponents(htmlContent, file)
+		htmlContent = processUIComponents(htmlContent, file, string(content), log)
 		htmlContent = rewriteAssetPaths(htmlContent)
 		if opts.PDFMode {
-			htmlContent = rewriteInternalLinks(htmlContent)
+			htmlContent = rewriteInternalLinks(htmlContent, ids)
 		}
 
-		titleText, level := extractTitle(string(content))
-		id := generateID(file)
-		subHeadings := extractSubHeadings(htmlContent)
+		unlisted := (fm.TOC != nil && !*fm.TOC) || unlistedFile(doc)
+		var subHeadings []SubHeading
+		if !unlisted {
+			subHeadings = toc.subHeadings(doc, content)
+		}
+
+		layout := Section{PageBreak: fm.PageBreak, Landscape: fm.Landscape, Audience: fm.Audience}
 
 		// Merge H2 sections into previous
-		if level == 2 && len(sections) > 0 {
+		if merged {
 			lastIdx := len(sections) - 1
-			sections[lastIdx].Content += fmt.Sprintf("\n<div id=\"%s\"></div>\n%s", id, htmlContent)
+			if attrs := layoutAttributes(layout); attrs != "" {
+				// 합쳐진 파일의 레이아웃은 그 부분에만 적용
+				sections[lastIdx].Content += fmt.Sprintf("\n<div id=\"%s\"%s>\n%s</div>\n", id, attrs, htmlContent)
+			} else {
+				sections[lastIdx].Content += fmt.Sprintf("\n<div id=\"%s\"></div>\n%s", id, htmlContent)
+			}
 			sections[lastIdx].SubHeadings = append(sections[lastIdx].SubHeadings, subHeadings...)
-			fmt.Printf("[INFO] Merged %s into previous section '%s'\n", file, sections[lastIdx].Title)
+			sections[lastIdx].Labels = append(sections[lastIdx].Labels, labels...)
+			sections[lastIdx].Anchors = append(sections[lastIdx].Anchors, anchors...)
+			log.Debugf("Merged %s into previous section '%s'", file, sections[lastIdx].Title)
 			continue
 		}
 
 		sections = append(sections, Section{
 			Title:       titleText,
 			ID:          id,
+			Number:      number,
 			Content:     htmlContent,
 			Level:       level,
+			Unlisted:    unlisted,
 			SubHeadings: subHeadings,
+			Labels:      labels,
+			Anchors:     anchors,
+			PageBreak:   layout.PageBreak,
+			Landscape:   layout.Landscape,
+			Audience:    layout.Audience,
 		})
 	}
+	if len(gloss.entries) > 0 {
+		sections = append(sections, Section{Title: glossaryTitle, ID: glossaryID, Content: gloss.render(), Level: 1})
+	}
+	if withIndex && len(index.refs) > 0 {
+		sections = append(sections, Section{Title: indexTitle, ID: indexID, Level: 1})
+	}
+
+	if opts.NumberHeadings {
+		for i := range sections {
+			sections[i].Content = numbering.relabelLinks(sections[i].Content)
+		}
+	}
+	if err := xrefs.check(sections, log); err != nil {
+		return nil, err
+	}
 
 	// Output sections JSON (for 2-Pass)
 	if opts.SectionsJSON != "" {
@@ -231,30 +449,146 @@ func ConvertToHTML(opts Options) ([]Section, error) {
 		if err := os.WriteFile(opts.SectionsJSON, jsonData, 0644); err != nil {
 			return sections, fmt.Errorf("failed to write sections JSON: %w", err)
 		}
-		fmt.Printf("[INFO] Sections JSON saved: %s\n", opts.SectionsJSON)
+		log.Infof("Sections JSON saved: %s", opts.SectionsJSON)
 	}
 
 	// Load page numbers (for 2-Pass, Pass 2)
-	if opts.PagesJSON != "" {
-		applyPageNumbers(opts.PagesJSON, sections)
+	pages := opts.Pages
+	if pages == nil && opts.PagesJSON != "" {
+		if pages, err = readPagesJSON(opts.PagesJSON); err != nil {
+			log.Warnf("%v", err)
+		}
+	}
+	if pages != nil {
+		applyPageNumbers(pages, sections, log)
+	}
+	for i := range sections {
+		sections[i].Content = xrefs.resolve(sections[i].Content, pages, opts.PDFMode)
+	}
+	if last := len(sections) - 1; withIndex && last >= 0 && sections[last].ID == indexID {
+		sections[last].Content = index.render(pages, opts.PDFMode)
 	}
+	lists := xrefs.lists(sections)
 
 	// Generate HTML
-	htmlContent, err := generateHTML(finalTitle, finalSubtitle, finalVersion, finalAuthor, finalHeader, finalFooter, finalCopyright, templateName, sections)
+	htmlContent, err := generateHTML(docInfo.Title, docInfo.Subtitle, docInfo.Version, docInfo.Date.Format(dateFormat), docInfo.Author, docInfo.Header, docInfo.Footer, docInfo.Copyright, templateName, sections, lists, opts.InlineAssets || opts.Offline, opts.Offline, mermaid.client, log)
 	if err != nil {
 		return sections, fmt.Errorf("failed to generate HTML: %w", err)
 	}
 
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, htmlContent); err != nil {
+			return sections, fmt.Errorf("failed to write output: %w", err)
+		}
+		log.Successf("Generated HTML (%d KB)", len(htmlContent)/1024)
+		return sections, nil
+	}
+
 	if err := os.WriteFile(opts.OutputFile, []byte(htmlContent), 0644); err != nil {
 		return sections, fmt.Errorf("failed to write output: %w", err)
 	}
 
-	fmt.Printf("[SUCCESS] Generated HTML: %s\n", opts.OutputFile)
+	log.Successf("Generated HTML: %s", opts.OutputFile)
 	return sections, nil
 }
 
+// layoutAttributes는 섹션의 class, data-audience 속성을 반환한다(없으면 "").
+func layoutAttributes(s Section) string {
+	var attrs string
+	if classes := s.Classes(); classes != "" {
+		attrs += ` class="` + classes + `"`
+	}
+	if len(s.Audience) > 0 {
+		attrs += ` data-audience="` + gohtml.EscapeString(strings.Join(s.Audience, " ")) + `"`
+	}
+	return attrs
+}
+
+// Templates는 내장 템플릿 이름 목록을 반환한다 ("default"는 layout.html,
+// 그 밖의 이름은 layout_<이름>.html).
+func Templates() []string {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".html")
+		switch {
+		case name == "layout":
+			names = append(names, "default")
+		case strings.HasPrefix(name, "layout_"):
+			names = append(names, strings.TrimPrefix(name, "layout_"))
+		}
+	}
+	return names
+}
+
+// ResolveInfo는 설정 파일을 읽어 문서 메타데이터를 확정한다.
+// opts의 CLI 값이 설정 파일 값보다 우선한다.
+func ResolveInfo(opts Options) DocumentInfo {
+	log := logging.Use(opts.Logger)
+	return resolveInfo(opts, loadConfig(opts.ConfigFile, log), log)
+}
+
+func resolveInfo(opts Options, cfg AuthorsConfig, log logging.Printer) DocumentInfo {
+	info := DocumentInfo{
+		Title:     resolveValue(opts.Title, cfg.Document.Title, cfg.ProjectName, "Document"),
+		Subtitle:  resolveValue(opts.Subtitle, cfg.Document.Subtitle, "", ""),
+		Author:    resolveValue(opts.Author, cfg.Document.Author, cfg.Organization, ""),
+		Header:    resolveValue(opts.Header, cfg.Document.Header, "", ""),
+		Footer:    resolveValue(opts.Footer, cfg.Document.Footer, "", ""),
+		Copyright: resolveValue("", cfg.Copyright, cfg.Organization, ""),
+		Subject:   cfg.Document.Subject,
+		Keywords:  cfg.Document.Keywords,
+		Version:   opts.Version,
+		Date:      documentDate(cfg.Document.Date, opts.ConfigFile, log),
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+	return info
+}
+
+// documentDate는 문서 발행일을 정한다: 설정 파일의 document.date, 없으면
+// 재현 가능한 빌드를 위한 SOURCE_DATE_EPOCH(유닉스 시각, UTC), 둘 다 없으면
+// 현재 시각.
+func documentDate(date, configFile string, log logging.Printer) time.Time {
+	if date != "" {
+		if t, err := time.Parse("2006-01-02", date); err == nil {
+			return t
+		}
+		log.At(configFile, 0).Warnf("Invalid document date %q (want YYYY-MM-DD)", date)
+	}
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if sec, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC()
+		}
+		log.Warnf("Invalid SOURCE_DATE_EPOCH %q", epoch)
+	}
+	return time.Now()
+}
+
 // --- Helper functions (extracted from md2html_v2) ---
 
+func loadConfig(path string, log logging.Printer) AuthorsConfig {
+	var cfg AuthorsConfig
+	if path == "" {
+		return cfg
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.At(path, 0).Warnf("Config file not found")
+		return cfg
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		log.At(path, 0).Warnf("Failed to parse config file: %v", err)
+		return cfg
+	}
+	log.Infof("Loaded config: %s", path)
+	return cfg
+}
+
 func resolveValue(cliValue, configValue, fallback, defaultVal string) string {
 	if cliValue != "" {
 		return cliValue
@@ -268,7 +602,8 @@ func resolveValue(cliValue, configValue, fallback, defaultVal string) string {
 	return defaultVal
 }
 
-func applyPageNumbers(pagesJSONPath string, sections []Section) {
+// readPagesJSON은 analyzer가 쓴 ID -> 쪽 번호 맵을 읽는다.
+func readPagesJSON(pagesJSONPath string) (map[string]int, error) {
 	type PageInfo struct {
 		ID   string `json:"id"`
 		Page int    `json:"page"`
@@ -279,29 +614,37 @@ func applyPageNumbers(pagesJSONPath string, sections []Section) {
 
 	jsonData, err := os.ReadFile(pagesJSONPath)
 	if err != nil {
-		fmt.Printf("[WARN] Could not read pages JSON: %v\n", err)
-		return
+		return nil, fmt.Errorf("could not read pages JSON: %w", err)
 	}
 
 	var pagesData PagesData
 	if err := json.Unmarshal(jsonData, &pagesData); err != nil {
-		fmt.Printf("[WARN] Could not parse pages JSON: %v\n", err)
-		return
+		return nil, fmt.Errorf("could not parse pages JSON: %w", err)
 	}
 
 	pageMap := make(map[string]int)
 	for _, p := range pagesData.Sections {
 		pageMap[p.ID] = p.Page
 	}
+	return pageMap, nil
+}
+
+func applyPageNumbers(pageMap map[string]int, sections []Section, log logging.Printer) {
 	for i := range sections {
 		if page, ok := pageMap[sections[i].ID]; ok {
 			sections[i].PageNumber = page
-			fmt.Printf("[INFO] Section '%s' -> page %d\n", sections[i].Title, page)
+			log.Debugf("Section '%s' -> page %d", sections[i].Title, page)
 		}
 		for j := range sections[i].SubHeadings {
 			if page, ok := pageMap[sections[i].SubHeadings[j].ID]; ok {
 				sections[i].SubHeadings[j].PageNumber = page
-				fmt.Printf("[INFO]   SubHeading '%s' -> page %d\n", sections[i].SubHeadings[j].Title, page)
+				log.Debugf("  SubHeading '%s' -> page %d", sections[i].SubHeadings[j].Title, page)
+			}
+		}
+		for j := range sections[i].Labels {
+			if page, ok := pageMap[sections[i].Labels[j].ID]; ok {
+				sections[i].Labels[j].PageNumber = page
+				log.Debugf("  Label '%s' -> page %d", sections[i].Labels[j].ID, page)
 			}
 		}
 	}
@@ -343,33 +686,6 @@ func scanMarkdownFiles(dir string) ([]string, error) {
 	return files, err
 }
 
-func extractTitle(content string) (string, int) {
-	lines := strings.Split(content, "\n")
-	var secondChoice string
-	inCodeBlock := false
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "```") {
-			inCodeBlock = !inCodeBlock
-			continue
-		}
-		if inCodeBlock {
-			continue
-		}
-		if strings.HasPrefix(line, "# ") {
-			return strings.TrimPrefix(line, "# "), 1
-		}
-		if secondChoice == "" && strings.HasPrefix(line, "## ") {
-			secondChoice = strings.TrimPrefix(line, "## ")
-		}
-	}
-	if secondChoice != "" {
-		return secondChoice, 2
-	}
-	return "Untitled", 0
-}
-
 func generateID(filePath string) string {
 	base := filepath.Base(filePath)
 	base = strings.TrimSuffix(base, ".md")
@@ -377,168 +693,7 @@ func generateID(filePath string) string {
 	return strings.ToLower(base)
 }
 
-func extractSubHeadings(htmlContent string) []SubHeading {
-	var subHeadings []SubHeading
-	re := regexp.MustCompile(`<h2\s+id="([^"]+)"[^>]*>(.*?)</h2>`)
-	matches := re.FindAllStringSubmatch(htmlContent, -1)
-	stripTags := regexp.MustCompile(`<[^>]*>`)
-
-	for _, match := range matches {
-		if len(match) >= 3 {
-			rawTitle := match[2]
-			title := strings.TrimSpace(stripTags.ReplaceAllString(rawTitle, ""))
-			title = gohtml.UnescapeString(title)
-			if strings.HasPrefix(title, "Q.") || strings.HasPrefix(title, "Q ") {
-				continue
-			}
-			subHeadings = append(subHeadings, SubHeading{
-				Title: title,
-				ID:    match[1],
-				Level: 2,
-			})
-		}
-	}
-	return subHeadings
-}
-
-func convertMermaidBlocks(h string) string {
-	re := regexp.MustCompile(`(?s)<pre><code class="language-mermaid">(.*?)</code></pre>`)
-	return re.ReplaceAllString(h, `<div class="mermaid">$1</div>`)
-}
-
-func preprocessAlerts(content string) string {
-	lines := strings.Split(content, "\n")
-	var newLines []string
-	inAlert := false
-	inDocusaurus := false
-
-	docusaurusMap := map[string]string{
-		"note": "NOTE", "tip": "TIP", "info": "NOTE",
-		"warning": "WARNING", "danger": "CAUTION", "caution": "CAUTION",
-	}
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, ":::") && !strings.HasSuffix(trimmed, ":::") {
-			rest := strings.TrimPrefix(trimmed, ":::")
-			typePart := rest
-			title := ""
-			if idx := strings.Index(rest, "["); idx != -1 {
-				typePart = rest[:idx]
-				if endIdx := strings.Index(rest, "]"); endIdx != -1 {
-					title = rest[idx+1 : endIdx]
-				}
-			}
-			typePart = strings.ToLower(strings.TrimSpace(typePart))
-			if gfmType, ok := docusaurusMap[typePart]; ok {
-				inDocusaurus = true
-				if title != "" {
-					newLines = append(newLines, fmt.Sprintf("> [!%s] **%s**", gfmType, title))
-				} else {
-					newLines = append(newLines, fmt.Sprintf("> [!%s]", gfmType))
-				}
-				continue
-			}
-		}
-
-		if inDocusaurus && trimmed == ":::" {
-			inDocusaurus = false
-			newLines = append(newLines, "")
-			continue
-		}
-
-		if inDocusaurus {
-			if trimmed == "" {
-				newLines = append(newLines, ">")
-			} else {
-				newLines = append(newLines, "> "+line)
-			}
-			continue
-		}
-
-		isImportant := strings.HasPrefix(trimmed, "!> ")
-		isTip := strings.HasPrefix(trimmed, "?> ")
-
-		if isImportant || isTip {
-			inAlert = true
-			prefix := "> [!IMPORTANT] "
-			clean := strings.TrimPrefix(trimmed, "!> ")
-			if isTip {
-				prefix = "> [!TIP] "
-				clean = strings.TrimPrefix(trimmed, "?> ")
-			}
-			newLines = append(newLines, prefix+clean)
-		} else if inAlert {
-			if trimmed == "" {
-				inAlert = false
-				newLines = append(newLines, "")
-			} else {
-				newLines = append(newLines, "> "+line)
-			}
-		} else {
-			newLines = append(newLines, line)
-		}
-	}
-	return strings.Join(newLines, "\n")
-}
-
-func postProcessAlerts(htmlContent string) string {
-	alertTypes := []struct {
-		Tag, Class, Icon string
-	}{
-		{"NOTE", "alert-note", "fa-info-circle"},
-		{"TIP", "alert-tip", "fa-lightbulb"},
-		{"IMPORTANT", "alert-important", "fa-exclamation-circle"},
-		{"WARNING", "alert-warning", "fa-triangle-exclamation"},
-		{"CAUTION", "alert-caution", "fa-radiation"},
-	}
-
-	for _, at := range alertTypes {
-		pattern := fmt.Sprintf(`(?s)<blockquote>\s*<p>\s*\[!%s\]\s*(.*?)</p>(\s*.*?)</blockquote>`, at.Tag)
-		re := regexp.MustCompile(pattern)
-		replacement := fmt.Sprintf(`<div class="alert %s"><div class="alert-icon"><i class="fas %s"></i></div><div class="alert-content"><p>$1</p>$2</div></div>`, at.Class, at.Icon)
-		htmlContent = re.ReplaceAllString(htmlContent, replacement)
-	}
-
-	reTitleBody := regexp.MustCompile(`<p><strong>([^<]+)</strong>\s*:\s*(.+?)</p>`)
-	htmlContent = reTitleBody.ReplaceAllString(htmlContent, `<div class="alert-title">$1</div><p class="alert-body">$2</p>`)
-
-	return htmlContent
-}
-
-func preprocessHighlight(content string) string {
-	re := regexp.MustCompile(`==([^=]+)==`)
-	return re.ReplaceAllString(content, "<mark>$1</mark>")
-}
-
-func preprocessEmoji(content string) string {
-	emojiMap := map[string]string{
-		":+1:": "👍", ":-1:": "👎", ":heart:": "❤️", ":star:": "⭐",
-		":fire:": "🔥", ":rocket:": "🚀", ":sparkles:": "✨", ":eyes:": "👀",
-		":clap:": "👏", ":muscle:": "💪", ":pray:": "🙏", ":wave:": "👋",
-		":warning:": "⚠️", ":x:": "❌", ":white_check_mark:": "✅", ":heavy_check_mark:": "✔️",
-		":question:": "❓", ":exclamation:": "❗", ":bangbang:": "‼️",
-		":info:": "ℹ️", ":bulb:": "💡", ":memo:": "📝", ":book:": "📖",
-		":smile:": "😊", ":grin:": "😁", ":joy:": "😂", ":thinking:": "🤔",
-		":sunglasses:": "😎", ":sob:": "😭", ":confused:": "😕", ":rage:": "😡",
-		":bug:": "🐛", ":wrench:": "🔧", ":hammer:": "🔨", ":gear:": "⚙️",
-		":lock:": "🔒", ":key:": "🔑", ":package:": "📦", ":link:": "🔗",
-		":zap:": "⚡", ":construction:": "🚧", ":recycle:": "♻️", ":trash:": "🗑️",
-		":arrow_right:": "➡️", ":arrow_left:": "⬅️", ":arrow_up:": "⬆️", ":arrow_down:": "⬇️",
-		":point_right:": "👉", ":point_left:": "👈", ":point_up:": "👆", ":point_down:": "👇",
-	}
-
-	re := regexp.MustCompile(`:([a-z0-9_+-]+):`)
-	return re.ReplaceAllStringFunc(content, func(match string) string {
-		if emoji, ok := emojiMap[match]; ok {
-			return emoji
-		}
-		return match
-	})
-}
-
-func embedImages(htmlContent, mdFilePath string) string {
+func embedImages(htmlContent, mdFilePath, source string, log logging.Printer) string {
 	re := regexp.MustCompile(`<img[^>]+src="([^"]+)"[^>]*>`)
 	return re.ReplaceAllStringFunc(htmlContent, func(imgTag string) string {
 		subMatch := re.FindStringSubmatch(imgTag)
@@ -553,7 +708,7 @@ func embedImages(htmlContent, mdFilePath string) string {
 		imgPath := filepath.Join(dir, src)
 		data, err := os.ReadFile(imgPath)
 		if err != nil {
-			fmt.Printf("[WARN] Failed to read image for embedding: %s (%v)\n", imgPath, err)
+			log.At(mdFilePath, lineOf(source, src)).Warnf("Failed to read image for embedding: %s (%v)", imgPath, err)
 			return imgTag
 		}
 		mimeType := mime.TypeByExtension(filepath.Ext(imgPath))
@@ -566,7 +721,7 @@ func embedImages(htmlContent, mdFilePath string) string {
 	})
 }
 
-func embedStylesheets(htmlContent, mdFilePath string) string {
+func embedStylesheets(htmlContent, mdFilePath, source string, log logging.Printer) string {
 	re := regexp.MustCompile(`<link[^>]+rel="stylesheet"[^>]+href="([^"]+)"[^>]*>`)
 	return re.ReplaceAllStringFunc(htmlContent, func(linkTag string) string {
 		subMatch := re.FindStringSubmatch(linkTag)
@@ -581,14 +736,14 @@ func embedStylesheets(htmlContent, mdFilePath string) string {
 		cssPath := filepath.Join(dir, href)
 		data, err := os.ReadFile(cssPath)
 		if err != nil {
-			fmt.Printf("[WARN] Failed to read CSS for embedding: %s (%v)\n", cssPath, err)
+			log.At(mdFilePath, lineOf(source, href)).Warnf("Failed to read CSS for embedding: %s (%v)", cssPath, err)
 			return linkTag
 		}
 		return fmt.Sprintf("<style>\n%s\n</style>", string(data))
 	})
 }
 
-func processUIComponents(htmlContent, mdFilePath string) string {
+func processUIComponents(htmlContent, mdFilePath, source string, log logging.Printer) string {
 	re := regexp.MustCompile(`<!--\s*@ui:([a-zA-Z0-9_-]+)\s*-->`)
 	return re.ReplaceAllStringFunc(htmlContent, func(marker string) string {
 		subMatch := re.FindStringSubmatch(marker)
@@ -612,24 +767,34 @@ func processUIComponents(htmlContent, mdFilePath string) string {
 			curr = parent
 		}
 		if assetsDir == "" {
-			fmt.Printf("[WARN] UI Component not found: %s\n", componentName)
+			log.At(mdFilePath, lineOf(source, "@ui:"+componentName)).Warnf("UI Component not found: %s", componentName)
 			return marker
 		}
 		data, err := os.ReadFile(assetsDir)
 		if err != nil {
-			fmt.Printf("[WARN] Failed to read UI component file: %s (%v)\n", assetsDir, err)
+			log.At(mdFilePath, lineOf(source, "@ui:"+componentName)).Warnf("Failed to read UI component file: %s (%v)", assetsDir, err)
 			return marker
 		}
 		return string(data)
 	})
 }
 
+// lineOf는 Markdown 원본에서 needle이 처음 나오는 줄(1부터)을 반환하고,
+// 없으면 0을 반환한다.
+func lineOf(source, needle string) int {
+	idx := strings.Index(source, needle)
+	if idx < 0 {
+		return 0
+	}
+	return strings.Count(source[:idx], "\n") + 1
+}
+
 func rewriteAssetPaths(h string) string {
 	re := regexp.MustCompile(`src="(?:\.\./)+assets/`)
 	return re.ReplaceAllString(h, `src="assets/`)
 }
 
-func rewriteInternalLinks(htmlContent string) string {
+func rewriteInternalLinks(htmlContent string, ids map[string]string) string {
 	re := regexp.MustCompile(`href="\.?/?([^"]*\.md)(#[^"]*)?"`)
 	return re.ReplaceAllStringFunc(htmlContent, func(match string) string {
 		subMatch := re.FindStringSubmatch(match)
@@ -644,8 +809,7 @@ func rewriteInternalLinks(htmlContent string) string {
 		if anchor != "" {
 			return fmt.Sprintf(`href="%s"`, normalizeAnchor(anchor))
 		}
-		id := generateID(mdPath)
-		return fmt.Sprintf(`href="#%s"`, id)
+		return fmt.Sprintf(`href="#%s"`, fileSectionID(mdPath, ids))
 	})
 }
 
@@ -669,7 +833,7 @@ func normalizeAnchor(anchor string) string {
 	return "#" + normalized
 }
 
-func generateHTML(title, subtitle, version, author, header, footer, copyright, templateName string, sections []Section) (string, error) {
+func generateHTML(title, subtitle, version, date, author, header, footer, copyright, templateName string, sections []Section, lists figureLists, inline, offline, mermaidJS bool, log logging.Printer) (string, error) {
 	filename := "templates/layout.html"
 	if templateName != "default" && templateName != "" {
 		filename = fmt.Sprintf("templates/layout_%s.html", templateName)
@@ -680,8 +844,29 @@ func generateHTML(title, subtitle, version, author, header, footer, copyright, t
 		return "", err
 	}
 
+	// mermaid.js는 미리 렌더링하지 못한 다이어그램이 있을 때만 필요
+	if !mermaidJS {
+		tmplData = []byte(stripMermaidScript(string(tmplData)))
+	}
+
+	tmpl, err := inlineStyles(string(tmplData))
+	if err != nil {
+		return "", err
+	}
+	tmplData = []byte(tmpl)
+
+	// 내장 자산은 템플릿에만 인라인하고 사용자 콘텐츠에는 적용하지 않음
+	if inline {
+		inlined, err := inlineAssets(string(tmplData), offline, log)
+		if err != nil {
+			return "", err
+		}
+		tmplData = []byte(inlined)
+	}
+
 	funcMap := template.FuncMap{
-		"inc": func(i int) int { return i + 1 },
+		"inc":  func(i int) int { return i + 1 },
+		"join": strings.Join,
 		"slice": func(s string, start, end int) string {
 			if len(s) < start {
 				return s
@@ -714,12 +899,17 @@ func generateHTML(title, subtitle, version, author, header, footer, copyright, t
 		Title:     title,
 		Subtitle:  subtitle,
 		Version:   version,
-		Date:      time.Now().Format("2006년 01월 02일"),
+		Date:      date,
 		Author:    author,
 		Header:    header,
 		Footer:    footer,
 		Copyright: copyright,
 		Sections:  sections,
+
+		Figures:    lists.figures,
+		Tables:     lists.tables,
+		FigureName: lists.figureName,
+		TableName:  lists.tableName,
 	}
 
 	if err := t.Execute(&buf, data); err != nil {
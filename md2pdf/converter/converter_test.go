@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+// testBuild는 convertDocs로 만든 문서
+type testBuild struct {
+	dir      string // 입력 디렉터리
+	html     string
+	sections []Section
+	logs     []logging.Entry
+}
+
+// convertDocs는 files(이름 -> 내용)로 입력 디렉터리를 만들고 ConvertToHTML을
+// 실행한다. InputDir, Output, Logger는 이 함수가 채우고, 설정 파일
+// AUTHORS.yml이 files에 있으면 ConfigFile로 쓴다. Glossary, IndexTerms가
+// files의 이름이면 입력 디렉터리의 그 파일을 가리키도록 바꾼다. Mermaid
+// 캐시는 테스트마다 새 디렉터리를 쓴다.
+func convertDocs(t *testing.T, files map[string]string, opts Options) (*testBuild, error) {
+	t.Helper()
+	b := &testBuild{dir: t.TempDir()}
+	for name, content := range files {
+		path := filepath.Join(b.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := files["AUTHORS.yml"]; ok && opts.ConfigFile == "" {
+		opts.ConfigFile = filepath.Join(b.dir, "AUTHORS.yml")
+	}
+	for _, path := range []*string{&opts.Glossary, &opts.IndexTerms} {
+		if _, ok := files[*path]; ok && *path != "" {
+			*path = filepath.Join(b.dir, filepath.FromSlash(*path))
+		}
+	}
+	if opts.DiagramCache == "" {
+		opts.DiagramCache = t.TempDir()
+	}
+
+	var mu sync.Mutex
+	var out bytes.Buffer
+	opts.InputDir = b.dir
+	opts.Output = &out
+	opts.Logger = logging.LoggerFunc(func(e logging.Entry) {
+		mu.Lock()
+		b.logs = append(b.logs, e)
+		mu.Unlock()
+	})
+	sections, err := ConvertToHTML(opts)
+	b.html, b.sections = out.String(), sections
+	return b, err
+}
+
+// messages는 level 이상의 로그를 "파일:줄: 메시지" 형식으로 반환한다.
+// 파일은 입력 디렉터리 기준 상대 경로다.
+func (b *testBuild) messages(level logging.Level) []string {
+	var msgs []string
+	for _, e := range b.logs {
+		if e.Level < level {
+			continue
+		}
+		msg := e.Message
+		if e.File != "" {
+			file := e.File
+			if rel, err := filepath.Rel(b.dir, file); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+			msg = file + ":" + strconv.Itoa(e.Line) + ": " + msg
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// body는 출력 HTML에서 <body> 안쪽만 반환한다(템플릿 CSS 제외).
+func (b *testBuild) body() string {
+	html := b.html
+	if i := strings.Index(html, "<body"); i >= 0 {
+		html = html[i:]
+	}
+	return html
+}
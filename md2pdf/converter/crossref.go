@@ -0,0 +1,379 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	gohtml "html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"md2pdf/logging"
+)
+
+// 상호 참조: 그림, 표, 코드, 제목에 종류 접두사가 붙은 라벨을 달고
+// @종류:이름으로 참조한다:
+//
+//	![Architecture](arch.png){#fig:arch}
+//	Table: Limits {#tbl:limits}      표 바로 뒤 문단
+//	```go {#lst:main}
+//	## Installation {#sec:install}
+//
+//	See @fig:arch.                   -> "Figure 3" (PDF 모드: "Figure 3, p. 12")
+//
+// 그림, 표, 코드는 캡션과 같은 번호를 쓰고(figures.go 참고), 절은 제목
+// 번호를, 제목 번호가 없으면 제목을 쓴다.
+
+// Label은 라벨이 붙은 그림, 표, 코드 또는 제목
+type Label struct {
+	ID         string `json:"id"`
+	Kind       string `json:"kind"` // fig, tbl, lst, sec
+	Number     string `json:"number,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Referenced bool   `json:"referenced,omitempty"` // @참조가 있음 (PDF 링크 대상)
+	Listed     bool   `json:"listed,omitempty"`     // 그림/표 목차에 있음 (PDF 링크 대상)
+	PageNumber int    `json:"page,omitempty"`
+}
+
+var (
+	reLabel = regexp.MustCompile(`^(fig|tbl|lst|sec):[\p{L}\p{N}_]+(?:[.-][\p{L}\p{N}_]+)*$`)
+	reXref  = regexp.MustCompile(`^@((?:fig|tbl|lst|sec):[\p{L}\p{N}_]+(?:[.-][\p{L}\p{N}_]+)*)`)
+	// 렌더링된 참조. 모든 라벨을 안 뒤에 풀어 씀
+	reXrefLink = regexp.MustCompile(`<a class="xref" href="#([^"]+)">@[^<]*</a>`)
+)
+
+// crossrefExtension은 @참조, 라벨, 그림을 파싱한다.
+type crossrefExtension struct {
+	refs *crossrefs
+}
+
+func (e *crossrefExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&xrefParser{}, 600)),
+		parser.WithASTTransformers(util.Prioritized(&figureTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&crossrefRenderer{}, 500),
+		util.Prioritized(&figureRenderer{names: e.refs.names}, 500),
+	))
+}
+
+var kindXref = ast.NewNodeKind("Xref")
+
+// xref는 @종류:이름 참조
+type xref struct {
+	ast.BaseInline
+	id     string
+	offset int // 원본 위치 (오류 줄 번호용)
+}
+
+func (n *xref) Kind() ast.NodeKind { return kindXref }
+
+func (n *xref) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"ID": n.id}, nil)
+}
+
+type xrefParser struct{}
+
+func (p *xrefParser) Trigger() []byte { return []byte{'@'} }
+
+func (p *xrefParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	// 단어 중간은 제외 (예: 메일 주소)
+	if prev := block.PrecendingCharacter(); prev == '_' || unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	m := reXref.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	block.Advance(len(m[0]))
+	return &xref{id: string(m[1]), offset: segment.Start}
+}
+
+// parseLabel은 b 앞의 속성 블록({#종류:이름 ...})을 파싱해 속성과 길이를
+// 반환한다. id가 라벨이 아니면 ok는 false다.
+func parseLabel(b []byte) (parser.Attributes, int, bool) {
+	if len(b) == 0 || b[0] != '{' {
+		return nil, 0, false
+	}
+	r := text.NewReader(b)
+	attrs, ok := parser.ParseAttributes(r)
+	if !ok {
+		return nil, 0, false
+	}
+	id, ok := attrs.Find([]byte("id"))
+	if idb, isBytes := id.([]byte); !ok || !isBytes || !reLabel.Match(idb) {
+		return nil, 0, false
+	}
+	_, pos := r.Position()
+	return attrs, pos.Start, true
+}
+
+type crossrefRenderer struct{}
+
+func (r *crossrefRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindXref, r.renderXref)
+}
+
+func (r *crossrefRenderer) renderXref(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		id := gohtml.EscapeString(node.(*xref).id)
+		_, _ = w.WriteString(`<a class="xref" href="#` + id + `">@` + id + `</a>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// xrefUse는 파일 안의 @참조
+type xrefUse struct {
+	id, file string
+	line     int
+}
+
+// crossrefs는 모든 파일의 라벨과 그림에 번호를 붙이고 참조를 풀어 쓴다.
+type crossrefs struct {
+	names      map[string]string // 종류별 참조 문구
+	page       string            // 쪽 번호 앞 문구
+	perChapter bool              // 장마다 그림 번호를 새로 붙임
+	numbered   bool              // 제목 번호 사용 (장 번호 = 제목 번호)
+	listed     map[string]bool   // 앞부분에 목록이 있는 종류
+	chapters   int
+	chapter    string // 현재 장 번호
+	counters   map[string]int
+	unnumbered map[string]int // 번호 없는 장들이 함께 쓰는 카운터
+	labels     map[string]Label
+	uses       []xrefUse
+}
+
+func newCrossrefs(opts Options, cfg AuthorsConfig, log logging.Printer) *crossrefs {
+	switch opts.FigureNumbering {
+	case "", "global", "chapter":
+	default:
+		log.Warnf("Unknown figure numbering %q, using global", opts.FigureNumbering)
+	}
+	return &crossrefs{
+		names: map[string]string{
+			"fig": resolveValue("", cfg.CrossRef.Figure, "", "Figure"),
+			"tbl": resolveValue("", cfg.CrossRef.Table, "", "Table"),
+			"lst": resolveValue("", cfg.CrossRef.Listing, "", "Listing"),
+			"sec": resolveValue("", cfg.CrossRef.Section, "", "Section"),
+		},
+		page:       resolveValue("", cfg.CrossRef.Page, "", "p."),
+		perChapter: opts.FigureNumbering == "chapter",
+		numbered:   opts.NumberHeadings,
+		listed:     map[string]bool{"fig": opts.ListOfFigures, "tbl": opts.ListOfTables},
+		counters:   make(map[string]int),
+		unnumbered: make(map[string]int),
+		labels:     make(map[string]Label),
+	}
+}
+
+// startChapter는 장(자체 제목이 있는 섹션)을 시작한다. number는 장의 제목
+// 번호이고, 제목 번호를 쓰지 않으면 장 순서로 번호를 정한다.
+func (c *crossrefs) startChapter(number string) {
+	c.chapters++
+	if !c.perChapter {
+		return
+	}
+	// 제목 번호를 쓸 때 번호 없는 장(예: 머리말)의 그림은 장 번호 없이
+	// 이어서 번호를 붙인다. 장 순서를 쓰면 다음 장의 번호와 겹침
+	if number == "" && c.numbered {
+		c.chapter = ""
+		c.counters = c.unnumbered
+		return
+	}
+	c.chapter = number
+	if c.chapter == "" {
+		c.chapter = strconv.Itoa(c.chapters)
+	}
+	c.counters = make(map[string]int)
+}
+
+// next는 종류의 다음 번호를 반환한다.
+func (c *crossrefs) next(kind string) string {
+	c.counters[kind]++
+	if c.chapter != "" {
+		return c.chapter + "." + strconv.Itoa(c.counters[kind])
+	}
+	return strconv.Itoa(c.counters[kind])
+}
+
+// collect는 파일 하나의 라벨에 문서 순서대로 번호를 붙이고 참조를 기록한다.
+// 제목 번호를 붙인 뒤에 호출한다.
+func (c *crossrefs) collect(doc ast.Node, source []byte, file string, log logging.Printer) []Label {
+	var labels []Label
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if x, ok := n.(*xref); ok {
+			c.uses = append(c.uses, xrefUse{id: x.id, file: file, line: lineAtOffset(source, x.offset)})
+			return ast.WalkContinue, nil
+		}
+
+		var id string
+		if v, ok := n.AttributeString("id"); ok {
+			if idb, ok := v.([]byte); ok && reLabel.Match(idb) {
+				id = string(idb)
+			}
+		}
+		var label Label
+		switch n := n.(type) {
+		case *figure:
+			n.number = c.next(n.kind)
+			label = Label{ID: id, Kind: n.kind, Number: n.number, Title: captionText(n, source)}
+			if id == "" {
+				// 라벨 없는 그림에는 그림 목차용 ID를 붙임
+				label.ID = n.kind + "-" + strings.ReplaceAll(n.number, ".", "-")
+				n.SetAttributeString("id", []byte(label.ID))
+			}
+			label.Listed = label.Title != "" && c.listed[n.kind]
+			if id == "" && !label.Listed {
+				return ast.WalkContinue, nil
+			}
+		case *ast.Heading:
+			if id == "" {
+				return ast.WalkContinue, nil
+			}
+			title, number := headingTitle(n, source)
+			label = Label{ID: id, Kind: id[:strings.IndexByte(id, ':')], Number: number}
+			label.Title = strings.TrimSpace(strings.TrimPrefix(title, number))
+		default:
+			if id == "" {
+				return ast.WalkContinue, nil
+			}
+			label = Label{ID: id, Kind: id[:strings.IndexByte(id, ':')]}
+			label.Number = c.next(label.Kind)
+			if img, ok := n.(*ast.Image); ok {
+				label.Title = string(img.Text(source))
+			}
+		}
+		if _, dup := c.labels[label.ID]; dup {
+			log.At(file, labelLine(n, source)).Warnf("Duplicate label #%s", label.ID)
+			return ast.WalkContinue, nil
+		}
+		c.labels[label.ID] = label
+		labels = append(labels, label)
+		return ast.WalkContinue, nil
+	})
+	return labels
+}
+
+// labelLine은 라벨이 붙은 노드의 원본 줄 번호를 반환한다(모르면 0).
+func labelLine(n ast.Node, source []byte) int {
+	// figure는 변환기가 만든 블록이라 자체 줄 정보가 없으므로 내용에서 찾음
+	if fig, ok := n.(*figure); ok {
+		if offset := firstOffset(fig, source); offset >= 0 {
+			return lineAtOffset(source, offset)
+		}
+	}
+	for ; n != nil; n = n.Parent() {
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			return lineAtOffset(source, n.Lines().At(0).Start)
+		}
+		if txt, ok := n.NextSibling().(*ast.Text); ok {
+			return lineAtOffset(source, txt.Segment.Start)
+		}
+	}
+	return 0
+}
+
+// firstOffset은 n 안에서 처음 나오는 원본 위치를 반환한다(없으면 -1).
+func firstOffset(n ast.Node, source []byte) int {
+	switch n := n.(type) {
+	case *ast.Text:
+		return n.Segment.Start
+	case *ast.FencedCodeBlock:
+		// 라벨은 여는 펜스 줄에 있음
+		if n.Info != nil {
+			return n.Info.Segment.Start
+		}
+		if n.Lines().Len() > 0 {
+			return bytes.LastIndexByte(source[:n.Lines().At(0).Start-1], '\n') + 1
+		}
+	}
+	if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+		return n.Lines().At(0).Start
+	}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if offset := firstOffset(c, source); offset >= 0 {
+			return offset
+		}
+	}
+	return -1
+}
+
+// check는 없는 라벨 참조를 오류로 보고하고, 섹션의 라벨 중 참조된 것을
+// 표시한다.
+func (c *crossrefs) check(sections []Section, log logging.Printer) error {
+	referenced := make(map[string]bool)
+	unresolved := 0
+	for _, u := range c.uses {
+		if _, ok := c.labels[u.id]; !ok {
+			log.At(u.file, u.line).Errorf("Unresolved reference @%s", u.id)
+			unresolved++
+			continue
+		}
+		referenced[u.id] = true
+	}
+	for i := range sections {
+		for j := range sections[i].Labels {
+			sections[i].Labels[j].Referenced = referenced[sections[i].Labels[j].ID]
+		}
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("%d unresolved cross-references", unresolved)
+	}
+	return nil
+}
+
+// resolve는 htmlContent의 참조를 참조 문구로 바꾸고, withPages이고 쪽을
+// 알면 쪽 번호를 덧붙인다.
+func (c *crossrefs) resolve(htmlContent string, pages map[string]int, withPages bool) string {
+	return reXrefLink.ReplaceAllStringFunc(htmlContent, func(link string) string {
+		id := gohtml.UnescapeString(reXrefLink.FindStringSubmatch(link)[1])
+		label, ok := c.labels[id]
+		if !ok {
+			return link
+		}
+		ref := c.names[label.Kind] + " " + label.Number
+		if label.Kind == "sec" && label.Number == "" {
+			ref = label.Title
+		}
+		if page := pages[id]; withPages && page > 0 {
+			ref += fmt.Sprintf(", %s %d", c.page, page)
+		}
+		return `<a class="xref" href="#` + gohtml.EscapeString(id) + `">` + gohtml.EscapeString(ref) + `</a>`
+	})
+}
+
+// figureLists는 그림 목차와 표 목차
+type figureLists struct {
+	figures, tables       []Label
+	figureName, tableName string
+}
+
+// lists는 섹션에서 목록에 넣을 그림과 표를 쪽 번호와 함께 반환한다.
+func (c *crossrefs) lists(sections []Section) figureLists {
+	l := figureLists{figureName: c.names["fig"], tableName: c.names["tbl"]}
+	for _, s := range sections {
+		for _, label := range s.Labels {
+			switch {
+			case !label.Listed:
+			case label.Kind == "fig":
+				l.figures = append(l.figures, label)
+			case label.Kind == "tbl":
+				l.tables = append(l.tables, label)
+			}
+		}
+	}
+	return l
+}
@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+var reTestXref = regexp.MustCompile(`<a class="xref" href="#([^"]+)">([^<]*)</a>`)
+
+// xrefTexts는 섹션 본문의 상호 참조를 "라벨=참조 문구" 형식으로 순서대로
+// 반환한다.
+func xrefTexts(sections []Section) []string {
+	var refs []string
+	for _, s := range sections {
+		for _, m := range reTestXref.FindAllStringSubmatch(s.Content, -1) {
+			refs = append(refs, m[1]+"="+m[2])
+		}
+	}
+	return refs
+}
+
+func TestCrossRefs(t *testing.T) {
+	labeled := "# Intro\n\n## Install {#sec:install}\n\n![Arch](arch.png){#fig:arch}\n\n" +
+		"| a |\n|---|\n| 1 |\n\nTable: Limits {#tbl:limits}\n\n```go {#lst:main}\nx\n```\n\n" +
+		"See @fig:arch, @tbl:limits, @lst:main and @sec:install.\n"
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		want  []string
+	}{
+		{"kinds", map[string]string{"01-intro.md": labeled}, Options{},
+			[]string{"fig:arch=Figure 1", "tbl:limits=Table 1", "lst:main=Listing 1", "sec:install=Install"}},
+		// 제목 번호가 있으면 절 참조는 번호, 없으면 제목
+		{"numbered headings", map[string]string{"01-intro.md": labeled}, Options{NumberHeadings: true},
+			[]string{"fig:arch=Figure 1", "tbl:limits=Table 1", "lst:main=Listing 1", "sec:install=Section 1.1"}},
+		{"config names", map[string]string{
+			"AUTHORS.yml": "crossref:\n  figure: 그림\n  table: 표\n  listing: 코드\n",
+			"01-intro.md": labeled,
+		}, Options{}, []string{"fig:arch=그림 1", "tbl:limits=표 1", "lst:main=코드 1", "sec:install=Install"}},
+		// 번호는 파일에 걸쳐 이어지고, 뒤 파일의 라벨도 참조할 수 있음
+		{"across files", map[string]string{
+			"01-intro.md": "# Intro\n\nSee @fig:b.\n\n![A](a.png){#fig:a}\n",
+			"02-more.md":  "# More\n\n![B](b.png){#fig:b}\n\nSee @fig:a.\n",
+		}, Options{}, []string{"fig:b=Figure 2", "fig:a=Figure 1"}},
+		// 단어 중간의 @는 참조가 아님 (예: 메일 주소)
+		{"not in a word", map[string]string{
+			"01-intro.md": "# Intro\n\n![A](a.png){#fig:a}\n\nMail me@fig:a or (@fig:a).\n",
+		}, Options{}, []string{"fig:a=Figure 1"}},
+		// PDF 모드에서는 쪽 번호를 덧붙임
+		{"pages", map[string]string{"01-intro.md": labeled},
+			Options{PDFMode: true, Pages: map[string]int{"fig:arch": 12, "sec:install": 11}},
+			[]string{"fig:arch=Figure 1, p. 12", "tbl:limits=Table 1", "lst:main=Listing 1", "sec:install=Install, p. 11"}},
+		{"page prefix", map[string]string{
+			"AUTHORS.yml": "crossref:\n  page: 쪽\n",
+			"01-intro.md": "# Intro\n\n![A](a.png){#fig:a}\n\nSee @fig:a.\n",
+		}, Options{PDFMode: true, Pages: map[string]int{"fig:a": 3}}, []string{"fig:a=Figure 1, 쪽 3"}},
+		// 웹 HTML에는 쪽 번호를 넣지 않음
+		{"no pages in HTML", map[string]string{
+			"01-intro.md": "# Intro\n\n![A](a.png){#fig:a}\n\nSee @fig:a.\n",
+		}, Options{Pages: map[string]int{"fig:a": 3}}, []string{"fig:a=Figure 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := xrefTexts(b.sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("references:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrossRefLabels(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\n## Install {#sec:install}\n\n![Arch](arch.png){#fig:arch}\n\n![Flow](flow.png){#fig:flow}\n\nSee @fig:flow.\n",
+	}, Options{NumberHeadings: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Label{
+		{ID: "sec:install", Kind: "sec", Number: "1.1", Title: "Install"},
+		{ID: "fig:arch", Kind: "fig", Number: "1", Title: "Arch"},
+		{ID: "fig:flow", Kind: "fig", Number: "2", Title: "Flow", Referenced: true},
+	}
+	if !reflect.DeepEqual(b.sections[0].Labels, want) {
+		t.Errorf("labels:\n got %+v\nwant %+v", b.sections[0].Labels, want)
+	}
+}
+
+func TestCrossRefUnresolved(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+		want  []string
+	}{
+		{"missing label", map[string]string{
+			"01-intro.md": "# Intro\n\nSee @fig:missing.\n",
+		}, "1 unresolved cross-references", []string{"01-intro.md:3: Unresolved reference @fig:missing"}},
+		// 종류가 다르면 같은 이름이라도 다른 라벨
+		{"wrong kind", map[string]string{
+			"01-intro.md": "# Intro\n\n![A](a.png){#fig:arch}\n\nSee @tbl:arch.\n",
+		}, "1 unresolved cross-references", []string{"01-intro.md:5: Unresolved reference @tbl:arch"}},
+		{"several files", map[string]string{
+			"01-intro.md": "# Intro\n\nSee @fig:a.\n",
+			"02-more.md":  "# More\n\n- item\n- see @sec:b\n",
+		}, "2 unresolved cross-references", []string{
+			"01-intro.md:3: Unresolved reference @fig:a",
+			"02-more.md:4: Unresolved reference @sec:b",
+		}},
+		// 빌드에서 빠진 파일의 라벨은 참조할 수 없음
+		{"excluded file", map[string]string{
+			"01-intro.md": "# Intro\n\nSee @fig:a.\n",
+			"02-draft.md": "---\ndraft: true\n---\n# Draft\n\n![A](a.png){#fig:a}\n",
+		}, "1 unresolved cross-references", []string{"01-intro.md:3: Unresolved reference @fig:a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, Options{})
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("err = %v, want %q", err, tt.err)
+			}
+			if b.html != "" {
+				t.Error("HTML written despite unresolved references")
+			}
+			if got := b.messages(logging.Error); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrossRefDuplicateLabel(t *testing.T) {
+	tests := []struct {
+		name, dup, ref string
+		want           string // 경고
+	}{
+		{"figure", "# More\n\n![Again](b.png){#fig:a}\n", "fig:a=Figure 1", "02-more.md:3: Duplicate label #fig:a"},
+		// 표 라벨은 캡션 줄에 있음
+		{"table", "# More\n\n| b |\n|---|\n| 2 |\n\nTable: Again {#fig:a}\n", "fig:a=Figure 1", "02-more.md:7: Duplicate label #fig:a"},
+		{"listing", "# More\n\ntext\n\n```go {#fig:a}\nx\n```\n", "fig:a=Figure 1", "02-more.md:5: Duplicate label #fig:a"},
+		{"listing without language", "# More\n\n```{#fig:a}\nx\n```\n", "fig:a=Figure 1", "02-more.md:3: Duplicate label #fig:a"},
+		{"heading", "# More\n\n## Again {#fig:a}\n", "fig:a=Figure 1", "02-more.md:3: Duplicate label #fig:a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, map[string]string{
+				"01-intro.md": "# Intro\n\n![A](a.png){#fig:a}\n\nSee @fig:a.\n",
+				"02-more.md":  tt.dup,
+			}, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := b.messages(logging.Warn); !reflect.DeepEqual(got, []string{tt.want}) {
+				t.Errorf("warnings = %q, want %q", got, tt.want)
+			}
+			// 먼저 나온 라벨을 참조
+			if got := strings.Join(xrefTexts(b.sections), " "); got != tt.ref {
+				t.Errorf("references = %s, want %s", got, tt.ref)
+			}
+		})
+	}
+}
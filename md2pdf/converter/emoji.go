@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// emojiExtension은 :shortcode:를 이모지로 바꾼다. 모르는 단축 코드와 코드
+// 안의 단축 코드는 그대로 둔다.
+type emojiExtension struct{}
+
+func (e *emojiExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&emojiParser{}, 600)))
+}
+
+var emojiShortcodes = map[string]string{
+	":+1:": "👍", ":-1:": "👎", ":heart:": "❤️", ":star:": "⭐",
+	":fire:": "🔥", ":rocket:": "🚀", ":sparkles:": "✨", ":eyes:": "👀",
+	":clap:": "👏", ":muscle:": "💪", ":pray:": "🙏", ":wave:": "👋",
+	":warning:": "⚠️", ":x:": "❌", ":white_check_mark:": "✅", ":heavy_check_mark:": "✔️",
+	":question:": "❓", ":exclamation:": "❗", ":bangbang:": "‼️",
+	":info:": "ℹ️", ":bulb:": "💡", ":memo:": "📝", ":book:": "📖",
+	":smile:": "😊", ":grin:": "😁", ":joy:": "😂", ":thinking:": "🤔",
+	":sunglasses:": "😎", ":sob:": "😭", ":confused:": "😕", ":rage:": "😡",
+	":bug:": "🐛", ":wrench:": "🔧", ":hammer:": "🔨", ":gear:": "⚙️",
+	":lock:": "🔒", ":key:": "🔑", ":package:": "📦", ":link:": "🔗",
+	":zap:": "⚡", ":construction:": "🚧", ":recycle:": "♻️", ":trash:": "🗑️",
+	":arrow_right:": "➡️", ":arrow_left:": "⬅️", ":arrow_up:": "⬆️", ":arrow_down:": "⬇️",
+	":point_right:": "👉", ":point_left:": "👈", ":point_up:": "👆", ":point_down:": "👇",
+}
+
+type emojiParser struct{}
+
+func (p *emojiParser) Trigger() []byte { return []byte{':'} }
+
+func (p *emojiParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	for i := 1; i < len(line) && i <= 32; i++ {
+		c := line[i]
+		if c == ':' {
+			emoji, ok := emojiShortcodes[string(line[:i+1])]
+			if !ok {
+				return nil
+			}
+			block.Advance(i + 1)
+			return ast.NewString([]byte(emoji))
+		}
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '+' || c == '-') {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestEmoji(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"shortcode", "Hot :fire: item", "<p>Hot 🔥 item</p>\n"},
+		{"adjacent", ":+1::rocket:", "<p>👍🚀</p>\n"},
+		{"unknown", "a :unknown_code: b", "<p>a :unknown_code: b</p>\n"},
+		{"time", "at 10:30:00", "<p>at 10:30:00</p>\n"},
+		{"uppercase", ":FIRE:", "<p>:FIRE:</p>\n"},
+		// 코드 스팬과 코드 블록 안은 그대로
+		{"code span", "`:fire:` :fire:", "<p><code>:fire:</code> 🔥</p>\n"},
+		{"fenced code", "```\n:fire:\n```", "<pre><code>:fire:\n</code></pre>\n"},
+		{"indented code", "    :fire:", "<pre><code>:fire:</code></pre>\n"},
+	}
+	md := goldmark.New(goldmark.WithExtensions(&emojiExtension{}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := md.Convert([]byte(tt.in), &b); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
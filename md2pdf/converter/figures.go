@@ -0,0 +1,325 @@
+package converter
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 그림은 캡션과 함께 번호를 붙인다(라벨은 crossref.go 참고):
+//
+//	![Alt](arch.png "Caption")              제목이 있는 단독 이미지
+//	![Caption](arch.png){#fig:arch}         라벨이 있는 단독 이미지
+//
+//	| ... |                                 뒤에 캡션이 있는 표
+//
+//	Table: Caption {#tbl:limits}            문단 (또는 {#tbl:limits}만)
+//
+//	```go {#lst:main caption="Main loop"}   라벨이 있는 코드 블록
+//
+// 번호는 문서 전체(Figure 3) 또는 장별(Figure 2.1)이다(Options.FigureNumbering
+// 참고). 장별 번호에서 제목 번호를 쓸 때 번호 없는 장의 그림은 장 번호 없이
+// 이어서 번호를 붙인다. 제목이나 라벨이 없는 이미지는 그대로 둔다.
+
+var (
+	kindFigure  = ast.NewNodeKind("Figure")
+	kindCaption = ast.NewNodeKind("Caption")
+)
+
+// figure는 번호가 붙은 그림, 표 또는 코드. 자식은 내용과 (있으면) 캡션이다.
+type figure struct {
+	ast.BaseBlock
+	kind   string // fig, tbl, lst
+	number string // crossrefs.collect가 설정
+}
+
+func (n *figure) Kind() ast.NodeKind { return kindFigure }
+
+func (n *figure) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Kind": n.kind, "Number": n.number}, nil)
+}
+
+// caption은 그림 캡션의 인라인 노드를 담는다.
+type caption struct {
+	ast.BaseBlock
+}
+
+func (n *caption) Kind() ast.NodeKind { return kindCaption }
+
+func (n *caption) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var figureClasses = map[string]string{"fig": "figure", "tbl": "table-figure", "lst": "listing"}
+
+// figureTransformer는 {#종류:이름} 라벨을 앞 요소에 붙이고, 캡션이 있는
+// 이미지, 표, 코드 블록을 figure로 감싼다.
+type figureTransformer struct{}
+
+func (t *figureTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var images []*ast.Image
+	var tables []*east.Table
+	var codes []*ast.FencedCodeBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Image:
+			images = append(images, n)
+		case *east.Table:
+			tables = append(tables, n)
+		case *ast.FencedCodeBlock:
+			codes = append(codes, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, img := range images {
+		attrs, labeled := imageLabel(img, source)
+		para, ok := img.Parent().(*ast.Paragraph)
+		if !ok || para.ChildCount() != 1 || (!labeled && img.Title == nil) {
+			setAttributes(img, attrs)
+			continue
+		}
+		fig := &figure{kind: "fig"}
+		rest := takeID(fig, attrs)
+		setAttributes(img, rest)
+		text := img.Title
+		if len(text) == 0 {
+			text = img.Text(source)
+		}
+		para.Parent().ReplaceChild(para.Parent(), para, fig)
+		fig.AppendChild(fig, img)
+		if len(text) > 0 {
+			c := &caption{}
+			c.AppendChild(c, ast.NewString(text))
+			fig.AppendChild(fig, c)
+		}
+	}
+
+	for _, tbl := range tables {
+		para, ok := tbl.NextSibling().(*ast.Paragraph)
+		if !ok {
+			continue
+		}
+		attrs, c, ok := tableCaption(para, source)
+		if !ok {
+			continue
+		}
+		fig := &figure{kind: "tbl"}
+		setAttributes(tbl, takeID(fig, attrs))
+		para.Parent().RemoveChild(para.Parent(), para)
+		tbl.Parent().ReplaceChild(tbl.Parent(), tbl, fig)
+		if c != nil {
+			fig.AppendChild(fig, c)
+		}
+		fig.AppendChild(fig, tbl)
+	}
+
+	// ```lang {#lst:x caption="..."}
+	for _, fcb := range codes {
+		if fcb.Info == nil {
+			continue
+		}
+		info := fcb.Info.Segment.Value(source)
+		i := bytes.IndexByte(info, '{')
+		if i < 0 {
+			continue
+		}
+		attrs, _, ok := parseLabel(info[i:])
+		if !ok {
+			continue
+		}
+		if len(bytes.TrimSpace(info[:i])) == 0 {
+			fcb.Info = nil // 언어 없음
+		}
+		fig := &figure{kind: "lst"}
+		takeID(fig, attrs)
+		fcb.Parent().ReplaceChild(fcb.Parent(), fcb, fig)
+		if v, ok := attrs.Find([]byte("caption")); ok {
+			if text, ok := v.([]byte); ok && len(text) > 0 {
+				c := &caption{}
+				c.AppendChild(c, ast.NewString(text))
+				fig.AppendChild(fig, c)
+			}
+		}
+		fig.AppendChild(fig, fcb)
+	}
+}
+
+// imageLabel은 이미지 뒤의 {#fig:x} 라벨을 지우고 그 속성을 반환한다.
+func imageLabel(img *ast.Image, source []byte) (parser.Attributes, bool) {
+	txt, ok := img.NextSibling().(*ast.Text)
+	if !ok {
+		return nil, false
+	}
+	// 인라인 파서가 텍스트를 나눴을 수 있음 (예: ':'에서)
+	stop := txt.Segment.Stop
+	for t := txt; !t.SoftLineBreak() && !t.HardLineBreak(); {
+		next, ok := t.NextSibling().(*ast.Text)
+		if !ok || next.Segment.Start != stop {
+			break
+		}
+		t, stop = next, next.Segment.Stop
+	}
+	attrs, n, ok := parseLabel(source[txt.Segment.Start:stop])
+	if !ok {
+		return nil, false
+	}
+	cutText(img.Parent(), txt.Segment.Start, txt.Segment.Start+n)
+	return attrs, true
+}
+
+// tableCaption은 표 뒤 문단을 파싱한다: "Table: Caption {#tbl:x}"(라벨은
+// 선택) 또는 라벨만. 캡션이 없으면 c는 nil이다.
+func tableCaption(para *ast.Paragraph, source []byte) (attrs parser.Attributes, c *caption, ok bool) {
+	lines := para.Lines()
+	if lines.Len() == 0 {
+		return nil, nil, false
+	}
+	first, last := lines.At(0), lines.At(lines.Len()-1)
+
+	// 끝의 라벨
+	labelStart := -1
+	lastLine := util.TrimRightSpace(last.Value(source))
+	if i := bytes.LastIndexByte(lastLine, '{'); i >= 0 {
+		if a, n, found := parseLabel(lastLine[i:]); found && i+n == len(lastLine) {
+			attrs, labelStart = a, last.Start+i
+		}
+	}
+
+	prefix := []byte("Table:")
+	if !bytes.HasPrefix(first.Value(source), prefix) {
+		if labelStart == first.Start && lines.Len() == 1 {
+			return attrs, nil, true
+		}
+		return nil, nil, false
+	}
+
+	if labelStart >= 0 {
+		for labelStart > first.Start && (source[labelStart-1] == ' ' || source[labelStart-1] == '\t') {
+			labelStart--
+		}
+		cutText(para, labelStart, last.Start+len(lastLine))
+	}
+	end := first.Start + len(prefix)
+	for end < first.Stop && (source[end] == ' ' || source[end] == '\t') {
+		end++
+	}
+	cutText(para, first.Start, end)
+
+	c = &caption{}
+	for n := para.FirstChild(); n != nil; {
+		next := n.NextSibling()
+		c.AppendChild(c, n)
+		n = next
+	}
+	return attrs, c, true
+}
+
+// cutText는 parent의 Text 자식에서 source[start:stop]을 지운다.
+func cutText(parent ast.Node, start, stop int) {
+	for c := parent.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		t, ok := c.(*ast.Text)
+		if !ok || t.Segment.Stop <= start || t.Segment.Start >= stop {
+			c = next
+			continue
+		}
+		seg := t.Segment
+		switch {
+		case seg.Start < start && seg.Stop > stop:
+			tail := ast.NewTextSegment(seg.WithStart(stop))
+			tail.SetSoftLineBreak(t.SoftLineBreak())
+			tail.SetHardLineBreak(t.HardLineBreak())
+			t.SetSoftLineBreak(false)
+			t.SetHardLineBreak(false)
+			t.Segment = seg.WithStop(start)
+			parent.InsertAfter(parent, t, tail)
+		case seg.Start < start:
+			t.Segment = seg.WithStop(start)
+		case seg.Stop > stop:
+			t.Segment = seg.WithStart(stop)
+		case t.SoftLineBreak() || t.HardLineBreak():
+			t.Segment = seg.WithStop(seg.Start)
+		default:
+			parent.RemoveChild(parent, t)
+		}
+		c = next
+	}
+}
+
+// takeID는 id 속성을 fig로 옮기고(그림 종류는 라벨에서 정함) 나머지 속성을
+// 반환한다.
+func takeID(fig *figure, attrs parser.Attributes) parser.Attributes {
+	var rest parser.Attributes
+	for _, a := range attrs {
+		if string(a.Name) != "id" {
+			rest = append(rest, a)
+			continue
+		}
+		fig.SetAttribute(a.Name, a.Value)
+		if id, ok := a.Value.([]byte); ok {
+			if kind := string(id[:bytes.IndexByte(id, ':')]); figureClasses[kind] != "" {
+				fig.kind = kind
+			}
+		}
+	}
+	return rest
+}
+
+func setAttributes(n ast.Node, attrs parser.Attributes) {
+	for _, a := range attrs {
+		n.SetAttribute(a.Name, a.Value)
+	}
+}
+
+// captionText는 그림 캡션의 텍스트를 반환한다(없으면 "").
+func captionText(fig *figure, source []byte) string {
+	for c := fig.FirstChild(); c != nil; c = c.NextSibling() {
+		if c, ok := c.(*caption); ok {
+			return string(c.Text(source))
+		}
+	}
+	return ""
+}
+
+type figureRenderer struct {
+	names map[string]string // 종류별 캡션 이름
+}
+
+func (r *figureRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindFigure, r.renderFigure)
+	reg.Register(kindCaption, r.renderCaption)
+}
+
+func (r *figureRenderer) renderFigure(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<figure class="` + figureClasses[node.(*figure).kind] + `"`)
+		html.RenderAttributes(w, node, html.GlobalAttributeFilter)
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</figure>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *figureRenderer) renderCaption(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		fig := node.Parent().(*figure)
+		_, _ = w.WriteString(`<figcaption><span class="caption-label">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(r.names[fig.kind] + " " + fig.number + ".")))
+		_, _ = w.WriteString(`</span> `)
+	} else {
+		_, _ = w.WriteString("</figcaption>\n")
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,172 @@
+package converter
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+var (
+	reTestFigure  = regexp.MustCompile(`(?s)<figure class="[^"]+" id="([^"]+)">(.*?)</figure>`)
+	reTestCaption = regexp.MustCompile(`<span class="caption-label">([^<]*)</span>`)
+)
+
+// figureNumbers는 섹션 본문의 그림을 "ID=캡션 번호" 형식으로 순서대로
+// 반환한다. 캡션이 없는 그림은 ID만 반환한다.
+func figureNumbers(sections []Section) []string {
+	var figures []string
+	for _, s := range sections {
+		for _, m := range reTestFigure.FindAllStringSubmatch(s.Content, -1) {
+			figure := m[1]
+			if c := reTestCaption.FindStringSubmatch(m[2]); c != nil {
+				figure += "=" + c[1]
+			}
+			figures = append(figures, figure)
+		}
+	}
+	return figures
+}
+
+func TestFigureNumbering(t *testing.T) {
+	chapters := map[string]string{
+		"01-intro.md": "# Intro\n\n![A](a.png \"Cap A\")\n\n| a |\n|---|\n| 1 |\n\nTable: Limits\n\n![B](b.png \"Cap B\")\n",
+		"02-usage.md": "# Usage\n\n![C](c.png){#fig:c}\n\n```go {#lst:main caption=\"Main\"}\nx\n```\n",
+	}
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		want  []string
+	}{
+		// 종류마다 문서 전체에 걸쳐 번호
+		{"global", chapters, Options{},
+			[]string{"fig-1=Figure 1.", "tbl-1=Table 1.", "fig-2=Figure 2.", "fig:c=Figure 3.", "lst:main=Listing 1."}},
+		{"chapter", chapters, Options{FigureNumbering: "chapter"},
+			[]string{"fig-1-1=Figure 1.1.", "tbl-1-1=Table 1.1.", "fig-1-2=Figure 1.2.", "fig:c=Figure 2.1.", "lst:main=Listing 2.1."}},
+		// 제목 번호를 쓰면 장 번호는 제목 번호 (부록은 문자)
+		{"chapter with heading numbers", map[string]string{
+			"01-intro.md": "# Intro\n\n![A](a.png \"Cap A\")\n",
+			"02-app.md":   "# Glossary {.appendix}\n\n![B](b.png \"Cap B\")\n\n![C](c.png \"Cap C\")\n",
+		}, Options{FigureNumbering: "chapter", NumberHeadings: true},
+			[]string{"fig-1-1=Figure 1.1.", "fig-A-1=Figure A.1.", "fig-A-2=Figure A.2."}},
+		// 번호 없는 장의 그림은 장 번호 없이 이어서 번호를 붙여 다른 장과 겹치지 않음
+		{"unnumbered chapters", map[string]string{
+			"01-pre.md":   "# Preface {.unnumbered}\n\n![A](a.png \"Cap A\")\n",
+			"02-intro.md": "# Intro\n\n![B](b.png \"Cap B\")\n",
+			"03-post.md":  "# Afterword {.unnumbered}\n\n![C](c.png \"Cap C\")\n",
+		}, Options{FigureNumbering: "chapter", NumberHeadings: true},
+			[]string{"fig-1=Figure 1.", "fig-1-1=Figure 1.1.", "fig-2=Figure 2."}},
+		// H2만 있는 파일은 앞 장에 합쳐지므로 번호가 이어짐
+		{"merged file", map[string]string{
+			"01-intro.md": "# Intro\n\n![A](a.png \"Cap A\")\n",
+			"02-more.md":  "## More\n\n![B](b.png \"Cap B\")\n",
+			"03-usage.md": "# Usage\n\n![C](c.png \"Cap C\")\n",
+		}, Options{FigureNumbering: "chapter"},
+			[]string{"fig-1-1=Figure 1.1.", "fig-1-2=Figure 1.2.", "fig-2-1=Figure 2.1."}},
+		{"config names", map[string]string{
+			"AUTHORS.yml": "crossref:\n  figure: 그림\n  table: 표\n",
+			"01-intro.md": chapters["01-intro.md"],
+		}, Options{}, []string{"fig-1=그림 1.", "tbl-1=표 1.", "fig-2=그림 2."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := figureNumbers(b.sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("figures:\n got %q\nwant %q", got, tt.want)
+			}
+			if warnings := b.messages(logging.Warn); len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %q", warnings)
+			}
+		})
+	}
+}
+
+func TestFigureNumberingUnknown(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\n![A](a.png \"Cap A\")\n",
+		"02-usage.md": "# Usage\n\n![B](b.png \"Cap B\")\n",
+	}, Options{FigureNumbering: "part"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := figureNumbers(b.sections), []string{"fig-1=Figure 1.", "fig-2=Figure 2."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("figures = %q, want %q", got, want)
+	}
+	want := []string{`Unknown figure numbering "part", using global`}
+	if got := b.messages(logging.Warn); !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %q, want %q", got, want)
+	}
+}
+
+func TestFigureCaptions(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string // 본문에 있어야 하는 HTML
+	}{
+		{"image title", `![Alt](a.png "Caption")`,
+			`<figure class="figure" id="fig-1">` + "\n" + `<img src="a.png" alt="Alt" title="Caption"><figcaption><span class="caption-label">Figure 1.</span> Caption</figcaption>` + "\n</figure>"},
+		{"labeled image", `![Caption](a.png){#fig:a}`,
+			`<figure class="figure" id="fig:a">` + "\n" + `<img src="a.png" alt="Caption"><figcaption><span class="caption-label">Figure 1.</span> Caption</figcaption>`},
+		// 제목과 라벨이 없는 이미지, 문단 안의 이미지는 그림이 아님
+		{"plain image", `![Alt](a.png)`, `<p><img src="a.png" alt="Alt"></p>`},
+		{"inline image", `Text ![Alt](a.png "Caption") text`, `<p>Text <img src="a.png" alt="Alt" title="Caption"> text</p>`},
+		{"table caption", "| a |\n|---|\n| 1 |\n\nTable: Limits {#tbl:limits}",
+			`<figure class="table-figure" id="tbl:limits">` + "\n" + `<figcaption><span class="caption-label">Table 1.</span> Limits</figcaption>` + "\n<table>"},
+		{"table label only", "| a |\n|---|\n| 1 |\n\n{#tbl:limits}",
+			`<figure class="table-figure" id="tbl:limits">` + "\n<table>"},
+		{"listing caption", "```go {#lst:main caption=\"Main loop\"}\nx\n```",
+			`<figure class="listing" id="lst:main">` + "\n" + `<figcaption><span class="caption-label">Listing 1.</span> Main loop</figcaption>` + "\n" + `<pre><code class="language-go">x`},
+		{"listing without language", "```{#lst:main}\nx\n```",
+			`<figure class="listing" id="lst:main">` + "\n<pre><code>x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, map[string]string{"01-intro.md": "# Intro\n\n" + tt.in + "\n"}, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(b.sections[0].Content, tt.want) {
+				t.Errorf("missing %q in:\n%s", tt.want, b.sections[0].Content)
+			}
+		})
+	}
+}
+
+func TestListOfFigures(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\n![A](a.png \"Cap A\")\n\n![B](b.png){#fig:b}\n\n| a |\n|---|\n| 1 |\n\nTable: Limits\n\n| b |\n|---|\n| 2 |\n\n{#tbl:bare}\n",
+	}, Options{Template: "modern", ListOfFigures: true, ListOfTables: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 캡션이 있는 그림과 표만 목록에 들어감
+	var listed []string
+	for _, l := range b.sections[0].Labels {
+		if l.Listed {
+			listed = append(listed, l.ID)
+		}
+	}
+	if want := []string{"fig-1", "fig:b", "tbl-1"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("listed = %q, want %q", listed, want)
+	}
+	body := b.body()
+	for _, want := range []string{
+		`<a href="#fig-1">Figure 1. Cap A</a>`,
+		`<a href="#fig:b">Figure 2. B</a>`,
+		`<a href="#tbl-1">Table 1. Limits</a>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("list entry %q missing", want)
+		}
+	}
+	if strings.Contains(body, `<a href="#tbl:bare">`) {
+		t.Error("table without caption listed")
+	}
+}
@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"md2pdf/logging"
+)
+
+// frontMatter는 Markdown 파일 맨 앞의 YAML 블록:
+//
+//	---
+//	title: 설치 안내      # 섹션 제목 (기본값: 첫 제목)
+//	id: install           # 섹션 ID (기본값: 파일 이름)
+//	order: 10             # order가 있는 파일이 먼저, 오름차순
+//	draft: true           # Options.Drafts가 아니면 제외
+//	exclude: true         # 항상 제외
+//	toc: false            # 목차에서 제외
+//	pagebreak: false      # 새 쪽에서 시작(true) 또는 이어서(false)
+//	landscape: true       # 가로 쪽
+//	audience: [admin]     # 템플릿용 (data-audience)
+//	only: [admin, pdf]    # 빌드 조건 (conditions.go 참고)
+//	---
+type frontMatter struct {
+	Title     string   `yaml:"title"`
+	ID        string   `yaml:"id"`
+	Order     int      `yaml:"order"`
+	Draft     bool     `yaml:"draft"`
+	Exclude   bool     `yaml:"exclude"`
+	TOC       *bool    `yaml:"toc"` // false: 파일을 목차에서 제외
+	PageBreak *bool    `yaml:"pagebreak"`
+	Landscape bool     `yaml:"landscape"`
+	Audience  yamlList `yaml:"audience"`
+	Only      yamlList `yaml:"only"`
+}
+
+// yamlList는 값 하나로도 쓸 수 있는 YAML 목록이다.
+type yamlList []string
+
+func (l *yamlList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = yamlList{node.Value}
+		return nil
+	}
+	return node.Decode((*[]string)(l))
+}
+
+// sourceFile은 Markdown 파일과 그 front matter다.
+type sourceFile struct {
+	path    string
+	content []byte // front matter를 뺀 내용
+	fm      frontMatter
+}
+
+// sortSources는 order가 있는 파일을 오름차순으로 먼저, 나머지 파일은 원래
+// 순서대로 놓는다.
+func sortSources(sources []sourceFile) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		a, b := sources[i].fm.Order, sources[j].fm.Order
+		if (a == 0) != (b == 0) {
+			return b == 0
+		}
+		return a < b
+	})
+}
+
+// sectionIDs는 파일 ID별 섹션 ID를 반환하고, 중복된 ID는 경고한다.
+func sectionIDs(sources []sourceFile, log logging.Printer) map[string]string {
+	ids := make(map[string]string)
+	seen := make(map[string]string)
+	for _, src := range sources {
+		id := generateID(src.path)
+		if src.fm.ID != "" {
+			ids[id] = src.fm.ID
+			id = src.fm.ID
+		}
+		if other, dup := seen[id]; dup {
+			log.At(src.path, 0).Warnf("Duplicate section ID %q (also %s)", id, filepath.Base(other))
+		}
+		seen[id] = src.path
+	}
+	return ids
+}
+
+// fileSectionID는 파일의 섹션 ID를 반환한다.
+func fileSectionID(path string, ids map[string]string) string {
+	id := generateID(path)
+	if custom, ok := ids[id]; ok {
+		return custom
+	}
+	return id
+}
+
+// parseFrontMatter는 파일의 front matter를 읽고, 줄 번호가 바뀌지 않도록
+// 블록을 빈 줄로 바꾼 내용을 반환한다. YAML 매핑이 아닌 블록은 Markdown으로
+// 그대로 둔다.
+func parseFrontMatter(file string, content []byte, log logging.Printer) (frontMatter, []byte) {
+	var fm frontMatter
+	if !bytes.HasPrefix(content, []byte("---")) {
+		return fm, content
+	}
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if string(bytes.TrimSpace(lines[0])) != "---" {
+		return fm, content
+	}
+	for i := 1; i < len(lines); i++ {
+		if l := string(bytes.TrimSpace(lines[i])); l != "---" && l != "..." {
+			continue
+		}
+		if err := yaml.Unmarshal(bytes.Join(lines[1:i], nil), &fm); err != nil {
+			log.At(file, 1).Warnf("Ignoring invalid front matter: %v", err)
+			return frontMatter{}, content
+		}
+		body := bytes.Repeat([]byte("\n"), i+1)
+		return fm, append(body, bytes.Join(lines[i+1:], nil)...)
+	}
+	return fm, content
+}
@@ -0,0 +1,216 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+// sectionTitles는 섹션을 "ID=제목" 형식으로 순서대로 반환한다.
+func sectionTitles(sections []Section) []string {
+	var titles []string
+	for _, s := range sections {
+		titles = append(titles, s.ID+"="+s.Title)
+	}
+	return titles
+}
+
+func TestFrontMatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		want  []string
+		info  string // 건너뛴 파일 로그
+	}{
+		{"none", map[string]string{
+			"01-intro.md": "# Intro\n",
+			"02-setup.md": "# Setup\n",
+		}, Options{}, []string{"01-intro=Intro", "02-setup=Setup"}, ""},
+		{"title and id", map[string]string{
+			"01-intro.md": "---\ntitle: Introduction\nid: intro\n---\n# Intro\n",
+		}, Options{}, []string{"intro=Introduction"}, ""},
+		// 제목 번호는 그대로 두고 제목만 바꿈
+		{"numbered title", map[string]string{
+			"01-intro.md": "# Intro\n",
+			"02-setup.md": "---\ntitle: 설치 안내\n---\n# Setup\n",
+		}, Options{NumberHeadings: true}, []string{"01-intro=1 Intro", "02-setup=2 설치 안내"}, ""},
+		// order가 있는 파일이 먼저 오름차순, 나머지는 원래 순서
+		{"order", map[string]string{
+			"01-a.md": "# A\n",
+			"02-b.md": "---\norder: 20\n---\n# B\n",
+			"03-c.md": "# C\n",
+			"04-d.md": "---\norder: 10\n---\n# D\n",
+		}, Options{}, []string{"04-d=D", "02-b=B", "01-a=A", "03-c=C"}, ""},
+		{"draft", map[string]string{
+			"01-intro.md": "# Intro\n",
+			"02-draft.md": "---\ndraft: true\n---\n# Draft\n",
+		}, Options{}, []string{"01-intro=Intro"}, "Skipping 02-draft.md (draft)"},
+		{"draft with Drafts", map[string]string{
+			"01-intro.md": "# Intro\n",
+			"02-draft.md": "---\ndraft: true\n---\n# Draft\n",
+		}, Options{Drafts: true}, []string{"01-intro=Intro", "02-draft=Draft"}, ""},
+		// exclude는 Drafts와 관계없이 제외
+		{"exclude", map[string]string{
+			"01-intro.md": "# Intro\n",
+			"02-old.md":   "---\nexclude: true\ndraft: true\n---\n# Old\n",
+		}, Options{Drafts: true}, []string{"01-intro=Intro"}, "Skipping 02-old.md (excluded)"},
+		// 닫는 줄이 없거나 "---" 뒤에 글자가 있으면 front matter가 아님
+		{"not front matter", map[string]string{
+			"01-intro.md": "---\ntitle: Other\n\n# Intro\n",
+			"02-setup.md": "--- title: Other\n# Setup\n",
+		}, Options{}, []string{"01-intro=Intro", "02-setup=Setup"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sectionTitles(b.sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sections:\n got %q\nwant %q", got, tt.want)
+			}
+			if warnings := b.messages(logging.Warn); len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %q", warnings)
+			}
+			info := strings.Join(b.messages(logging.Info), "\n")
+			if tt.info != "" && !strings.Contains(info, tt.info) {
+				t.Errorf("missing %q in:\n%s", tt.info, info)
+			}
+		})
+	}
+}
+
+func TestFrontMatterBody(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "---\ntitle: Introduction\n...\n# Intro\n\nSee @fig:missing.\n",
+	}, Options{})
+	if err == nil {
+		t.Fatal("unresolved reference not reported")
+	}
+	// 블록은 본문에서 빠지지만 줄 번호는 원본 그대로
+	want := []string{"01-intro.md:6: Unresolved reference @fig:missing"}
+	if got := b.messages(logging.Error); !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %q, want %q", got, want)
+	}
+
+	b, err = convertDocs(t, map[string]string{
+		"01-intro.md": "---\ntitle: Introduction\n---\n# Intro\n\nText.\n",
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := b.sections[0].Content; strings.Contains(content, "title:") || strings.Contains(content, "<hr>") {
+		t.Errorf("front matter left in the body:\n%s", content)
+	}
+}
+
+func TestFrontMatterInvalid(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "---\ntitle: [unclosed\n---\n# Intro\n",
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 경고하고 블록을 Markdown으로 둠
+	warnings := b.messages(logging.Warn)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "01-intro.md:1: Ignoring invalid front matter: ") {
+		t.Errorf("warnings = %q", warnings)
+	}
+	if got := sectionTitles(b.sections); !reflect.DeepEqual(got, []string{"01-intro=Intro"}) {
+		t.Errorf("sections = %q", got)
+	}
+}
+
+func TestFrontMatterID(t *testing.T) {
+	files := map[string]string{
+		"01-intro.md":   "# Intro\n\nSee [install](02-install.md) and [keys](02-install.md#keys).\n",
+		"02-install.md": "---\nid: install\n---\n# Install\n\n## Keys {#keys}\n",
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want []string // 01-intro.md의 링크
+	}{
+		// PDF에서는 파일 링크가 새 ID를 가리킴
+		{"pdf", Options{PDFMode: true}, []string{`href="#install"`, `href="#keys"`}},
+		{"html", Options{}, []string{`href="02-install.md"`, `href="02-install.md#keys"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, files, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sectionTitles(b.sections); !reflect.DeepEqual(got, []string{"01-intro=Intro", "install=Install"}) {
+				t.Errorf("sections = %q", got)
+			}
+			for _, link := range tt.want {
+				if !strings.Contains(b.sections[0].Content, link) {
+					t.Errorf("missing %s in:\n%s", link, b.sections[0].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestFrontMatterDuplicateID(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n",
+		"02-setup.md": "---\nid: 01-intro\n---\n# Setup\n",
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`02-setup.md:0: Duplicate section ID "01-intro" (also 01-intro.md)`}
+	if got := b.messages(logging.Warn); !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestFrontMatterLayout(t *testing.T) {
+	files := map[string]string{
+		"01-intro.md": "---\naudience: admin\n---\n# Intro\n",
+		"02-wide.md":  "---\nlandscape: true\npagebreak: true\naudience: [admin, ops]\n---\n## Wide table\n",
+		"03-ref.md":   "---\nlandscape: true\npagebreak: false\n---\n# Reference\n",
+		"04-plain.md": "# Plain\n",
+	}
+	tests := []struct {
+		template, class string // 섹션 요소의 클래스
+	}{
+		{"default", "section"},
+		{"modern", "page"},
+		{"report", "section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			b, err := convertDocs(t, files, Options{Template: tt.template})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, s := range b.sections {
+				got = append(got, s.ID+"="+s.Classes()+"/"+strings.Join(s.Audience, " "))
+			}
+			if want := []string{"01-intro=/admin", "03-ref=no-page-break landscape/", "04-plain=/"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("layout:\n got %q\nwant %q", got, want)
+			}
+			// 합쳐진 파일의 레이아웃은 그 부분에만 적용
+			if want := `<div id="02-wide" class="page-break landscape" data-audience="admin ops">`; !strings.Contains(b.sections[0].Content, want) {
+				t.Errorf("missing %s in:\n%s", want, b.sections[0].Content)
+			}
+			body := b.body()
+			for _, want := range []string{
+				`id="01-intro" data-audience="admin">`,
+				`<div class="` + tt.class + ` no-page-break landscape" id="03-ref">`,
+				`<div class="` + tt.class + `" id="04-plain">`,
+			} {
+				if !strings.Contains(body, want) {
+					t.Errorf("missing %s in the page", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	gohtml "html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"gopkg.in/yaml.v3"
+
+	"md2pdf/logging"
+)
+
+// 용어집(Options.Glossary). 원본은 YAML 또는 Markdown 정의 목록이고, 약어는
+// Kramdown 문법으로 쓴다:
+//
+//	# glossary.yml                          # glossary.md
+//	인증서: 공개 키와 소유자를 묶은 문서       인증서
+//	TLS:                                    : 공개 키와 소유자를 묶은 문서
+//	  abbr: Transport Layer Security
+//	  definition: 전송 계층 암호화 규약       *[TLS]: Transport Layer Security
+//
+// 장마다 처음 나온 용어는 정렬된 용어집 부록의 항목으로 링크하고, 약어는
+// 모든 곳에서 <abbr title>로 감싼다. 라틴 문자 용어는 단어 단위로 대소문자를
+// 무시하고 찾는다(약어는 대소문자 구분).
+
+const (
+	glossaryID    = "book-glossary"
+	glossaryTitle = "용어집"
+)
+
+var kindGlossaryRef = ast.NewNodeKind("GlossaryRef")
+
+// glossaryRef는 용어 사용 하나다. 자식은 용어 텍스트다.
+type glossaryRef struct {
+	ast.BaseInline
+	entry *glossaryEntry
+	link  bool // 장에서 처음 사용
+}
+
+func (n *glossaryRef) Kind() ast.NodeKind { return kindGlossaryRef }
+
+func (n *glossaryRef) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Term": n.entry.term}, nil)
+}
+
+// glossaryExtension은 glossary.link가 표시한 용어 사용을 렌더링한다.
+type glossaryExtension struct{}
+
+func (e *glossaryExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&glossaryRenderer{}, 500)))
+}
+
+type glossaryRenderer struct{}
+
+func (r *glossaryRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindGlossaryRef, r.render)
+}
+
+func (r *glossaryRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	ref := node.(*glossaryRef)
+	e := ref.entry
+	if entering {
+		if ref.link {
+			_, _ = w.WriteString(`<a class="glossary-term" href="#` + e.id + `"`)
+			if e.abbr == "" && e.plain != "" {
+				_, _ = w.WriteString(` title="` + gohtml.EscapeString(e.plain) + `"`)
+			}
+			_, _ = w.WriteString(">")
+		}
+		if e.abbr != "" {
+			_, _ = w.WriteString(`<abbr title="` + gohtml.EscapeString(e.abbr) + `">`)
+		}
+		return ast.WalkContinue, nil
+	}
+	if e.abbr != "" {
+		_, _ = w.WriteString("</abbr>")
+	}
+	if ref.link {
+		_, _ = w.WriteString("</a>")
+	}
+	return ast.WalkContinue, nil
+}
+
+// glossaryEntry는 용어와 그 정의다.
+type glossaryEntry struct {
+	term       string
+	match      string // 대소문자 무시 비교용 ASCII 소문자 용어
+	abbr       string // 약어의 원어
+	definition string // HTML
+	plain      string // 마우스를 올렸을 때 보일 일반 텍스트 정의
+	id         string
+}
+
+// glossaryValue는 YAML 정의다: 문자열 또는 {abbr, definition}.
+type glossaryValue struct {
+	Abbr       string `yaml:"abbr"`
+	Definition string `yaml:"definition"`
+}
+
+func (v *glossaryValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		v.Definition = node.Value
+		return nil
+	}
+	type plain glossaryValue
+	return node.Decode((*plain)(v))
+}
+
+// glossary는 모든 파일의 용어를 링크하고 부록을 렌더링한다.
+type glossary struct {
+	source  string           // 원본 파일의 절대 경로
+	entries []*glossaryEntry // 부록 순서
+	terms   []*glossaryEntry // 비교용, 긴 용어 먼저
+	linked  map[*glossaryEntry]bool
+}
+
+var reAbbreviation = regexp.MustCompile(`(?m)^\*\[([^\]]+)\]:[ \t]*(.*)$`)
+
+func newGlossary(file string, log logging.Printer) *glossary {
+	g := &glossary{linked: make(map[*glossaryEntry]bool)}
+	if file == "" {
+		return g
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.At(file, 0).Warnf("Could not read glossary: %v", err)
+		return g
+	}
+	g.source, _ = filepath.Abs(file)
+
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM, extension.DefinitionList))
+	byTerm := make(map[string]*glossaryEntry)
+	entry := func(term string) *glossaryEntry {
+		term = strings.TrimSpace(term)
+		if e, ok := byTerm[term]; ok {
+			return e
+		}
+		e := &glossaryEntry{term: term, match: asciiLower(term)}
+		byTerm[term] = e
+		return e
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yml", ".yaml":
+		var values map[string]glossaryValue
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			log.At(file, 0).Warnf("Could not parse glossary: %v", err)
+			return g
+		}
+		for term, v := range values {
+			if strings.TrimSpace(term) == "" {
+				continue
+			}
+			e := entry(term)
+			e.abbr = strings.TrimSpace(v.Abbr)
+			if v.Definition != "" {
+				source := []byte(v.Definition)
+				var n ast.Node = md.Parser().Parse(text.NewReader(source))
+				if p, ok := n.FirstChild().(*ast.Paragraph); ok && n.ChildCount() == 1 {
+					n = p // 좁은 정의 목록처럼 인라인으로
+				}
+				e.definition, e.plain = renderDefinition(md, []ast.Node{n}, source)
+			}
+		}
+	default:
+		// 약어 줄은 비워서 렌더링하지 않음
+		source := reAbbreviation.ReplaceAllFunc(data, func(line []byte) []byte {
+			m := reAbbreviation.FindSubmatch(line)
+			entry(string(m[1])).abbr = string(bytes.TrimSpace(m[2]))
+			return nil
+		})
+		doc := md.Parser().Parse(text.NewReader(source))
+		_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			term, ok := n.(*east.DefinitionTerm)
+			if !entering || !ok {
+				return ast.WalkContinue, nil
+			}
+			var descriptions []ast.Node
+			for d := term.NextSibling(); d != nil && d.Kind() == east.KindDefinitionDescription; d = d.NextSibling() {
+				descriptions = append(descriptions, d)
+			}
+			e := entry(string(term.Text(source)))
+			e.definition, e.plain = renderDefinition(md, descriptions, source)
+			return ast.WalkSkipChildren, nil
+		})
+	}
+
+	for _, e := range byTerm {
+		if e.term != "" {
+			g.entries = append(g.entries, e)
+		}
+	}
+	sort.Slice(g.entries, func(i, j int) bool { return indexLess(g.entries[i].term, g.entries[j].term) })
+	for i, e := range g.entries {
+		e.id = fmt.Sprintf("gloss-%d", i+1)
+	}
+	g.terms = append(g.terms, g.entries...)
+	sort.SliceStable(g.terms, func(i, j int) bool { return len(g.terms[i].term) > len(g.terms[j].term) })
+	log.Infof("Loaded %d glossary terms: %s", len(g.entries), file)
+	return g
+}
+
+// renderDefinition은 nodes의 자식을 HTML과 일반 텍스트로 렌더링한다.
+func renderDefinition(md goldmark.Markdown, nodes []ast.Node, source []byte) (string, string) {
+	var buf bytes.Buffer
+	var plain []string
+	for _, n := range nodes {
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			_ = md.Renderer().Render(&buf, source, c)
+			if _, inline := n.(*ast.Paragraph); !inline {
+				plain = append(plain, strings.TrimSpace(string(c.Text(source))))
+			}
+		}
+		if _, inline := n.(*ast.Paragraph); inline {
+			plain = append(plain, strings.TrimSpace(string(n.Text(source))))
+		}
+	}
+	return strings.TrimSpace(buf.String()), strings.Join(plain, " ")
+}
+
+// isSource는 file이 용어집 원본인지 알려준다. 원본은 장으로 변환하지 않는다.
+func (g *glossary) isSource(file string) bool {
+	abs, err := filepath.Abs(file)
+	return g.source != "" && err == nil && abs == g.source
+}
+
+// startChapter는 다음에 나오는 용어를 다시 링크하게 한다.
+func (g *glossary) startChapter() {
+	g.linked = make(map[*glossaryEntry]bool)
+}
+
+// link는 파일 하나의 용어 사용을 표시한다.
+func (g *glossary) link(doc ast.Node, source []byte) {
+	if len(g.terms) == 0 {
+		return
+	}
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Heading, *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		if txt, ok := n.(*ast.Text); ok {
+			texts = append(texts, txt)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, txt := range texts {
+		for txt != nil {
+			txt = g.linkText(txt, source)
+		}
+	}
+}
+
+// linkText는 txt에서 처음 나온 용어 사용을 표시하고 그 뒤의 텍스트를
+// 반환한다(없으면 nil).
+func (g *glossary) linkText(txt *ast.Text, source []byte) *ast.Text {
+	value := string(txt.Segment.Value(source))
+	folded := asciiLower(value)
+	var found *glossaryEntry
+	pos := -1
+	for _, e := range g.terms {
+		if g.linked[e] && e.abbr == "" {
+			continue
+		}
+		i := termIndex(folded, e.match)
+		if e.abbr != "" {
+			i = termIndex(value, e.term)
+		}
+		if i >= 0 && (pos < 0 || i < pos) {
+			found, pos = e, i
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	ref := &glossaryRef{entry: found, link: !g.linked[found]}
+	g.linked[found] = true
+	return wrapText(txt, pos, pos+len(found.term), ref)
+}
+
+// wrapText는 txt[start:stop]을 wrapper로 옮기고 그 뒤의 텍스트를 반환한다.
+func wrapText(txt *ast.Text, start, stop int, wrapper ast.Node) *ast.Text {
+	parent, seg := txt.Parent(), txt.Segment
+	wrapper.AppendChild(wrapper, ast.NewTextSegment(text.NewSegment(seg.Start+start, seg.Start+stop)))
+	tail := ast.NewTextSegment(seg.WithStart(seg.Start + stop))
+	tail.SetSoftLineBreak(txt.SoftLineBreak())
+	tail.SetHardLineBreak(txt.HardLineBreak())
+	txt.SetSoftLineBreak(false)
+	txt.SetHardLineBreak(false)
+	txt.Segment = seg.WithStop(seg.Start + start)
+	parent.InsertAfter(parent, txt, wrapper)
+	parent.InsertAfter(parent, wrapper, tail)
+	return tail
+}
+
+// render는 부록 내용을 반환한다.
+func (g *glossary) render() string {
+	var b strings.Builder
+	b.WriteString("<h1>" + glossaryTitle + "</h1>\n")
+	b.WriteString(`<dl class="glossary">` + "\n")
+	for _, e := range g.entries {
+		b.WriteString(`<dt id="` + e.id + `">` + gohtml.EscapeString(e.term))
+		definition := e.definition
+		switch {
+		case e.abbr != "" && definition == "":
+			definition = gohtml.EscapeString(e.abbr)
+		case e.abbr != "":
+			b.WriteString(` <span class="glossary-abbr">` + gohtml.EscapeString(e.abbr) + `</span>`)
+		}
+		b.WriteString("</dt>\n<dd>" + definition + "</dd>\n")
+	}
+	b.WriteString("</dl>\n")
+	return b.String()
+}
+
+// asciiLower는 바이트 위치가 바뀌지 않게 ASCII 문자만 소문자로 바꾼다.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,179 @@
+package converter
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+var reTestGlossary = regexp.MustCompile(`<a class="glossary-term" href="#[^"]+"[^>]*>|<abbr title="([^"]*)">|</abbr>|</a>|([^<]+)`)
+
+// glossaryUses는 섹션 본문에서 용어 사용을 순서대로 반환한다: 링크는
+// "link:용어", 약어는 "abbr:약어", 링크된 약어는 "link+abbr:약어".
+func glossaryUses(s Section) []string {
+	var uses []string
+	prefix := ""
+	for _, m := range reTestGlossary.FindAllStringSubmatch(s.Content, -1) {
+		switch {
+		case strings.HasPrefix(m[0], `<a class="glossary-term"`):
+			prefix = "link"
+		case strings.HasPrefix(m[0], "<abbr"):
+			if prefix != "" {
+				prefix += "+"
+			}
+			prefix += "abbr"
+		case m[0] == "</a>", m[0] == "</abbr>":
+			prefix = ""
+		case m[2] != "" && prefix != "":
+			uses = append(uses, prefix+":"+m[2])
+		}
+	}
+	return uses
+}
+
+func TestGlossaryLinks(t *testing.T) {
+	yml := "인증서: 공개 키와 소유자를 묶은 문서\ncertificate: signed key\nTLS certificate: server certificate\n" +
+		"TLS:\n  abbr: Transport Layer Security\n  definition: 전송 계층 암호화 규약\n"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  [][]string // 섹션별 용어 사용 (용어집 제외)
+	}{
+		// 장마다 처음 나온 용어만 링크
+		{"first use per chapter", map[string]string{
+			"01-intro.md": "# Intro\n\n인증서를 만들고 인증서를 씁니다.\n\n다시 인증서.\n",
+			"02-ops.md":   "# Operations\n\n인증서를 갱신합니다.\n",
+		}, [][]string{{"link:인증서"}, {"link:인증서"}}},
+		// H2만 있는 파일은 앞 장에 합쳐지므로 다시 링크하지 않음
+		{"merged file", map[string]string{
+			"01-intro.md": "# Intro\n\n인증서를 만듭니다.\n",
+			"02-more.md":  "## More\n\n인증서를 씁니다.\n",
+		}, [][]string{{"link:인증서"}}},
+		// 약어는 모든 곳에서 <abbr>로 감싸고 대소문자를 구분
+		{"abbreviations", map[string]string{
+			"01-intro.md": "# Intro\n\nTLS and TLS, not tls or TLSv1.\n",
+		}, [][]string{{"link+abbr:TLS", "abbr:TLS"}}},
+		// 라틴 문자 용어는 대소문자를 무시하고 단어 단위로 찾음
+		{"latin words", map[string]string{
+			"01-intro.md": "# Intro\n\nNo certificates here, but a Certificate there.\n",
+		}, [][]string{{"link:Certificate"}}},
+		// 같은 자리에서는 긴 용어 우선
+		{"longest term", map[string]string{
+			"01-intro.md": "# Intro\n\nA TLS certificate, then a certificate.\n",
+		}, [][]string{{"link:TLS certificate", "link:certificate"}}},
+		// 제목, 코드, 링크 안은 제외
+		{"skipped nodes", map[string]string{
+			"01-intro.md": "# Intro 인증서\n\n## 인증서 만들기\n\n`인증서` [인증서](x.html) ![인증서](a.png)\n\n본문의 인증서.\n",
+		}, [][]string{{"link:인증서"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"glossary.yml": yml}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			b, err := convertDocs(t, files, Options{Glossary: "glossary.yml"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [][]string
+			for _, s := range b.sections {
+				if s.ID != glossaryID {
+					got = append(got, glossaryUses(s))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uses:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlossaryAppendix(t *testing.T) {
+	sources := map[string]string{
+		"glossary.yml": "인증서: 공개 키와 *소유자*를 묶은 문서\nAPI:\n  abbr: Application Programming Interface\n" +
+			"TLS:\n  abbr: Transport Layer Security\n  definition: 전송 계층 암호화 규약\n갱신: 만료 전에 다시 발급\n",
+		"glossary.md": "인증서\n: 공개 키와 *소유자*를 묶은 문서\n\nTLS\n: 전송 계층 암호화 규약\n\n갱신\n: 만료 전에 다시 발급\n\n" +
+			"*[API]: Application Programming Interface\n*[TLS]: Transport Layer Security\n",
+	}
+	// 한글 다음 라틴 문자 순, 약어만 있으면 원어가 정의
+	want := `<h1>용어집</h1>
+<dl class="glossary">
+<dt id="gloss-1">갱신</dt>
+<dd>만료 전에 다시 발급</dd>
+<dt id="gloss-2">인증서</dt>
+<dd>공개 키와 <em>소유자</em>를 묶은 문서</dd>
+<dt id="gloss-3">API</dt>
+<dd>Application Programming Interface</dd>
+<dt id="gloss-4">TLS <span class="glossary-abbr">Transport Layer Security</span></dt>
+<dd>전송 계층 암호화 규약</dd>
+</dl>
+`
+	for source, content := range sources {
+		t.Run(source, func(t *testing.T) {
+			b, err := convertDocs(t, map[string]string{
+				source:        content,
+				"01-intro.md": "# Intro\n\n인증서와 TLS.\n",
+			}, Options{Glossary: source})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Markdown 원본 파일은 장으로 변환하지 않고, 용어집은 마지막 섹션
+			if len(b.sections) != 2 || b.sections[1].ID != glossaryID || b.sections[1].Title != "용어집" {
+				t.Fatalf("sections = %+v", b.sections)
+			}
+			info := strings.Join(b.messages(logging.Info), "\n")
+			if source == "glossary.md" && !strings.Contains(info, "Skipping glossary.md (glossary source)") {
+				t.Errorf("glossary source not skipped:\n%s", info)
+			}
+			if got := b.sections[1].Content; got != want {
+				t.Errorf("appendix:\n%s\nwant:\n%s", got, want)
+			}
+			// 약어가 아닌 용어는 링크에 정의를 보여줌
+			intro := b.sections[0].Content
+			for _, link := range []string{
+				`<a class="glossary-term" href="#gloss-2" title="공개 키와 소유자를 묶은 문서">인증서</a>`,
+				`<a class="glossary-term" href="#gloss-4"><abbr title="Transport Layer Security">TLS</abbr></a>`,
+			} {
+				if !strings.Contains(intro, link) {
+					t.Errorf("missing %s in:\n%s", link, intro)
+				}
+			}
+		})
+	}
+}
+
+func TestGlossaryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		file  string
+		want  string
+	}{
+		{"missing", nil, "missing.yml", "Could not read glossary: "},
+		{"invalid YAML", map[string]string{"glossary.yml": "- a\n- list\n"}, "glossary.yml", "Could not parse glossary: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"01-intro.md": "# Intro\n"}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			b, err := convertDocs(t, files, Options{Glossary: tt.file})
+			if err != nil {
+				t.Fatal(err)
+			}
+			warnings := b.messages(logging.Warn)
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.want)
+			}
+			// 용어집 없이 빌드
+			if len(b.sections) != 1 {
+				t.Errorf("got %d sections, want 1", len(b.sections))
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"md2pdf/logging"
+)
+
+// 포함 지시문은 파싱 전에 Markdown 원본에서 펼친다. 경로는 포함하는 파일
+// 기준이다:
+//
+//	<!-- @include ../shared/notice.md -->                     Markdown
+//	<!-- @include-code ../../tkcli/main.go lines=10-20 -->    코드 줄 범위
+//	<!-- @include-code config.yaml region=tls -->             코드 영역
+//	<!-- @include-code main.go region=main {#lst:main} -->    속성 지정
+//
+// 영역은 주석(// # -- <!-- /* ;) 안의 "region:name"과 "endregion:name"
+// 사이의 줄이다. 언어는 lang=이 없으면 확장자로 정한다. 코드 블록 안의
+// 지시문과 빌드 조건에 맞지 않는 '::: only' 블록 안의 지시문은 펼치지 않고,
+// 포함한 Markdown 파일에도 조건을 적용한다(conditions.go 참고). 펼친 내용에
+// 대한 메시지는 내용이 나온 파일과 줄을 가리킨다(sourceMap).
+
+var (
+	reInclude     = regexp.MustCompile(`^[ \t]*<!--[ \t]*@include(-code)?[ \t]+(\S+)((?:[ \t]+\w+=(?:"[^"]*"|[^\s{]+))*)[ \t]*(\{[^}]*\})?[ \t]*-->[ \t]*$`)
+	reIncludeArg  = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|(\S+))`)
+	reRegion      = regexp.MustCompile(`(?://|#|--|<!--|/\*|;)\s*(end)?region:([\w.-]+)`)
+	reFenceOpen   = regexp.MustCompile("^[ \t]{0,3}(`{3,}|~{3,})")
+	reMarkdownDst = regexp.MustCompile(`(\]\()([^)\s]+)`)
+)
+
+// 확장자별 코드 언어 (그 밖의 확장자는 확장자 그대로)
+var codeLanguages = map[string]string{
+	".py": "python", ".js": "javascript", ".ts": "typescript", ".sh": "bash",
+	".yml": "yaml", ".rs": "rust", ".rb": "ruby", ".h": "c", ".hpp": "cpp",
+	".cc": "cpp", ".md": "markdown", ".ps1": "powershell", ".kt": "kotlin",
+}
+
+// fenceTracker는 Markdown 줄의 코드 울타리를 따라간다.
+type fenceTracker struct {
+	fence []byte // 열린 울타리, 코드 밖에서는 nil
+}
+
+// inCode는 line이 울타리 코드 블록(울타리 포함)에 속하는지 알려준다.
+func (t *fenceTracker) inCode(line []byte) bool {
+	m := reFenceOpen.FindSubmatch(line)
+	if t.fence == nil {
+		if m != nil {
+			t.fence = m[1]
+		}
+		return m != nil
+	}
+	if m != nil && m[1][0] == t.fence[0] && len(m[1]) >= len(t.fence) && len(bytes.TrimSpace(line[len(m[0]):])) == 0 {
+		t.fence = nil
+	}
+	return true
+}
+
+// sourceLine은 펼친 내용의 한 줄이 나온 곳이다.
+type sourceLine struct {
+	file string
+	line int
+}
+
+// sourceMap은 펼친 파일에 대한 메시지의 위치를 내용이 나온 파일과 줄로
+// 바꾸는 Logger다.
+type sourceMap struct {
+	logging.Logger
+	mu    sync.RWMutex
+	files map[string][]sourceLine // 펼친 파일별, 줄마다 나온 곳
+}
+
+func newSourceMap(l logging.Logger) *sourceMap {
+	return &sourceMap{Logger: l, files: make(map[string][]sourceLine)}
+}
+
+// set은 펼친 파일의 줄마다 나온 곳을 기록한다. nil은 포함이 없었다는 뜻이다.
+func (m *sourceMap) set(file string, lines []sourceLine) {
+	if lines == nil {
+		return
+	}
+	m.mu.Lock()
+	m.files[file] = lines
+	m.mu.Unlock()
+}
+
+// Log는 e의 위치를 바꿔 넘긴다.
+func (m *sourceMap) Log(e logging.Entry) {
+	m.mu.RLock()
+	lines := m.files[e.File]
+	m.mu.RUnlock()
+	if e.Line > 0 && e.Line <= len(lines) {
+		e.File, e.Line = lines[e.Line-1].file, lines[e.Line-1].line
+	}
+	m.Logger.Log(e)
+}
+
+// expandIncludes는 파일에 prof의 조건을 적용하고 포함 지시문을 펼친 내용과
+// 결과의 줄마다 나온 곳을 반환한다(포함이 없으면 nil).
+func expandIncludes(file string, content []byte, prof profile, log logging.Printer) ([]byte, []sourceLine) {
+	abs, _ := filepath.Abs(file)
+	return expandFile(file, content, prof, []string{abs}, log)
+}
+
+func expandFile(file string, content []byte, prof profile, stack []string, log logging.Printer) ([]byte, []sourceLine) {
+	// 버려지는 블록의 지시문은 펼치지 않도록 조건을 먼저 적용
+	content = prof.filter(file, content, log)
+	if !bytes.Contains(content, []byte("@include")) {
+		return content, nil
+	}
+	var out bytes.Buffer
+	var lines []sourceLine
+	var fences fenceTracker
+	for i, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if fences.inCode(line) {
+			out.Write(line)
+			lines = append(lines, sourceLine{file, i + 1})
+			continue
+		}
+		m := reInclude.FindSubmatch(bytes.TrimRight(line, "\r\n"))
+		if m == nil {
+			out.Write(line)
+			lines = append(lines, sourceLine{file, i + 1})
+			continue
+		}
+		log := log.At(file, i+1)
+		path := filepath.Join(filepath.Dir(file), string(m[2]))
+		args := make(map[string]string)
+		for _, a := range reIncludeArg.FindAllSubmatch(m[3], -1) {
+			args[string(a[1])] = string(a[2]) + string(a[3])
+		}
+
+		var included []byte
+		var origin []sourceLine
+		if len(m[1]) > 0 {
+			included = includeCode(path, args, string(m[4]), log)
+		} else {
+			included, origin = includeMarkdown(path, filepath.Dir(file), prof, stack, log)
+		}
+		out.Write(included)
+		if len(included) > 0 && included[len(included)-1] != '\n' {
+			out.WriteByte('\n')
+		}
+		// 코드 줄은 지시문의 줄, Markdown 줄은 포함한 파일의 줄
+		added := bytes.Count(out.Bytes(), []byte("\n")) - len(lines)
+		for n := 0; n < added; n++ {
+			switch {
+			case n < len(origin):
+				lines = append(lines, origin[n])
+			case len(m[1]) > 0:
+				lines = append(lines, sourceLine{file, i + 1})
+			default:
+				lines = append(lines, sourceLine{path, n + 1})
+			}
+		}
+	}
+	return out.Bytes(), lines
+}
+
+// includeMarkdown은 Markdown 파일을 펼쳐 상대 링크를 dir 기준으로 바꾼
+// 내용과 줄마다 나온 곳(모두 그 파일의 줄이면 nil)을 반환한다.
+func includeMarkdown(path, dir string, prof profile, stack []string, log logging.Printer) ([]byte, []sourceLine) {
+	abs, _ := filepath.Abs(path)
+	for i, f := range stack {
+		if f == abs {
+			cycle := make([]string, 0, len(stack)-i+1)
+			for _, f := range append(stack[i:], abs) {
+				cycle = append(cycle, filepath.Base(f))
+			}
+			log.Warnf("Include cycle: %s", strings.Join(cycle, " -> "))
+			return nil, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Warnf("Could not include %s: %v", path, err)
+		return nil, nil
+	}
+	_, data = parseFrontMatter(path, data, log)
+	data, lines := expandFile(path, data, prof, append(stack[:len(stack):len(stack)], abs), log)
+	return rebaseLinks(data, filepath.Dir(path), dir), lines
+}
+
+// rebaseLinks는 Markdown의 상대 링크·이미지 경로를 from 디렉터리 기준에서
+// to 디렉터리 기준으로 바꾼다.
+func rebaseLinks(content []byte, from, to string) []byte {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return content
+	}
+	var out bytes.Buffer
+	var fences fenceTracker
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if fences.inCode(line) {
+			out.Write(line)
+			continue
+		}
+		out.Write(reMarkdownDst.ReplaceAllFunc(line, func(b []byte) []byte {
+			m := reMarkdownDst.FindSubmatch(b)
+			dst := string(m[2])
+			if dst == "" || strings.HasPrefix(dst, "#") || strings.HasPrefix(dst, "/") || strings.Contains(dst, ":") {
+				return b
+			}
+			rel, err := filepath.Rel(to, filepath.Join(from, filepath.FromSlash(dst)))
+			if err != nil {
+				return b
+			}
+			return append(m[1], filepath.ToSlash(rel)...)
+		}))
+	}
+	return out.Bytes()
+}
+
+// includeCode는 소스 파일의 줄로 울타리 코드 블록을 만든다.
+func includeCode(path string, args map[string]string, attrs string, log logging.Printer) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Warnf("Could not include %s: %v", path, err)
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+
+	if r := args["lines"]; r != "" {
+		selected, err := selectLines(lines, r)
+		if err != nil {
+			log.Warnf("Invalid line range %q: %v", r, err)
+			return nil
+		}
+		lines = selected
+	}
+	if name := args["region"]; name != "" {
+		region, ok := selectRegion(lines, name)
+		if !ok {
+			log.Warnf("Region %q not found in %s", name, path)
+			return nil
+		}
+		lines = region
+	}
+	// 다른 영역의 표시 줄은 제외
+	kept := lines[:0:0]
+	for _, l := range lines {
+		if !reRegion.MatchString(l) {
+			kept = append(kept, l)
+		}
+	}
+	code := strings.Join(dedent(kept), "\n")
+
+	lang := args["lang"]
+	if lang == "" {
+		ext := strings.ToLower(filepath.Ext(path))
+		if lang = codeLanguages[ext]; lang == "" {
+			lang = strings.TrimPrefix(ext, ".")
+		}
+	}
+	info := strings.TrimSpace(lang + " " + attrs)
+
+	// 코드 안의 어떤 백틱 연속보다도 길게
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return []byte(fence + info + "\n" + code + "\n" + fence + "\n")
+}
+
+// selectLines는 r 범위의 줄을 반환한다: "10-20", "10-", "5" 또는 이를
+// 쉼표로 나열한 목록(1부터, 끝 포함).
+func selectLines(lines []string, r string) ([]string, error) {
+	var selected []string
+	for _, part := range strings.Split(r, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := strconv.Atoi(from)
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("bad start %q", from)
+		}
+		end := start
+		if isRange {
+			end = len(lines)
+			if to != "" {
+				if end, err = strconv.Atoi(to); err != nil || end < start {
+					return nil, fmt.Errorf("bad end %q", to)
+				}
+			}
+		}
+		if start > len(lines) {
+			return nil, fmt.Errorf("file has %d lines", len(lines))
+		}
+		selected = append(selected, lines[start-1:min(end, len(lines))]...)
+	}
+	return selected, nil
+}
+
+// selectRegion은 영역 표시 사이의 줄을 반환한다.
+func selectRegion(lines []string, name string) ([]string, bool) {
+	start := -1
+	for i, l := range lines {
+		m := reRegion.FindStringSubmatch(l)
+		if m == nil || m[2] != name {
+			continue
+		}
+		if m[1] == "" && start < 0 {
+			start = i + 1
+		} else if m[1] != "" && start >= 0 {
+			return lines[start:i], true
+		}
+	}
+	return nil, false
+}
+
+// dedent는 줄들에 공통된 앞 공백을 없앤다.
+func dedent(lines []string) []string {
+	prefix := ""
+	first := true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if prefix == "" {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = strings.TrimPrefix(l, prefix)
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+func TestSelectLines(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5"}
+	tests := []struct {
+		r       string
+		want    string
+		wantErr bool
+	}{
+		{r: "2-4", want: "2 3 4"},
+		{r: "4-", want: "4 5"},
+		{r: "3", want: "3"},
+		{r: "1, 3-4", want: "1 3 4"},
+		{r: "4-9", want: "4 5"},
+		{r: "0-2", wantErr: true},
+		{r: "4-2", wantErr: true},
+		{r: "6", wantErr: true},
+		{r: "a-b", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := selectLines(lines, tt.r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("selectLines(%q) error = %v, want error %v", tt.r, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && strings.Join(got, " ") != tt.want {
+			t.Errorf("selectLines(%q) = %q, want %q", tt.r, strings.Join(got, " "), tt.want)
+		}
+	}
+}
+
+func TestSelectRegion(t *testing.T) {
+	lines := strings.Split(`a
+// region:main
+b
+# region:inner
+c
+# endregion:inner
+<!-- endregion:main -->
+d`, "\n")
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"main", "b|# region:inner|c|# endregion:inner", true},
+		{"inner", "c", true},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := selectRegion(lines, tt.name)
+		if ok != tt.ok || strings.Join(got, "|") != tt.want {
+			t.Errorf("selectRegion(%q) = %q, %v, want %q, %v", tt.name, strings.Join(got, "|"), ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIncludeCode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	src := "package main\n\nfunc main() {\n\t// region:body\n\tprintln(\"```\")\n\t// endregion:body\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		args  map[string]string
+		attrs string
+		want  string
+	}{
+		{"lines", map[string]string{"lines": "1"}, "", "```go\npackage main\n```\n"},
+		{"region", map[string]string{"region": "body"}, "", "````go\nprintln(\"```\")\n````\n"},
+		{"markers dropped", map[string]string{"lines": "3-7"}, "", "````go\nfunc main() {\n\tprintln(\"```\")\n}\n````\n"},
+		{"lang and attributes", map[string]string{"lines": "1", "lang": "text"}, "{#lst:main}", "```text {#lst:main}\npackage main\n```\n"},
+		{"missing region", map[string]string{"region": "nope"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(includeCode(path, tt.args, tt.attrs, logging.Use(logging.Discard)))
+			if got != tt.want {
+				t.Errorf("includeCode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandIncludesSourceLines(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	part := write("part.md", "---\ntitle: x\n---\nPart {{ var.x }}\n")
+	write("code.go", "package x\n")
+	src := "# Main\n<!-- @include missing.md -->\n<!-- @include part.md -->\n<!-- @include-code code.go -->\nEnd\n"
+	main := write("main.md", src)
+
+	var entries []logging.Entry
+	m := newSourceMap(logging.LoggerFunc(func(e logging.Entry) { entries = append(entries, e) }))
+	log := logging.Use(m)
+	content, lines := expandIncludes(main, []byte(src), newProfile(Options{}), log)
+	if got := strings.Count(string(content), "\n"); got != len(lines) {
+		t.Fatalf("%d lines mapped, content has %d", len(lines), got)
+	}
+	m.set(main, lines)
+	log.At(main, 5).Warnf("in part")
+	log.At(main, 9).Warnf("at end")
+	log.At(main, 7).Warnf("in code")
+
+	want := []struct {
+		file string
+		line int
+	}{{main, 2}, {part, 4}, {main, 5}, {main, 4}}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		if entries[i].File != w.file || entries[i].Line != w.line {
+			t.Errorf("entry %d (%s) at %s:%d, want %s:%d", i, entries[i].Message, entries[i].File, entries[i].Line, w.file, w.line)
+		}
+	}
+}
+
+func TestIncludeConditions(t *testing.T) {
+	part := "Part.\n\n::: only pdf\nPDF part.\n:::\n\n::: only html\nHTML part.\n:::\n"
+	tests := []struct {
+		name     string
+		doc      string
+		opts     Options
+		want     []string // 본문에 있어야 하는 문구
+		unwanted []string
+		warn     []string
+	}{
+		// 버려지는 블록의 지시문은 펼치지 않으므로 없는 파일도 경고하지 않음
+		{"dropped block", "::: only admin\n<!-- @include part.inc -->\n<!-- @include missing.inc -->\n<!-- @include-code missing.go -->\n:::\n",
+			Options{}, nil, []string{"Part."}, nil},
+		{"kept block", "::: only admin\n<!-- @include part.inc -->\n:::\n",
+			Options{Profiles: []string{"admin"}}, []string{"Part.", "HTML part."}, []string{"PDF part."}, nil},
+		// 포함한 파일에도 조건 적용
+		{"pdf", "<!-- @include part.inc -->\n", Options{PDFMode: true}, []string{"Part.", "PDF part."}, []string{"HTML part."}, nil},
+		{"nested", "::: only admin\n<!-- @include nested.inc -->\n:::\n",
+			Options{Profiles: []string{"admin"}}, []string{"Nested.", "Part."}, nil, nil},
+		{"nested dropped", "<!-- @include nested.inc -->\n",
+			Options{}, []string{"Nested."}, []string{"Part."}, nil},
+		// 버려지는 블록 안의 자기 포함은 순환이 아님
+		{"cycle in dropped block", "::: only admin\n<!-- @include 01-intro.md -->\n:::\n", Options{}, nil, nil, nil},
+		// 포함한 파일의 메시지는 그 파일의 줄
+		{"unclosed block", "<!-- @include unclosed.inc -->\n", Options{},
+			nil, []string{"Hidden."}, []string{"unclosed.inc:2: Unclosed '::: only' block"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, map[string]string{
+				"01-intro.md":  "# Intro\n\n" + tt.doc,
+				"part.inc":     part,
+				"nested.inc":   "Nested.\n\n::: only admin\n<!-- @include part.inc -->\n:::\n",
+				"unclosed.inc": "Text.\n::: only admin\nHidden.\n",
+			}, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := b.sections[0].Content
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("missing %q in:\n%s", want, content)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(content, unwanted) {
+					t.Errorf("unexpected %q in:\n%s", unwanted, content)
+				}
+			}
+			if got := b.messages(logging.Warn); strings.Join(got, "\n") != strings.Join(tt.warn, "\n") {
+				t.Errorf("warnings = %q, want %q", got, tt.warn)
+			}
+		})
+	}
+}
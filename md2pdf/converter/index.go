@@ -0,0 +1,427 @@
+package converter
+
+import (
+	"fmt"
+	gohtml "html"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"gopkg.in/yaml.v3"
+
+	"md2pdf/logging"
+)
+
+// 찾아보기(Options.Index). 항목은 본문, 제목, 용어 파일로 표시하고,
+// "; "로 항목의 수준을 나눈다:
+//
+//	{{< index "certificate; renewal" >}}      본문 표시
+//	## Renewal {index="certificate; renewal"} 제목 속성
+//
+//	# 용어 파일 (Options.IndexTerms): 용어 -> 항목 (비우면 용어 그대로)
+//	TLS: security; TLS
+//	인증서:
+//
+// 용어는 문단마다 한 번 표시하고, 긴 용어 안의 짧은 용어는 표시하지 않는다.
+// 라틴 문자 용어는 대소문자를 무시하고 단어 단위로 찾는다. 찾아보기는 마지막
+// 섹션으로 붙고, 한글 초성과 라틴 문자로 묶으며, 쪽 분석 결과의 쪽 번호를
+// 쓴다(HTML 빌드는 섹션 제목).
+
+const (
+	indexID    = "book-index"
+	indexTitle = "찾아보기"
+)
+
+var kindIndexMark = ast.NewNodeKind("IndexMark")
+
+// indexMark는 찾아보기 항목의 보이지 않는 앵커
+type indexMark struct {
+	ast.BaseInline
+	entries []string
+	id      string // bookIndex.collect가 설정
+}
+
+func (n *indexMark) Kind() ast.NodeKind { return kindIndexMark }
+
+func (n *indexMark) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Entries": strings.Join(n.entries, " | ")}, nil)
+}
+
+// indexExtension은 {{< index "..." >}} 표시를 파싱한다.
+type indexExtension struct{}
+
+func (e *indexExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&indexMarkParser{}, 600)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&indexMarkRenderer{}, 500)))
+}
+
+var (
+	reIndexMark  = regexp.MustCompile(`^\{\{<\s*index((?:\s+"[^"]*")+)\s*>\}\}`)
+	reIndexEntry = regexp.MustCompile(`"([^"]*)"`)
+)
+
+type indexMarkParser struct{}
+
+func (p *indexMarkParser) Trigger() []byte { return []byte{'{'} }
+
+func (p *indexMarkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	m := reIndexMark.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	block.Advance(len(m[0]))
+	mark := &indexMark{}
+	for _, e := range reIndexEntry.FindAllSubmatch(m[1], -1) {
+		mark.entries = append(mark.entries, string(e[1]))
+	}
+	return mark
+}
+
+type indexMarkRenderer struct{}
+
+func (r *indexMarkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindIndexMark, r.render)
+}
+
+func (r *indexMarkRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	// 찾아보기가 없으면(ID 없음) 표시를 지움
+	if id := node.(*indexMark).id; entering && id != "" {
+		_, _ = w.WriteString(`<span class="index-mark" id="` + id + `"></span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// indexTerm은 용어 파일의 용어
+type indexTerm struct {
+	match string // 소문자
+	entry string
+}
+
+// indexRef는 찾아보기 항목이 한 번 나온 자리
+type indexRef struct {
+	path    []string // 항목 수준
+	id      string   // 앵커
+	section string   // 섹션 제목 (HTML 빌드용)
+}
+
+// bookIndex는 모든 파일의 찾아보기 항목을 모은다.
+type bookIndex struct {
+	terms []indexTerm
+	refs  []indexRef
+	marks int
+}
+
+func newBookIndex(termsFile string, log logging.Printer) *bookIndex {
+	x := &bookIndex{}
+	if termsFile == "" {
+		return x
+	}
+	data, err := os.ReadFile(termsFile)
+	if err != nil {
+		log.At(termsFile, 0).Warnf("Could not read index terms: %v", err)
+		return x
+	}
+	var terms map[string]string
+	if err := yaml.Unmarshal(data, &terms); err != nil {
+		log.At(termsFile, 0).Warnf("Could not parse index terms: %v", err)
+		return x
+	}
+	for term, entry := range terms {
+		if strings.TrimSpace(term) == "" {
+			continue
+		}
+		if strings.TrimSpace(entry) == "" {
+			entry = term
+		}
+		x.terms = append(x.terms, indexTerm{match: strings.ToLower(strings.TrimSpace(term)), entry: entry})
+	}
+	// 긴 용어부터 찾아 문단의 "TLS certificate"가 "TLS"보다 우선
+	sort.Slice(x.terms, func(i, j int) bool {
+		if len(x.terms[i].match) != len(x.terms[j].match) {
+			return len(x.terms[i].match) > len(x.terms[j].match)
+		}
+		return x.terms[i].match < x.terms[j].match
+	})
+	log.Infof("Loaded %d index terms: %s", len(x.terms), termsFile)
+	return x
+}
+
+// collect는 파일 하나의 찾아보기 항목을 표시하고 앵커 ID를 반환한다.
+func (x *bookIndex) collect(doc ast.Node, source []byte, section string) []string {
+	var headings []*ast.Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if hd, ok := n.(*ast.Heading); ok && entering {
+			headings = append(headings, hd)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, hd := range headings {
+		v, ok := hd.AttributeString("index")
+		if !ok {
+			continue
+		}
+		// HTML 속성으로 출력하지 않음
+		attrs := hd.Attributes()
+		hd.RemoveAttributes()
+		for _, a := range attrs {
+			if string(a.Name) != "index" {
+				hd.SetAttribute(a.Name, a.Value)
+			}
+		}
+		b, ok := v.([]byte)
+		if !ok || len(b) == 0 {
+			continue
+		}
+		mark := &indexMark{entries: []string{string(b)}}
+		if first := hd.FirstChild(); first != nil {
+			hd.InsertBefore(hd, first, mark)
+		} else {
+			hd.AppendChild(hd, mark)
+		}
+	}
+	if len(x.terms) > 0 {
+		x.markTerms(doc, source)
+	}
+
+	var ids []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		mark, ok := n.(*indexMark)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		x.marks++
+		mark.id = fmt.Sprintf("idx-%d", x.marks)
+		for _, e := range mark.entries {
+			var path []string
+			for _, level := range strings.Split(e, ";") {
+				if level = strings.TrimSpace(level); level != "" {
+					path = append(path, level)
+				}
+			}
+			if len(path) > 0 {
+				x.refs = append(x.refs, indexRef{path: path, id: mark.id, section: section})
+			}
+		}
+		ids = append(ids, mark.id)
+		return ast.WalkContinue, nil
+	})
+	return ids
+}
+
+// markTerms는 문단(또는 다른 텍스트 블록)마다 각 용어가 처음 나온 자리 앞에
+// 표시를 넣는다.
+func (x *bookIndex) markTerms(doc ast.Node, source []byte) {
+	type hit struct {
+		txt   *ast.Text
+		entry string
+	}
+	var hits []hit
+	seen := make(map[ast.Node]map[string]bool)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Heading, *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		txt, ok := n.(*ast.Text)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		block := txt.Parent()
+		for block.Type() != ast.TypeBlock {
+			block = block.Parent()
+		}
+		if seen[block] == nil {
+			seen[block] = make(map[string]bool)
+		}
+		value := strings.ToLower(string(txt.Segment.Value(source)))
+		for _, t := range x.terms {
+			found := false
+			for i := termIndex(value, t.match); i >= 0; i = termIndex(value, t.match) {
+				// 긴 용어 안의 짧은 용어(예: "TLS certificate"의 "TLS")는 따로
+				// 찾지 않도록 찾은 자리를 지움
+				value = value[:i] + strings.Repeat(" ", len(t.match)) + value[i+len(t.match):]
+				found = true
+			}
+			if found && !seen[block][t.match] {
+				seen[block][t.match] = true
+				hits = append(hits, hit{txt, t.entry})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, h := range hits {
+		h.txt.Parent().InsertBefore(h.txt.Parent(), h.txt, &indexMark{entries: []string{h.entry}})
+	}
+}
+
+// termIndex는 s에서 term의 위치를 반환한다(없으면 -1). term 양 끝의 라틴
+// 문자와 숫자는 s에서 단어 중간이면 안 된다.
+func termIndex(s, term string) int {
+	for i := 0; i <= len(s)-len(term); {
+		j := strings.Index(s[i:], term)
+		if j < 0 {
+			return -1
+		}
+		start, end := i+j, i+j+len(term)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		first, _ := utf8.DecodeRuneInString(term)
+		last, _ := utf8.DecodeLastRuneInString(term)
+		if !(isLatinWord(first) && isLatinWord(before)) && !(isLatinWord(last) && isLatinWord(after)) {
+			return start
+		}
+		i = start + 1
+	}
+	return -1
+}
+
+func isLatinWord(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// indexNode는 출력할 찾아보기의 항목
+type indexNode struct {
+	term     string
+	refs     []indexRef
+	children map[string]*indexNode
+}
+
+// render는 찾아보기 섹션 내용을 반환한다. 쪽 번호가 있으면 위치는 쪽
+// 번호이고, 쪽 번호 없는 PDF 모드(pass 1)에서는 모든 앵커가 PDF 위치를
+// 갖도록 전부 링크하며, 그 밖에는 섹션 제목이다.
+func (x *bookIndex) render(pages map[string]int, pdfMode bool) string {
+	root := &indexNode{children: make(map[string]*indexNode)}
+	for _, ref := range x.refs {
+		n := root
+		for _, level := range ref.path {
+			child, ok := n.children[level]
+			if !ok {
+				child = &indexNode{term: level, children: make(map[string]*indexNode)}
+				n.children[level] = child
+			}
+			n = child
+		}
+		n.refs = append(n.refs, ref)
+	}
+
+	var b strings.Builder
+	b.WriteString("<h1>" + indexTitle + "</h1>\n")
+	b.WriteString(`<div class="book-index">` + "\n")
+	group := ""
+	for _, n := range sortedIndexNodes(root) {
+		if g := indexGroup(n.term); g != group || group == "" {
+			if group != "" {
+				b.WriteString("</ul>\n")
+			}
+			group = g
+			b.WriteString(`<h2 class="index-group">` + gohtml.EscapeString(g) + "</h2>\n<ul>\n")
+		}
+		writeIndexNode(&b, n, pages, pdfMode)
+	}
+	if group != "" {
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+func writeIndexNode(b *strings.Builder, n *indexNode, pages map[string]int, pdfMode bool) {
+	b.WriteString(`<li><span class="index-term">` + gohtml.EscapeString(n.term) + `</span>`)
+	var locators []string
+	seen := make(map[string]bool)
+	for _, ref := range n.refs {
+		label := ref.section
+		if page := pages[ref.id]; page > 0 {
+			label = fmt.Sprint(page)
+		} else if pdfMode {
+			label = "?"
+		}
+		if seen[label] && !(pdfMode && pages == nil) {
+			continue
+		}
+		seen[label] = true
+		locators = append(locators, `<a href="#`+ref.id+`">`+gohtml.EscapeString(label)+`</a>`)
+	}
+	if len(locators) > 0 {
+		b.WriteString(` <span class="index-pages">` + strings.Join(locators, ", ") + `</span>`)
+	}
+	if len(n.children) > 0 {
+		b.WriteString("\n<ul>\n")
+		for _, child := range sortedIndexNodes(n) {
+			writeIndexNode(b, child, pages, pdfMode)
+		}
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</li>\n")
+}
+
+func sortedIndexNodes(n *indexNode) []*indexNode {
+	nodes := make([]*indexNode, 0, len(n.children))
+	for _, c := range n.children {
+		nodes = append(nodes, c)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return indexLess(nodes[i].term, nodes[j].term) })
+	return nodes
+}
+
+// hangulGroups는 초성 순서의 한글 묶음. 된소리는 예사소리에 묶는다(ㄲ -> ㄱ)
+var hangulGroups = []string{"ㄱ", "ㄱ", "ㄴ", "ㄷ", "ㄷ", "ㄹ", "ㅁ", "ㅂ", "ㅂ", "ㅅ", "ㅅ", "ㅇ", "ㅈ", "ㅈ", "ㅊ", "ㅋ", "ㅌ", "ㅍ", "ㅎ"}
+
+// tenseJamo는 호환용 자음 된소리를 예사소리로 바꾼다.
+var tenseJamo = strings.NewReplacer("ㄲ", "ㄱ", "ㄸ", "ㄷ", "ㅃ", "ㅂ", "ㅆ", "ㅅ", "ㅉ", "ㅈ")
+
+// indexGroup은 용어의 묶음 제목을 반환한다: 한글은 초성, 라틴 문자는
+// 대문자, 그 밖에는 "#".
+func indexGroup(term string) string {
+	r, _ := utf8.DecodeRuneInString(term)
+	switch {
+	case r >= 0xAC00 && r <= 0xD7A3:
+		return hangulGroups[(r-0xAC00)/588]
+	case r >= 'ㄱ' && r <= 'ㅎ':
+		return tenseJamo.Replace(string(r))
+	case r < utf8.RuneSelf && unicode.IsLetter(r):
+		return string(unicode.ToUpper(r))
+	}
+	return "#"
+}
+
+// indexRank는 묶음 순서를 정한다: 기호와 숫자, 한글, 라틴 문자.
+func indexRank(term string) int {
+	switch g := indexGroup(term); {
+	case g == "#":
+		return 0
+	case g >= "A" && g <= "Z":
+		return 2
+	}
+	return 1
+}
+
+// indexLess는 용어를 묶음 순서, 묶음 안에서는 사전 순서로 비교한다(한글
+// 음절은 유니코드 순서가 사전 순서, 라틴 문자는 대소문자 무시). 자음만으로
+// 시작하는 용어(예: "ㄴ자형")도 그 초성 묶음에 들어가도록 묶음을 먼저 비교한다.
+func indexLess(a, b string) bool {
+	if ra, rb := indexRank(a), indexRank(b); ra != rb {
+		return ra < rb
+	}
+	if ga, gb := indexGroup(a), indexGroup(b); ga != gb {
+		return ga < gb
+	}
+	la, lb := strings.ToLower(a), strings.ToLower(b)
+	if la != lb {
+		return la < lb
+	}
+	return a < b
+}
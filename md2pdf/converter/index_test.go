@@ -0,0 +1,206 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+var (
+	reTestIndex   = regexp.MustCompile(`<h2 class="index-group">([^<]*)</h2>|<ul>|</ul>|<li><span class="index-term">([^<]*)</span>(?: <span class="index-pages">(.*?)</span>)?`)
+	reTestLocator = regexp.MustCompile(`>([^<]*)</a>`)
+)
+
+// indexOutline은 찾아보기 섹션을 줄 목록으로 바꾼다: 묶음은 "[ㄱ]", 용어는
+// 수준만큼 들여 쓴 "용어 (위치, ...)".
+func indexOutline(sections []Section) []string {
+	if len(sections) == 0 || sections[len(sections)-1].ID != indexID {
+		return nil
+	}
+	var lines []string
+	depth := 0
+	for _, m := range reTestIndex.FindAllStringSubmatch(sections[len(sections)-1].Content, -1) {
+		switch {
+		case m[0] == "<ul>":
+			depth++
+		case m[0] == "</ul>":
+			depth--
+		case m[1] != "":
+			lines = append(lines, "["+m[1]+"]")
+		default:
+			line := strings.Repeat("  ", depth-1) + m[2]
+			var locators []string
+			for _, l := range reTestLocator.FindAllStringSubmatch(m[3], -1) {
+				locators = append(locators, l[1])
+			}
+			if len(locators) > 0 {
+				line += " (" + strings.Join(locators, ", ") + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestIndexGroup(t *testing.T) {
+	tests := map[string]string{
+		"가나":    "ㄱ",
+		"까치":    "ㄱ", // 된소리는 예사소리에 묶음
+		"힣":     "ㅎ",
+		"ㄴ자형":   "ㄴ",
+		"ㄲ":     "ㄱ",
+		"alpha": "A",
+		"Zeta":  "Z",
+		"1번":    "#",
+		"_tmp":  "#",
+		"émile": "#",
+	}
+	for term, want := range tests {
+		if got := indexGroup(term); got != want {
+			t.Errorf("indexGroup(%q) = %q, want %q", term, got, want)
+		}
+	}
+}
+
+func TestIndexOrder(t *testing.T) {
+	want := []string{"#tag", "1번", "ㄱ", "가나", "까치", "ㄴ자형", "나비", "하늘", "alpha", "Beta", "beta", "zeta"}
+	got := append([]string(nil), want...)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	sort.Slice(got, func(i, j int) bool { return indexLess(got[i], got[j]) })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		terms string // 용어 파일 (Options.IndexTerms)
+		opts  Options
+		want  []string
+	}{
+		// 기호·숫자, 한글 초성, 라틴 문자 순으로 묶음
+		{"groups", map[string]string{
+			"01-intro.md": "# Intro\n\n" +
+				`{{< index "TLS" >}} {{< index "인증서" >}} {{< index "갱신" >}} {{< index "ㄴ자형" >}} ` +
+				`{{< index "까치" >}} {{< index "2FA" >}} {{< index "nginx" >}} {{< index "Apache" >}}` + "\n",
+		}, "", Options{Index: true}, []string{
+			"[#]", "2FA (Intro)",
+			"[ㄱ]", "갱신 (Intro)", "까치 (Intro)",
+			"[ㄴ]", "ㄴ자형 (Intro)",
+			"[ㅇ]", "인증서 (Intro)",
+			"[A]", "Apache (Intro)",
+			"[N]", "nginx (Intro)",
+			"[T]", "TLS (Intro)",
+		}},
+		// "; "로 수준을 나누고, 위치는 섹션별로 한 번
+		{"levels", map[string]string{
+			"01-intro.md": "# Intro\n\n" + `{{< index "인증서; 갱신" "인증서" >}} {{< index "인증서; 발급" >}} {{< index "인증서" >}}` + "\n",
+			"02-ops.md":   "# Operations\n\n" + `{{< index "인증서; 갱신" >}}` + "\n",
+		}, "", Options{Index: true}, []string{
+			"[ㅇ]", "인증서 (Intro)", "  갱신 (Intro, Operations)", "  발급 (Intro)",
+		}},
+		{"heading attribute", map[string]string{
+			"01-intro.md": "# Intro\n\n## Renewal {index=\"certificate; renewal\"}\n",
+		}, "", Options{Index: true}, []string{"[C]", "certificate", "  renewal (Intro)"}},
+		// 용어 파일: 문단마다 한 번, 라틴 문자는 단어 단위·대소문자 무시, 긴 용어 우선
+		{"terms file", map[string]string{
+			"01-intro.md": "# Intro\n\nThe tls setup uses TLS twice.\n\nNo TLSv1 or xTLS here.\n\n" +
+				"A TLS certificate and 인증서를 씁니다.\n\n`TLS` in code, [TLS](x.html) in a link.\n\n## TLS heading\n",
+		}, "TLS: security; TLS\nTLS certificate:\n인증서:\n", Options{}, []string{
+			"[ㅇ]", "인증서 (Intro)",
+			"[S]", "security", "  TLS (Intro)",
+			"[T]", "TLS certificate (Intro)",
+		}},
+		// pass 1은 표시마다 위치를 남기므로 표시 수를 셀 수 있음: 문단마다 한 번,
+		// 긴 용어 안의 짧은 용어는 표시하지 않음
+		{"term marks", map[string]string{
+			"01-intro.md": "# Intro\n\nA TLS certificate here.\n\nTLS and TLS again.\n\nTLS certificate, then TLS.\n",
+		}, "TLS:\nTLS certificate:\n", Options{PDFMode: true}, []string{
+			"[T]", "TLS (?, ?)", "TLS certificate (?, ?)",
+		}},
+		// PDF 모드: 쪽 번호가 있으면 쪽 번호, 같은 쪽은 한 번
+		{"pages", map[string]string{
+			"01-intro.md": "# Intro\n\n" + `{{< index "TLS" >}} {{< index "TLS" >}}` + "\n\n## More\n\n" + `{{< index "TLS" >}}` + "\n",
+		}, "", Options{Index: true, PDFMode: true, Pages: map[string]int{"idx-1": 3, "idx-2": 3, "idx-3": 5}},
+			[]string{"[T]", "TLS (3, 5)"}},
+		// 쪽 번호 분석 전(pass 1)에는 모든 위치를 링크해 PDF 위치를 만듦
+		{"pass 1", map[string]string{
+			"01-intro.md": "# Intro\n\n" + `{{< index "TLS" >}} {{< index "TLS" >}}` + "\n",
+		}, "", Options{Index: true, PDFMode: true}, []string{"[T]", "TLS (?, ?)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, opts := tt.files, tt.opts
+			if tt.terms != "" {
+				files = map[string]string{"terms.yml": tt.terms}
+				for name, content := range tt.files {
+					files[name] = content
+				}
+				opts.IndexTerms = "terms.yml"
+			}
+			b, err := convertDocs(t, files, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := indexOutline(b.sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("index:\n got %q\nwant %q", got, tt.want)
+			}
+			if warnings := b.messages(logging.Warn); len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %q", warnings)
+			}
+		})
+	}
+}
+
+func TestIndexDisabled(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\nText " + `{{< index "TLS" >}}` + "here.\n\n## Renewal {index=\"TLS\"}\n",
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 찾아보기가 없으면 표시는 지우고 섹션도 추가하지 않음
+	if len(b.sections) != 1 {
+		t.Errorf("got %d sections, want 1", len(b.sections))
+	}
+	content := b.sections[0].Content
+	for _, unwanted := range []string{"index-mark", "{{<", `index="`} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("content contains %q:\n%s", unwanted, content)
+		}
+	}
+	if !strings.Contains(content, "<p>Text here.</p>") {
+		t.Errorf("marker not removed cleanly:\n%s", content)
+	}
+}
+
+func TestIndexTermsFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.yml")
+	if err := os.WriteFile(invalid, []byte("- not\n- a map\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		file, want string
+	}{
+		{filepath.Join(dir, "missing.yml"), "Could not read index terms: "},
+		{invalid, "Could not parse index terms: "},
+	} {
+		b, err := convertDocs(t, map[string]string{"01-intro.md": "# Intro\n"}, Options{IndexTerms: tt.file})
+		if err != nil {
+			t.Fatal(err)
+		}
+		warnings := b.messages(logging.Warn)
+		if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+			t.Errorf("%s: warnings = %q, want %q", filepath.Base(tt.file), warnings, tt.want)
+		}
+	}
+}
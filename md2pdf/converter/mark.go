@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// markExtension은 ==text==를 <mark>로 렌더링한다. ~~취소선~~과 같은 경계
+// 규칙을 따르므로 "a == b"와 "==>"는 텍스트로 남는다.
+type markExtension struct{}
+
+func (e *markExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&markParser{}, 500)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&markRenderer{}, 500)))
+}
+
+var kindMark = ast.NewNodeKind("Mark")
+
+type mark struct {
+	ast.BaseInline
+}
+
+func (n *mark) Kind() ast.NodeKind { return kindMark }
+
+func (n *mark) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type markDelimiterProcessor struct{}
+
+func (p *markDelimiterProcessor) IsDelimiter(b byte) bool { return b == '=' }
+
+func (p *markDelimiterProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	return opener.Char == closer.Char
+}
+
+func (p *markDelimiterProcessor) OnMatch(consumes int) ast.Node { return &mark{} }
+
+var defaultMarkDelimiterProcessor = &markDelimiterProcessor{}
+
+type markParser struct{}
+
+func (s *markParser) Trigger() []byte { return []byte{'='} }
+
+func (s *markParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+	node := parser.ScanDelimiter(line, before, 2, defaultMarkDelimiterProcessor)
+	// ===처럼 =가 셋 이상인 연속은 중간부터 다시 읽지 않도록 앞 글자도 확인
+	if node == nil || node.OriginalLength != 2 || before == '=' {
+		return nil
+	}
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+func (s *markParser) CloseBlock(parent ast.Node, pc parser.Context) {}
+
+type markRenderer struct{}
+
+func (r *markRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMark, r.renderMark)
+}
+
+func (r *markRenderer) renderMark(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<mark>")
+	} else {
+		_, _ = w.WriteString("</mark>")
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"highlight", "a ==marked== word", "<p>a <mark>marked</mark> word</p>\n"},
+		{"nested emphasis", "==**bold** text==", "<p><mark><strong>bold</strong> text</mark></p>\n"},
+		// 취소선과 같은 규칙: 양쪽이 공백이거나 =가 더 많으면 텍스트로 남음
+		{"comparison", "a == b", "<p>a == b</p>\n"},
+		{"arrow", "x ==> y", "<p>x ==&gt; y</p>\n"},
+		{"triple", "===x===", "<p>===x===</p>\n"},
+		{"unclosed", "==open", "<p>==open</p>\n"},
+		// 코드 스팬과 코드 블록 안은 그대로
+		{"code span", "`==x==` and ==y==", "<p><code>==x==</code> and <mark>y</mark></p>\n"},
+		{"fenced code", "```\n==x==\n```", "<pre><code>==x==\n</code></pre>\n"},
+	}
+	md := goldmark.New(goldmark.WithExtensions(extension.Strikethrough, &markExtension{}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := md.Convert([]byte(tt.in), &b); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"md2pdf/logging"
+)
+
+// mathExtension은 TeX 수식을 MathML로 렌더링한다: $...$는 인라인, 문단 안의
+// $$...$$는 디스플레이, $$ 블록(구분자가 따로 한 줄씩 있거나 한 줄을 감싼
+// 경우)은 블록 수식이다. Pandoc과 같이 여는 $ 뒤에는 공백이 올 수 없고,
+// 닫는 $ 앞에는 공백이, 뒤에는 숫자가 올 수 없으므로 "$5 and $10" 같은
+// 가격은 텍스트로 남는다.
+type mathExtension struct {
+	log  logging.Printer
+	file string // 변환 중인 Markdown 파일 (경고용)
+}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 90)),
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{ext: e}, 500)))
+}
+
+var (
+	kindMathInline = ast.NewNodeKind("MathInline")
+	kindMathBlock  = ast.NewNodeKind("MathBlock")
+)
+
+// mathInline은 $...$ (또는 문단 안의 $$...$$)
+type mathInline struct {
+	ast.BaseInline
+	tex     string
+	display bool
+	offset  int // 원본 오프셋, 오류 줄 번호용
+}
+
+func (n *mathInline) Kind() ast.NodeKind { return kindMathInline }
+
+func (n *mathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": n.tex}, nil)
+}
+
+// mathBlock은 $$ 디스플레이 블록이며 TeX 원본은 Lines()에 있다.
+type mathBlock struct {
+	ast.BaseBlock
+	closed bool // 닫는 $$를 만났는지
+}
+
+func (n *mathBlock) Kind() ast.NodeKind { return kindMathBlock }
+
+func (n *mathBlock) IsRaw() bool { return true }
+
+func (n *mathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var mathDelimiter = []byte("$$")
+
+type mathBlockParser struct{}
+
+func (b *mathBlockParser) Trigger() []byte { return []byte{'$'} }
+
+func (b *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathDelimiter) {
+		return nil, parser.NoChildren
+	}
+	node := &mathBlock{}
+	rest := line[pos+2:]
+	if end := bytes.Index(rest, mathDelimiter); end >= 0 {
+		// 한 줄의 $$ ... $$, 뒤에 텍스트가 있으면 인라인 수식으로 처리
+		if !util.IsBlank(rest[end+2:]) {
+			return nil, parser.NoChildren
+		}
+		start := segment.Start + pos + 2
+		node.Lines().Append(text.NewSegment(start, start+end))
+		node.closed = true
+	} else if !util.IsBlank(rest) {
+		start := segment.Start + pos + 2
+		node.Lines().Append(text.NewSegment(start, segment.Stop))
+	}
+	advanceLine(reader, line, segment)
+	return node, parser.NoChildren
+}
+
+func (b *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*mathBlock)
+	if n.closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	if end := bytes.Index(line, mathDelimiter); end >= 0 {
+		if end > 0 {
+			n.Lines().Append(text.NewSegment(segment.Start, segment.Start+end))
+		}
+		n.closed = true
+		advanceLine(reader, line, segment)
+		return parser.Close
+	}
+	n.Lines().Append(segment)
+	advanceLine(reader, line, segment)
+	return parser.Continue | parser.NoChildren
+}
+
+// advanceLine은 줄바꿈까지 한 줄을 소비한다. 파일의 마지막 줄에는 줄바꿈이
+// 없을 수 있다.
+func advanceLine(reader text.Reader, line []byte, segment text.Segment) {
+	n := segment.Len()
+	if bytes.HasSuffix(line, []byte("\n")) {
+		n--
+	}
+	reader.Advance(n)
+}
+
+func (b *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *mathBlockParser) CanInterruptParagraph() bool { return true }
+
+func (b *mathBlockParser) CanAcceptIndentedLine() bool { return false }
+
+type mathInlineParser struct{}
+
+func (p *mathInlineParser) Trigger() []byte { return []byte{'$'} }
+
+func (p *mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	if len(line) < 3 {
+		return nil
+	}
+
+	if line[1] == '$' {
+		end := bytes.Index(line[2:], mathDelimiter)
+		if end <= 0 || util.IsBlank(line[2:2+end]) {
+			return nil
+		}
+		block.Advance(end + 4)
+		return &mathInline{tex: string(line[2 : 2+end]), display: true, offset: segment.Start}
+	}
+
+	if util.IsSpace(line[1]) {
+		return nil
+	}
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '$':
+			if util.IsSpace(line[i-1]) || (i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9') {
+				continue
+			}
+			block.Advance(i + 1)
+			return &mathInline{tex: string(line[1:i]), offset: segment.Start}
+		}
+	}
+	return nil
+}
+
+type mathRenderer struct {
+	ext *mathExtension
+}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMathInline, r.renderInline)
+	reg.Register(kindMathBlock, r.renderBlock)
+}
+
+func (r *mathRenderer) renderInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*mathInline)
+		_, _ = w.WriteString(r.convert(source, n.tex, n.display, n.offset))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var tex bytes.Buffer
+	lines := node.Lines()
+	offset := 0
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		if i == 0 {
+			offset = seg.Start
+		}
+		tex.Write(seg.Value(source))
+	}
+	if !node.(*mathBlock).closed {
+		r.ext.log.At(r.ext.file, lineAtOffset(source, offset)).Warnf("Math block is missing the closing $$")
+	}
+	_, _ = w.WriteString(`<div class="math-display">`)
+	_, _ = w.WriteString(r.convert(source, tex.String(), true, offset))
+	_, _ = w.WriteString("</div>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+// convert는 수식 하나를 변환하고 지원하지 않는 TeX를 보고한다.
+func (r *mathRenderer) convert(source []byte, tex string, display bool, offset int) string {
+	mathml, errs := texToMathML(tex, display)
+	for _, err := range errs {
+		r.ext.log.At(r.ext.file, lineAtOffset(source, offset)).Warnf("Math: %v in %q", err, strings.TrimSpace(tex))
+	}
+	return mathml
+}
+
+// lineAtOffset은 원본 오프셋의 줄 번호(1부터)를 반환한다.
+func lineAtOffset(source []byte, offset int) int {
+	if offset > len(source) {
+		offset = len(source)
+	}
+	return bytes.Count(source[:offset], []byte("\n")) + 1
+}
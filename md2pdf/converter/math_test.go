@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+
+	"md2pdf/logging"
+)
+
+// mathBody는 <math> 요소와 annotation을 뺀 tex의 MathML을 반환한다.
+func mathBody(t *testing.T, tex string) string {
+	t.Helper()
+	out, errs := texToMathML(tex, false)
+	if len(errs) > 0 {
+		t.Errorf("texToMathML(%q): %v", tex, errs)
+	}
+	out = strings.TrimPrefix(out, `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics>`)
+	if i := strings.Index(out, "<annotation"); i >= 0 {
+		out = out[:i]
+	}
+	return out
+}
+
+func TestTexToMathML(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want string
+	}{
+		{`\frac{a}{b}`, `<mfrac><mi>a</mi><mi>b</mi></mfrac>`},
+		{`\frac12`, `<mfrac><mn>1</mn><mn>2</mn></mfrac>`},
+		{`\frac{a+1}{2}`, `<mfrac><mrow><mi>a</mi><mo>+</mo><mn>1</mn></mrow><mn>2</mn></mfrac>`},
+		{`x^2`, `<msup><mi>x</mi><mn>2</mn></msup>`},
+		{`x_i`, `<msub><mi>x</mi><mi>i</mi></msub>`},
+		{`x_i^2`, `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>`},
+		{`a_{ij}`, `<msub><mi>a</mi><mrow><mi>i</mi><mi>j</mi></mrow></msub>`},
+		{`\sqrt{x}`, `<msqrt><mi>x</mi></msqrt>`},
+		{`\alpha + 1`, `<mrow><mi>α</mi><mo>+</mo><mn>1</mn></mrow>`},
+	}
+	for _, tt := range tests {
+		if got := mathBody(t, tt.tex); got != tt.want {
+			t.Errorf("texToMathML(%q) = %s, want %s", tt.tex, got, tt.want)
+		}
+	}
+}
+
+func TestTexToMathMLErrors(t *testing.T) {
+	out, errs := texToMathML(`\frac{a}`, true)
+	if len(errs) == 0 {
+		t.Errorf("no error for a missing argument")
+	}
+	if !strings.Contains(out, ` display="block"`) || !strings.Contains(out, `<annotation encoding="application/x-tex">\frac{a}</annotation>`) {
+		t.Errorf("unexpected output %s", out)
+	}
+}
+
+var reMathElement = regexp.MustCompile(`<math[^>]*><semantics>.*?<annotation encoding="application/x-tex">(.*?)</annotation></semantics></math>`)
+
+func TestMathDelimiters(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string // <math ...>...</math>를 [tex]로 바꾼 렌더링 HTML
+	}{
+		{"prices stay text", "It costs $5 and $10.", "<p>It costs $5 and $10.</p>"},
+		{"space after opening", "$ x$", "<p>$ x$</p>"},
+		{"inline", "$x^2$ is", "<p>[x^2] is</p>"},
+		{"escaped dollar", `\$5 $a$`, "<p>$5 [a]</p>"},
+		{"inline display", "a $$x$$ b", "<p>a [x] b</p>"},
+		{"code span", "`$x$`", "<p><code>$x$</code></p>"},
+		{"block", "$$\nx\n$$\n\ntext\n", `<div class="math-display">[x]</div><p>text</p>`},
+		{"block at end of file", "$$\nx\n$$", `<div class="math-display">[x]</div>`},
+		{"one-line block", "$$x$$\n", `<div class="math-display">[x]</div>`},
+	}
+	md := goldmark.New(goldmark.WithExtensions(&mathExtension{log: logging.Use(logging.Discard)}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := md.Convert([]byte(tt.in), &b); err != nil {
+				t.Fatal(err)
+			}
+			got := reMathElement.ReplaceAllString(b.String(), "[$1]")
+			got = strings.ReplaceAll(got, "\n", "")
+			if got != tt.want {
+				t.Errorf("%q = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
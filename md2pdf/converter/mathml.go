@@ -0,0 +1,651 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 기술 문서에서 쓰는 실용적인 LaTeX 부분 집합의 TeX -> MathML 변환: 분수,
+// 근호, 아래/위 첨자, 그리스 문자, 연산자와 관계 기호, 극한이 붙는 큰
+// 연산자, \left...\right 구분자, 악센트, 글꼴 스타일, \text, matrix/cases/
+// aligned 환경을 지원한다. Chrome이 결과를 직접 렌더링하므로(MathML Core)
+// 스크립트나 네트워크 접근이 필요 없다.
+
+// texToMathML은 TeX 원본을 <math> 요소로 변환한다. 지원하지 않는 명령은
+// <merror>로 렌더링하고 반환하는 오류 목록에 보고한다.
+func texToMathML(tex string, display bool) (string, []error) {
+	p := &texParser{src: []rune(tex)}
+	body := p.parseBody()
+
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML"`)
+	if display {
+		b.WriteString(` display="block"`)
+	}
+	b.WriteString(`><semantics>`)
+	b.WriteString(body)
+	b.WriteString(`<annotation encoding="application/x-tex">`)
+	b.WriteString(escapeXML(strings.TrimSpace(tex)))
+	b.WriteString(`</annotation></semantics></math>`)
+	return b.String(), p.errs
+}
+
+// mathNode는 변환한 원자 하나. limits는 첨자가 위아래에 붙는 연산자
+// (\sum, \lim, ...)를 표시한다.
+type mathNode struct {
+	xml    string
+	limits bool
+}
+
+type texParser struct {
+	src     []rune
+	pos     int
+	variant string // 현재 글꼴 명령 (\mathbf, \mathbb, ...)
+	errs    []error
+}
+
+// parseSeq의 종료 조건
+const (
+	stopEnd   = iota // 입력 끝
+	stopGroup        // 닫는 }
+	stopRight        // \right
+	stopCell         // & 또는 \\ (표 칸), \end
+)
+
+func (p *texParser) errorf(format string, args ...interface{}) {
+	p.errs = append(p.errs, fmt.Errorf(format, args...))
+}
+
+// parseBody는 입력 전체를 해석한다. 최상위의 &와 \\는 수식을 정렬된 행으로
+// 배치한다.
+func (p *texParser) parseBody() string {
+	rows := p.parseRows("")
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		return rows[0][0]
+	}
+	return alignedTable(rows)
+}
+
+func (p *texParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *texParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *texParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// peekCommand는 현재 위치의 명령 이름을 소비하지 않고 반환한다(없으면 "").
+func (p *texParser) peekCommand() string {
+	if p.peek() != '\\' || p.pos+1 >= len(p.src) {
+		return ""
+	}
+	i := p.pos + 1
+	if !isASCIILetter(p.src[i]) {
+		return string(p.src[i])
+	}
+	for i < len(p.src) && isASCIILetter(p.src[i]) {
+		i++
+	}
+	return string(p.src[p.pos+1 : i])
+}
+
+func (p *texParser) readCommand() string {
+	name := p.peekCommand()
+	p.pos += 1 + len([]rune(name))
+	return name
+}
+
+// parseSeq는 종료 조건까지 원자를 해석해 한 행으로 반환한다.
+func (p *texParser) parseSeq(stop int) string {
+	var nodes []string
+	for {
+		p.skipSpace()
+		if p.eof() {
+			if stop == stopGroup {
+				p.errorf("missing closing brace")
+			}
+			break
+		}
+		c := p.peek()
+		if c == '}' {
+			if stop == stopGroup {
+				p.pos++
+				break
+			}
+			p.errorf("unexpected closing brace")
+			p.pos++
+			continue
+		}
+		if c == '&' && stop == stopCell {
+			break
+		}
+		if cmd := p.peekCommand(); cmd != "" {
+			if (cmd == "\\" || cmd == "end") && stop == stopCell {
+				break
+			}
+			if cmd == "right" && stop == stopRight {
+				break
+			}
+			if cmd == "displaystyle" || cmd == "textstyle" {
+				p.readCommand()
+				rest := p.parseSeq(stop)
+				nodes = append(nodes, fmt.Sprintf(`<mstyle displaystyle="%t">%s</mstyle>`, cmd == "displaystyle", rest))
+				return row(nodes)
+			}
+		}
+		if c == '&' || p.peekCommand() == "\\" {
+			p.errorf("alignment outside of an environment")
+			p.readCommandOrChar()
+			continue
+		}
+		nodes = append(nodes, p.parseScripted())
+	}
+	return row(nodes)
+}
+
+func (p *texParser) readCommandOrChar() {
+	if p.peek() == '\\' {
+		p.readCommand()
+		return
+	}
+	p.pos++
+}
+
+// parseScripted는 원자와 뒤따르는 아래/위 첨자, 프라임을 해석한다.
+func (p *texParser) parseScripted() string {
+	var base mathNode
+	p.skipSpace()
+	if c := p.peek(); c == '^' || c == '_' {
+		base = mathNode{xml: emptyRow}
+	} else {
+		base = p.parseAtom()
+	}
+
+	var sub, sup string
+	primes := ""
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case '\'':
+			p.pos++
+			primes += "′"
+			continue
+		case '^':
+			p.pos++
+			if sup != "" {
+				p.errorf("double superscript")
+			}
+			sup = p.parseArg().xml
+			continue
+		case '_':
+			p.pos++
+			if sub != "" {
+				p.errorf("double subscript")
+			}
+			sub = p.parseArg().xml
+			continue
+		}
+		break
+	}
+	if primes != "" {
+		sup = row([]string{"<mo>" + primes + "</mo>", sup})
+	}
+
+	switch {
+	case sub == "" && sup == "":
+		return base.xml
+	case base.limits && sub != "" && sup != "":
+		return "<munderover>" + base.xml + sub + sup + "</munderover>"
+	case base.limits && sub != "":
+		return "<munder>" + base.xml + sub + "</munder>"
+	case base.limits:
+		return "<mover>" + base.xml + sup + "</mover>"
+	case sub != "" && sup != "":
+		return "<msubsup>" + base.xml + sub + sup + "</msubsup>"
+	case sub != "":
+		return "<msub>" + base.xml + sub + "</msub>"
+	default:
+		return "<msup>" + base.xml + sup + "</msup>"
+	}
+}
+
+// parseArg는 명령 인수나 첨자를 해석한다: {그룹}, 명령, 또는 한 글자.
+func (p *texParser) parseArg() mathNode {
+	p.skipSpace()
+	if p.eof() {
+		p.errorf("missing argument")
+		return mathNode{xml: emptyRow}
+	}
+	if p.peek() == '{' {
+		p.pos++
+		return mathNode{xml: p.parseSeq(stopGroup)}
+	}
+	if c := p.peek(); isDigit(c) {
+		// 첨자는 숫자 한 개만 받음: x^23은 x² 다음에 3
+		p.pos++
+		return mathNode{xml: "<mn>" + p.styled(string(c)) + "</mn>"}
+	}
+	return p.parseAtom()
+}
+
+// parseAtom은 첨자 없는 원자 하나를 해석한다.
+func (p *texParser) parseAtom() mathNode {
+	c := p.peek()
+	switch {
+	case c == '{':
+		p.pos++
+		return mathNode{xml: p.parseSeq(stopGroup)}
+	case c == '\\':
+		return p.parseCommand(p.readCommand())
+	case isDigit(c) || (c == '.' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1])):
+		start := p.pos
+		for !p.eof() && (isDigit(p.peek()) || (p.peek() == '.' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1]))) {
+			p.pos++
+		}
+		return mathNode{xml: "<mn>" + p.styled(string(p.src[start:p.pos])) + "</mn>"}
+	case isASCIILetter(c):
+		p.pos++
+		return mathNode{xml: p.identifier(string(c))}
+	case c == '~':
+		p.pos++
+		return mathNode{xml: `<mspace width="0.333em"></mspace>`}
+	}
+
+	p.pos++
+	switch c {
+	case '-':
+		return mathNode{xml: "<mo>−</mo>"}
+	case '*':
+		return mathNode{xml: "<mo>∗</mo>"}
+	case '^', '_':
+		p.errorf("misplaced %c", c)
+		return mathNode{xml: emptyRow}
+	}
+	if unicode.IsLetter(c) {
+		return mathNode{xml: "<mi>" + escapeXML(string(c)) + "</mi>"}
+	}
+	return mathNode{xml: "<mo>" + escapeXML(string(c)) + "</mo>"}
+}
+
+// identifier는 글자를 현재 글꼴로 렌더링한다.
+func (p *texParser) identifier(s string) string {
+	if p.variant == "normal" {
+		return `<mi mathvariant="normal">` + escapeXML(s) + "</mi>"
+	}
+	return "<mi>" + p.styled(s) + "</mi>"
+}
+
+// styled는 ASCII 글자와 숫자를 현재 글꼴의 유니코드 수학 영숫자로 바꾼다
+// (MathML Core에는 normal 외의 mathvariant가 없다).
+func (p *texParser) styled(s string) string {
+	font, ok := mathFonts[p.variant]
+	if !ok {
+		return escapeXML(s)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteString(font.apply(r))
+	}
+	return b.String()
+}
+
+func (p *texParser) parseCommand(name string) mathNode {
+	if s, ok := texGreek[name]; ok {
+		if unicode.IsUpper(s) {
+			return mathNode{xml: `<mi mathvariant="normal">` + string(s) + "</mi>"}
+		}
+		return mathNode{xml: "<mi>" + string(s) + "</mi>"}
+	}
+	if s, ok := texIdentifiers[name]; ok {
+		return mathNode{xml: "<mi>" + s + "</mi>"}
+	}
+	if s, ok := texOperators[name]; ok {
+		return mathNode{xml: "<mo>" + escapeXML(s) + "</mo>"}
+	}
+	if s, ok := texBigOperators[name]; ok {
+		// 적분 기호는 극한을 첨자로 유지
+		return mathNode{xml: "<mo>" + s + "</mo>", limits: !strings.Contains(name, "int")}
+	}
+	if texLimitFunctions[name] {
+		return mathNode{xml: `<mo form="prefix" movablelimits="true">` + name + "</mo>", limits: true}
+	}
+	if texFunctions[name] {
+		return mathNode{xml: "<mi>" + name + "</mi>"}
+	}
+	if w, ok := texSpaces[name]; ok {
+		return mathNode{xml: `<mspace width="` + w + `"></mspace>`}
+	}
+	if a, ok := texAccents[name]; ok {
+		arg := p.parseArg().xml
+		return mathNode{xml: `<mover accent="true">` + arg + "<mo" + a.attrs + ">" + a.char + "</mo></mover>"}
+	}
+	if v, ok := texFontCommands[name]; ok {
+		saved := p.variant
+		p.variant = v
+		arg := p.parseArg()
+		p.variant = saved
+		return arg
+	}
+	if size, ok := texBigDelimiters[name]; ok {
+		return mathNode{xml: p.delimiter(fmt.Sprintf(` minsize="%s" maxsize="%s"`, size, size))}
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num, den := p.parseArg().xml, p.parseArg().xml
+		frac := "<mfrac>" + num + den + "</mfrac>"
+		switch name {
+		case "dfrac", "cfrac":
+			frac = `<mstyle displaystyle="true">` + frac + "</mstyle>"
+		case "tfrac":
+			frac = `<mstyle displaystyle="false">` + frac + "</mstyle>"
+		}
+		return mathNode{xml: frac}
+	case "binom", "dbinom", "tbinom":
+		n, k := p.parseArg().xml, p.parseArg().xml
+		return mathNode{xml: `<mrow><mo>(</mo><mfrac linethickness="0">` + n + k + `</mfrac><mo>)</mo></mrow>`}
+	case "sqrt":
+		p.skipSpace()
+		if p.peek() == '[' {
+			p.pos++
+			index := p.parseUntil(']')
+			return mathNode{xml: "<mroot>" + p.parseArg().xml + index + "</mroot>"}
+		}
+		return mathNode{xml: "<msqrt>" + p.parseArg().xml + "</msqrt>"}
+	case "text", "textrm", "textup", "mbox", "textnormal":
+		return mathNode{xml: "<mtext>" + escapeXML(p.rawArg()) + "</mtext>"}
+	case "textbf":
+		return mathNode{xml: "<mtext>" + mathFonts["bold"].applyString(p.rawArg()) + "</mtext>"}
+	case "textit":
+		return mathNode{xml: "<mtext>" + mathFonts["italic"].applyString(p.rawArg()) + "</mtext>"}
+	case "operatorname":
+		return mathNode{xml: "<mi>" + escapeXML(strings.TrimSpace(p.rawArg())) + "</mi>"}
+	case "overline":
+		return mathNode{xml: `<mover accent="true">` + p.parseArg().xml + `<mo stretchy="true">¯</mo></mover>`}
+	case "underline":
+		return mathNode{xml: `<munder accentunder="true">` + p.parseArg().xml + `<mo stretchy="true">_</mo></munder>`}
+	case "overbrace":
+		return mathNode{xml: "<mover>" + p.parseArg().xml + `<mo stretchy="true">⏞</mo></mover>`, limits: true}
+	case "underbrace":
+		return mathNode{xml: "<munder>" + p.parseArg().xml + `<mo stretchy="true">⏟</mo></munder>`, limits: true}
+	case "overset", "stackrel":
+		over, base := p.parseArg().xml, p.parseArg().xml
+		return mathNode{xml: "<mover>" + base + over + "</mover>"}
+	case "underset":
+		under, base := p.parseArg().xml, p.parseArg().xml
+		return mathNode{xml: "<munder>" + base + under + "</munder>"}
+	case "not":
+		next := p.parseAtom().xml
+		if strings.HasPrefix(next, "<mo>") {
+			return mathNode{xml: strings.Replace(next, "</mo>", "̸</mo>", 1)}
+		}
+		return mathNode{xml: "<mrow><mo>/</mo>" + next + "</mrow>"}
+	case "pmod":
+		return mathNode{xml: `<mrow><mspace width="0.444em"></mspace><mo>(</mo><mi>mod</mi><mspace width="0.333em"></mspace>` + p.parseArg().xml + "<mo>)</mo></mrow>"}
+	case "bmod", "mod":
+		return mathNode{xml: `<mo lspace="0.2222em" rspace="0.2222em">mod</mo>`}
+	case "left":
+		open := p.delimiter(` fence="true" form="prefix" stretchy="true"`)
+		body := p.parseSeq(stopRight)
+		close := ""
+		if p.peekCommand() == "right" {
+			p.readCommand()
+			close = p.delimiter(` fence="true" form="postfix" stretchy="true"`)
+		} else {
+			p.errorf(`\left without \right`)
+		}
+		return mathNode{xml: "<mrow>" + open + body + close + "</mrow>"}
+	case "middle":
+		return mathNode{xml: p.delimiter(` stretchy="true"`)}
+	case "right":
+		p.errorf(`\right without \left`)
+		p.delimiter("")
+		return mathNode{xml: emptyRow}
+	case "begin":
+		return mathNode{xml: p.parseEnvironment(p.rawArg())}
+	}
+
+	p.errorf(`unsupported command \%s`, name)
+	return mathNode{xml: `<merror><mtext>\` + escapeXML(name) + "</mtext></merror>"}
+}
+
+// rawArg는 {그룹}(또는 한 글자)을 그대로 읽는다.
+func (p *texParser) rawArg() string {
+	p.skipSpace()
+	if p.peek() != '{' {
+		if p.eof() {
+			p.errorf("missing argument")
+			return ""
+		}
+		p.pos++
+		return string(p.src[p.pos-1])
+	}
+	p.pos++
+	start, depth := p.pos, 1
+	for !p.eof() {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				s := string(p.src[start:p.pos])
+				p.pos++
+				return s
+			}
+		}
+		p.pos++
+	}
+	p.errorf("missing closing brace")
+	return string(p.src[start:])
+}
+
+// parseUntil은 닫는 대괄호까지 선택 인수를 해석한다.
+func (p *texParser) parseUntil(end rune) string {
+	start := p.pos
+	for !p.eof() && p.peek() != end {
+		p.pos++
+	}
+	sub := &texParser{src: p.src[start:p.pos], variant: p.variant}
+	xml := sub.parseSeq(stopEnd)
+	p.errs = append(p.errs, sub.errs...)
+	if p.eof() {
+		p.errorf("missing %c", end)
+	} else {
+		p.pos++
+	}
+	return xml
+}
+
+// delimiter는 \left, \right, \big 등의 뒤에 오는 구분자를 읽는다. "."는
+// 빈 구분자다.
+func (p *texParser) delimiter(attrs string) string {
+	p.skipSpace()
+	if p.eof() {
+		p.errorf("missing delimiter")
+		return ""
+	}
+	var d string
+	if p.peek() == '\\' {
+		name := p.readCommand()
+		s, ok := texDelimiters[name]
+		if !ok {
+			p.errorf(`invalid delimiter \%s`, name)
+			return ""
+		}
+		d = s
+	} else {
+		d = string(p.peek())
+		p.pos++
+	}
+	if d == "." {
+		return ""
+	}
+	return "<mo" + attrs + ">" + escapeXML(d) + "</mo>"
+}
+
+// parseRows는 \end{env}까지(env가 비어 있으면 입력 끝까지) 표의 행을
+// 해석한다. 칸은 &, 행은 \\로 구분한다.
+func (p *texParser) parseRows(env string) [][]string {
+	var rows [][]string
+	var cells []string
+	for {
+		cells = append(cells, p.parseSeq(stopCell))
+		p.skipSpace()
+		if p.peek() == '&' {
+			p.pos++
+			continue
+		}
+		switch p.peekCommand() {
+		case "\\":
+			p.readCommand()
+			// 선택적 행 간격: \\[2pt]
+			if p.skipSpace(); p.peek() == '[' {
+				for !p.eof() && p.peek() != ']' {
+					p.pos++
+				}
+				p.pos++
+			}
+			rows = append(rows, cells)
+			cells = nil
+			continue
+		case "end":
+			p.readCommand()
+			if name := p.rawArg(); name != env {
+				p.errorf(`\begin{%s} ended by \end{%s}`, env, name)
+			}
+		default:
+			if env != "" {
+				p.errorf(`missing \end{%s}`, env)
+			}
+		}
+		break
+	}
+	// 끝의 \\는 빈 마지막 행을 남김
+	if len(cells) != 1 || cells[0] != emptyRow || len(rows) == 0 {
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func (p *texParser) parseEnvironment(env string) string {
+	switch env {
+	case "array":
+		spec := p.rawArg()
+		var align []string
+		for _, c := range spec {
+			switch c {
+			case 'l':
+				align = append(align, "left")
+			case 'c':
+				align = append(align, "center")
+			case 'r':
+				align = append(align, "right")
+			}
+		}
+		return table(p.parseRows(env), align)
+	case "matrix", "smallmatrix":
+		return table(p.parseRows(env), nil)
+	case "pmatrix", "bmatrix", "Bmatrix", "vmatrix", "Vmatrix":
+		fences := map[string][2]string{
+			"pmatrix": {"(", ")"}, "bmatrix": {"[", "]"}, "Bmatrix": {"{", "}"},
+			"vmatrix": {"|", "|"}, "Vmatrix": {"‖", "‖"},
+		}[env]
+		return `<mrow><mo fence="true" form="prefix">` + fences[0] + `</mo>` + table(p.parseRows(env), nil) +
+			`<mo fence="true" form="postfix">` + fences[1] + `</mo></mrow>`
+	case "cases":
+		return `<mrow><mo fence="true" form="prefix">{</mo>` + table(p.parseRows(env), []string{"left", "left"}) + `</mrow>`
+	case "aligned", "align", "align*", "split", "gathered", "gather", "gather*", "equation", "equation*", "eqnarray", "eqnarray*":
+		return alignedTable(p.parseRows(env))
+	}
+	p.errorf("unsupported environment %s", env)
+	p.parseRows(env)
+	return `<merror><mtext>` + escapeXML(env) + `</mtext></merror>`
+}
+
+// table은 행들을 <mtable>로 렌더링한다. align은 열별 정렬이다(마지막
+// 값을 반복).
+func table(rows [][]string, align []string) string {
+	var b strings.Builder
+	b.WriteString("<mtable>")
+	for _, cells := range rows {
+		b.WriteString("<mtr>")
+		for i, cell := range cells {
+			a := ""
+			if len(align) > 0 {
+				a = align[len(align)-1]
+				if i < len(align) {
+					a = align[i]
+				}
+			}
+			if a != "" && a != "center" {
+				fmt.Fprintf(&b, `<mtd style="text-align: %s">%s</mtd>`, a, cell)
+			} else {
+				b.WriteString("<mtd>" + cell + "</mtd>")
+			}
+		}
+		b.WriteString("</mtr>")
+	}
+	b.WriteString("</mtable>")
+	return b.String()
+}
+
+// alignedTable은 align 형식의 행을 렌더링한다: 열은 오른쪽/왼쪽 정렬을
+// 번갈아 쓰고 두 열을 간격 없이 붙이므로 "a &= b"는 관계 기호에서 정렬된다.
+func alignedTable(rows [][]string) string {
+	multi := false
+	for _, cells := range rows {
+		multi = multi || len(cells) > 1
+	}
+	if !multi {
+		return `<mtable displaystyle="true">` + strings.TrimPrefix(table(rows, nil), "<mtable>")
+	}
+	var b strings.Builder
+	b.WriteString(`<mtable displaystyle="true">`)
+	for _, cells := range rows {
+		b.WriteString("<mtr>")
+		for i, cell := range cells {
+			if i%2 == 0 {
+				fmt.Fprintf(&b, `<mtd style="text-align: right; padding-right: 0">%s</mtd>`, cell)
+			} else {
+				fmt.Fprintf(&b, `<mtd style="text-align: left; padding-left: 0">%s</mtd>`, cell)
+			}
+		}
+		b.WriteString("</mtr>")
+	}
+	b.WriteString("</mtable>")
+	return b.String()
+}
+
+const emptyRow = "<mrow></mrow>"
+
+func row(nodes []string) string {
+	var parts []string
+	for _, n := range nodes {
+		if n != "" {
+			parts = append(parts, n)
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "<mrow>" + strings.Join(parts, "") + "</mrow>"
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func isASCIILetter(r rune) bool { return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') }
+
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
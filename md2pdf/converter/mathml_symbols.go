@@ -0,0 +1,153 @@
+package converter
+
+// texToMathML의 기호 표
+
+var texGreek = map[string]rune{
+	"alpha": 'α', "beta": 'β', "gamma": 'γ', "delta": 'δ', "epsilon": 'ϵ', "varepsilon": 'ε',
+	"zeta": 'ζ', "eta": 'η', "theta": 'θ', "vartheta": 'ϑ', "iota": 'ι', "kappa": 'κ',
+	"lambda": 'λ', "mu": 'μ', "nu": 'ν', "xi": 'ξ', "omicron": 'ο', "pi": 'π', "varpi": 'ϖ',
+	"rho": 'ρ', "varrho": 'ϱ', "sigma": 'σ', "varsigma": 'ς', "tau": 'τ', "upsilon": 'υ',
+	"phi": 'ϕ', "varphi": 'φ', "chi": 'χ', "psi": 'ψ', "omega": 'ω',
+	"Gamma": 'Γ', "Delta": 'Δ', "Theta": 'Θ', "Lambda": 'Λ', "Xi": 'Ξ', "Pi": 'Π',
+	"Sigma": 'Σ', "Upsilon": 'Υ', "Phi": 'Φ', "Psi": 'Ψ', "Omega": 'Ω',
+}
+
+var texIdentifiers = map[string]string{
+	"infty": "∞", "partial": "∂", "nabla": "∇", "emptyset": "∅", "varnothing": "∅",
+	"ell": "ℓ", "hbar": "ℏ", "imath": "ı", "jmath": "ȷ", "Re": "ℜ", "Im": "ℑ",
+	"aleph": "ℵ", "wp": "℘", "complement": "∁",
+}
+
+var texOperators = map[string]string{
+	// 관계 기호
+	"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "leqslant": "⩽", "geqslant": "⩾",
+	"ne": "≠", "neq": "≠", "approx": "≈", "equiv": "≡", "sim": "∼", "simeq": "≃",
+	"cong": "≅", "propto": "∝", "ll": "≪", "gg": "≫", "prec": "≺", "succ": "≻",
+	"preceq": "⪯", "succeq": "⪰", "perp": "⊥", "parallel": "∥", "mid": "∣",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "supset": "⊃",
+	"subseteq": "⊆", "supseteq": "⊇", "models": "⊨", "vdash": "⊢", "doteq": "≐",
+	"coloneqq": "≔", "triangleq": "≜",
+	// 이항 연산자
+	"pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "⋅", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "∙", "cup": "∪", "cap": "∩", "setminus": "∖",
+	"vee": "∨", "lor": "∨", "wedge": "∧", "land": "∧", "oplus": "⊕", "ominus": "⊖",
+	"otimes": "⊗", "odot": "⊙", "dagger": "†", "ddagger": "‡",
+	// 화살표
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←", "leftrightarrow": "↔",
+	"Rightarrow": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "iff": "⟺",
+	"implies": "⟹", "impliedby": "⟸", "mapsto": "↦", "longrightarrow": "⟶",
+	"longleftarrow": "⟵", "uparrow": "↑", "downarrow": "↓", "Uparrow": "⇑", "Downarrow": "⇓",
+	"rightleftharpoons": "⇌",
+	// 논리 기호와 기타
+	"forall": "∀", "exists": "∃", "nexists": "∄", "neg": "¬", "lnot": "¬",
+	"therefore": "∴", "because": "∵", "colon": ":", "angle": "∠", "triangle": "△",
+	"degree": "°", "prime": "′", "top": "⊤", "bot": "⊥", "backslash": "\\",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+	// \left/\right 없이 쓰는 구분자
+	"{": "{", "}": "}", "|": "‖", "vert": "|", "Vert": "‖", "lvert": "|", "rvert": "|",
+	"lVert": "‖", "rVert": "‖", "langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋",
+	"lceil": "⌈", "rceil": "⌉", "lbrace": "{", "rbrace": "}", "lbrack": "[", "rbrack": "]",
+	"#": "#", "$": "$", "%": "%", "&": "&", "_": "_",
+}
+
+// 큰 연산자. 적분 기호를 제외하면 극한이 위아래에 붙는다
+var texBigOperators = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "bigcup": "⋃", "bigcap": "⋂",
+	"bigoplus": "⨁", "bigotimes": "⨂", "bigvee": "⋁", "bigwedge": "⋀",
+	"int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+}
+
+var texLimitFunctions = map[string]bool{
+	"lim": true, "limsup": true, "liminf": true, "max": true, "min": true,
+	"sup": true, "inf": true, "det": true, "gcd": true, "Pr": true, "argmax": true, "argmin": true,
+}
+
+var texFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+	"log": true, "ln": true, "lg": true, "exp": true, "arg": true, "deg": true,
+	"dim": true, "ker": true, "hom": true,
+}
+
+var texSpaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em", "!": "-0.1667em",
+	" ": "0.333em", "quad": "1em", "qquad": "2em",
+}
+
+type texAccent struct {
+	char  string
+	attrs string
+}
+
+var texAccents = map[string]texAccent{
+	"hat": {"^", ""}, "widehat": {"^", ` stretchy="true"`}, "bar": {"¯", ""},
+	"vec": {"→", ""}, "overrightarrow": {"→", ` stretchy="true"`}, "dot": {"˙", ""},
+	"ddot": {"¨", ""}, "tilde": {"~", ""}, "widetilde": {"~", ` stretchy="true"`},
+	"check": {"ˇ", ""}, "breve": {"˘", ""}, "acute": {"´", ""}, "grave": {"`", ""},
+}
+
+var texFontCommands = map[string]string{
+	"mathrm": "normal", "mathup": "normal", "mathbf": "bold", "mathit": "italic",
+	"boldsymbol": "bold-italic", "bm": "bold-italic", "mathbb": "double-struck",
+	"mathcal": "script", "mathscr": "script", "mathfrak": "fraktur",
+	"mathsf": "sans-serif", "mathtt": "monospace",
+}
+
+var texBigDelimiters = map[string]string{
+	"big": "1.2em", "bigl": "1.2em", "bigr": "1.2em", "bigm": "1.2em",
+	"Big": "1.8em", "Bigl": "1.8em", "Bigr": "1.8em", "Bigm": "1.8em",
+	"bigg": "2.4em", "biggl": "2.4em", "biggr": "2.4em", "biggm": "2.4em",
+	"Bigg": "3em", "Biggl": "3em", "Biggr": "3em", "Biggm": "3em",
+}
+
+var texDelimiters = map[string]string{
+	"{": "{", "}": "}", "|": "‖", "lbrace": "{", "rbrace": "}", "langle": "⟨", "rangle": "⟩",
+	"lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉", "vert": "|", "Vert": "‖",
+	"lvert": "|", "rvert": "|", "lVert": "‖", "rVert": "‖", "backslash": "\\",
+	"uparrow": "↑", "downarrow": "↓",
+}
+
+// mathFont는 ASCII 글자와 숫자를 수학 영숫자 기호(Mathematical
+// Alphanumeric Symbols) 블록으로 대응시킨다.
+type mathFont struct {
+	upper, lower, digit rune          // A, a, 0의 첫 코드 포인트 (0 = 바꾸지 않음)
+	holes               map[rune]rune // 블록 밖에 인코딩된 글자
+}
+
+func (f mathFont) apply(r rune) string {
+	if h, ok := f.holes[r]; ok {
+		return string(h)
+	}
+	switch {
+	case r >= 'A' && r <= 'Z' && f.upper != 0:
+		return string(f.upper + r - 'A')
+	case r >= 'a' && r <= 'z' && f.lower != 0:
+		return string(f.lower + r - 'a')
+	case r >= '0' && r <= '9' && f.digit != 0:
+		return string(f.digit + r - '0')
+	}
+	return escapeXML(string(r))
+}
+
+func (f mathFont) applyString(s string) string {
+	out := ""
+	for _, r := range s {
+		out += f.apply(r)
+	}
+	return out
+}
+
+var mathFonts = map[string]mathFont{
+	"bold":        {upper: 0x1D400, lower: 0x1D41A, digit: 0x1D7CE},
+	"italic":      {upper: 0x1D434, lower: 0x1D44E, holes: map[rune]rune{'h': 'ℎ'}},
+	"bold-italic": {upper: 0x1D468, lower: 0x1D482, digit: 0x1D7CE},
+	"double-struck": {upper: 0x1D538, lower: 0x1D552, digit: 0x1D7D8, holes: map[rune]rune{
+		'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ'}},
+	"script": {upper: 0x1D49C, lower: 0x1D4B6, holes: map[rune]rune{
+		'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ',
+		'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ'}},
+	"fraktur": {upper: 0x1D504, lower: 0x1D51E, holes: map[rune]rune{
+		'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ'}},
+	"sans-serif": {upper: 0x1D5A0, lower: 0x1D5BA, digit: 0x1D7E2},
+	"monospace":  {upper: 0x1D670, lower: 0x1D68A, digit: 0x1D7F6},
+}
@@ -0,0 +1,257 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+
+	"md2pdf/logging"
+)
+
+// Mermaid 다이어그램은 빌드할 때 DiagramRenderer로 SVG로 렌더링하고 원본
+// 해시로 디스크에 캐시한다. 출력에는 정적 SVG가 들어가므로 인쇄가 브라우저
+// JavaScript에 의존하지 않는다. 렌더러를 주지 않은 경우(예: serve)에는
+// 브라우저의 mermaid.js로 렌더링하고, 렌더러가 실패해 이렇게 대체하면 위치와
+// 함께 경고한다. 오프라인 빌드에서 Mermaid가 내장되어 있지 않으면 실패한다.
+
+// Diagram은 렌더링할 Mermaid 다이어그램
+type Diagram struct {
+	ID     string // SVG 요소 ID (문서에 넣을 때 나온 순서별 ID로 바뀜)
+	Source string
+}
+
+// DiagramResult는 다이어그램 하나의 SVG 또는 Mermaid 오류 메시지
+type DiagramResult struct {
+	SVG   string
+	Error string
+}
+
+// DiagramRenderer는 Mermaid 다이어그램을 SVG로 렌더링한다. Mermaid 라이브러리는
+// JavaScript 원본(script)으로, script가 비어 있으면 URL로 주어진다.
+// 구문 오류는 결과에 다이어그램별로 보고한다.
+type DiagramRenderer interface {
+	RenderMermaid(script, url string, diagrams []Diagram) ([]DiagramResult, error)
+}
+
+// mermaidVersion은 고정한 Mermaid 릴리스. 템플릿, 내장 스크립트와 같아야
+// 한다.
+const (
+	mermaidVersion = "10.9.1"
+	mermaidURL     = "https://cdn.jsdelivr.net/npm/mermaid@" + mermaidVersion + "/dist/mermaid.min.js"
+)
+
+var (
+	reMermaidCode   = regexp.MustCompile(`(?s)<pre><code class="language-mermaid">(.*?)</code></pre>`)
+	reMermaidScript = regexp.MustCompile(`<script src="https://cdn\.jsdelivr\.net/npm/mermaid(?:@[^/"]*)?/[^"]*"></script>`)
+	reMermaidInit   = regexp.MustCompile(`(?s)mermaid\.initialize\(\{.*?\}\);`)
+	reMermaidLine   = regexp.MustCompile(`\bline (\d+)`)
+)
+
+// mermaidBlock은 Markdown 파일의 mermaid 코드 블록
+type mermaidBlock struct {
+	source string
+	line   int // 여는 펜스의 줄
+}
+
+// collectMermaid는 문서의 mermaid 코드 블록을 순서대로 반환한다.
+func collectMermaid(doc ast.Node, source []byte) []mermaidBlock {
+	var blocks []mermaidBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		fcb, ok := n.(*ast.FencedCodeBlock)
+		if !entering || !ok || string(fcb.Language(source)) != "mermaid" {
+			return ast.WalkContinue, nil
+		}
+		var b strings.Builder
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			b.Write(seg.Value(source))
+		}
+		blocks = append(blocks, mermaidBlock{source: b.String(), line: lineAtOffset(source, fcb.Info.Segment.Start)})
+		return ast.WalkSkipChildren, nil
+	})
+	return blocks
+}
+
+// mermaidRenderer는 변환 한 번 동안 mermaid 코드 블록을 캐시된 SVG나 새로
+// 렌더링한 SVG로 바꾼다.
+type mermaidRenderer struct {
+	renderer DiagramRenderer
+	dir      string // 캐시 디렉터리 ("" = 디스크 캐시 없음)
+	script   string // 내장 Mermaid 라이브러리 (있으면)
+	url      string // 내장되지 않았을 때의 Mermaid URL (오프라인 빌드는 "")
+	version  string // Mermaid 릴리스, 캐시 키에 포함
+	log      logging.Printer
+
+	client   bool // 일부 다이어그램을 브라우저의 mermaid.js에 맡김
+	disabled bool // 렌더러가 실패함, 나머지는 브라우저에서 렌더링
+	count    int  // 문서에 넣은 SVG 수 (나온 순서별 ID용)
+}
+
+func newMermaidRenderer(opts Options, log logging.Printer) *mermaidRenderer {
+	m := &mermaidRenderer{renderer: opts.Diagrams, dir: opts.DiagramCache, version: mermaidVersion, log: log}
+	if m.dir == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			m.dir = filepath.Join(dir, "md2pdf", "mermaid")
+		}
+	}
+	if p, ok := assetPath(mermaidURL); ok {
+		if data, err := fs.ReadFile(assetFS, p); err == nil {
+			m.script = string(data)
+		}
+	}
+	if m.script == "" {
+		if !opts.Offline {
+			m.url = mermaidURL
+		}
+	}
+	return m
+}
+
+// key는 다이어그램 원본의 캐시 키를 반환한다.
+func (m *mermaidRenderer) key(source string) string {
+	sum := sha256.Sum256([]byte(m.version + "\x00default\x00" + source))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *mermaidRenderer) load(key string) (string, bool) {
+	if m.dir == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(filepath.Join(m.dir, key+".svg"))
+	return string(data), err == nil
+}
+
+func (m *mermaidRenderer) store(key, svg string) {
+	if m.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		m.log.Debugf("Mermaid cache disabled: %v", err)
+		m.dir = ""
+		return
+	}
+	// 동시에 실행된 빌드가 쓰다 만 파일을 읽지 않도록 임시 파일에 쓰고 이름 변경
+	tmp, err := os.CreateTemp(m.dir, key+".*.tmp")
+	if err != nil {
+		m.log.Debugf("Mermaid cache disabled: %v", err)
+		m.dir = ""
+		return
+	}
+	_, err = tmp.WriteString(svg)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(m.dir, key+".svg"))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		m.log.Debugf("Could not cache Mermaid diagram: %v", err)
+	}
+}
+
+// replace는 file을 렌더링한 htmlContent의 mermaid 코드 블록을 SVG로 바꾼다.
+// Mermaid 구문 오류가 있으면 변환이 실패한다.
+func (m *mermaidRenderer) replace(htmlContent, file string, blocks []mermaidBlock) (string, error) {
+	if len(blocks) == 0 {
+		return htmlContent, nil
+	}
+
+	// 같은 원본은 한 번만 렌더링하고 캐시 키(원본 해시)로 공유
+	svgs := make(map[string]string)
+	var missing []int
+	for i, b := range blocks {
+		key := m.key(b.source)
+		if _, seen := svgs[key]; seen {
+			continue
+		}
+		svg, ok := m.load(key)
+		svgs[key] = svg
+		if !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	reason := ""
+	switch {
+	case len(missing) == 0:
+	case m.renderer == nil:
+		// 렌더러를 주지 않은 호출(예: serve)은 브라우저 렌더링을 선택한 것
+	case m.disabled:
+		reason = "the diagram renderer failed earlier"
+	case m.script == "" && m.url == "":
+		line := blocks[missing[0]].line
+		m.log.At(file, line).Errorf("Mermaid is not vendored, so diagrams cannot be rendered in an offline build")
+		return htmlContent, fmt.Errorf("offline build: Mermaid is not vendored (%s:%d); run `go run ./scripts/fetch_assets.go`", file, line)
+	default:
+		diagrams := make([]Diagram, len(missing))
+		for j, i := range missing {
+			diagrams[j] = Diagram{ID: mermaidID(m.key(blocks[i].source)), Source: blocks[i].source}
+		}
+		m.log.Infof("Rendering %d Mermaid diagrams (%s)", len(diagrams), filepath.Base(file))
+		results, err := m.renderer.RenderMermaid(m.script, m.url, diagrams)
+		if err != nil {
+			reason = err.Error()
+			m.disabled = true
+		}
+		for j, r := range results {
+			i := missing[j]
+			if r.Error != "" {
+				line := blocks[i].line
+				if sub := reMermaidLine.FindStringSubmatch(r.Error); sub != nil {
+					n, _ := strconv.Atoi(sub[1])
+					line += n
+				}
+				m.log.At(file, line).Errorf("Mermaid syntax error: %s", r.Error)
+				return htmlContent, fmt.Errorf("Mermaid syntax error in %s:%d", file, line)
+			}
+			key := m.key(blocks[i].source)
+			svgs[key] = r.SVG
+			m.store(key, r.SVG)
+		}
+	}
+
+	// 사전 렌더링하지 못한 다이어그램은 mermaid.js로 인쇄되므로 위치와 함께 경고
+	i := 0
+	htmlContent = reMermaidCode.ReplaceAllStringFunc(htmlContent, func(code string) string {
+		defer func() { i++ }()
+		if i < len(blocks) {
+			key := m.key(blocks[i].source)
+			if svg := svgs[key]; svg != "" {
+				// 같은 다이어그램이 여러 번 나와도 SVG ID(와 ID를 참조하는
+				// 내부 스타일·마커)가 겹치지 않도록 나올 때마다 새 ID를 붙임
+				m.count++
+				id := fmt.Sprintf("%s-%d", mermaidID(key), m.count)
+				return `<div class="mermaid" data-processed="true">` + strings.ReplaceAll(svg, mermaidID(key), id) + `</div>`
+			}
+			if reason != "" {
+				m.log.At(file, blocks[i].line).Warnf("Mermaid diagram was not pre-rendered (%s); it is rendered by mermaid.js in the browser", reason)
+			}
+		}
+		m.client = true
+		return `<div class="mermaid">` + reMermaidCode.FindStringSubmatch(code)[1] + `</div>`
+	})
+	return htmlContent, nil
+}
+
+// mermaidID는 캐시 키로 만든 다이어그램의 SVG ID. 캐시된 SVG에 들어 있으므로
+// 문서에 넣을 때 나온 순서별 ID로 바꾼다.
+func mermaidID(key string) string {
+	return "mermaid-" + key[:12]
+}
+
+// stripMermaidScript는 모든 다이어그램을 미리 렌더링했을 때 템플릿에서
+// mermaid.js를 제거한다.
+func stripMermaidScript(tmpl string) string {
+	tmpl = reMermaidScript.ReplaceAllString(tmpl, "")
+	return reMermaidInit.ReplaceAllString(tmpl, "")
+}
@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"md2pdf/logging"
+)
+
+// fakeDiagrams는 원본을 그대로 담은 SVG를 돌려주는 DiagramRenderer.
+// 원본에 "bad"가 있으면 Mermaid처럼 줄 번호가 든 구문 오류를 보고한다.
+type fakeDiagrams struct {
+	err      error
+	calls    int
+	rendered []string
+}
+
+func (f *fakeDiagrams) RenderMermaid(script, url string, diagrams []Diagram) ([]DiagramResult, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make([]DiagramResult, len(diagrams))
+	for i, d := range diagrams {
+		f.rendered = append(f.rendered, strings.TrimSpace(d.Source))
+		if strings.Contains(d.Source, "bad") {
+			results[i].Error = "Parse error on line 2:\n...unexpected token"
+			continue
+		}
+		results[i].SVG = `<svg id="` + d.ID + `"><style>#` + d.ID + ` .node{}</style><marker id="` + d.ID + `_arrow"/>` +
+			strings.TrimSpace(d.Source) + `</svg>`
+	}
+	return results, nil
+}
+
+// useVendoredMermaid는 내장 Mermaid 스크립트가 있는 것처럼 자산을 바꾼다.
+func useVendoredMermaid(t *testing.T) {
+	t.Helper()
+	p, _ := assetPath(mermaidURL)
+	useAssets(t, fstest.MapFS{p: {Data: []byte("mermaid")}})
+}
+
+var reSVGID = regexp.MustCompile(`<svg id="([^"]+)"`)
+
+func TestMermaidCache(t *testing.T) {
+	useVendoredMermaid(t)
+	files := map[string]string{
+		"01-a.md": "# A\n\n```mermaid\ngraph TD; A-->B\n```\n\n```mermaid\ngraph TD; C-->D\n```\n",
+		"02-b.md": "# B\n\n```mermaid\ngraph TD; A-->B\n```\n",
+	}
+	cache := t.TempDir()
+
+	// 같은 원본은 한 번만 렌더링
+	first := &fakeDiagrams{}
+	b, err := convertDocs(t, files, Options{Diagrams: first, DiagramCache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(first.rendered, "|"); got != "graph TD; A-->B|graph TD; C-->D" {
+		t.Errorf("first build rendered %q", got)
+	}
+
+	// 두 번째 빌드는 모두 캐시에서 읽고 렌더러를 부르지 않음
+	second := &fakeDiagrams{}
+	b2, err := convertDocs(t, files, Options{Diagrams: second, DiagramCache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.calls != 0 {
+		t.Errorf("cached build called the renderer %d times", second.calls)
+	}
+	for _, build := range []*testBuild{b, b2} {
+		if n := strings.Count(build.body(), `<div class="mermaid" data-processed="true"><svg`); n != 3 {
+			t.Errorf("%d pre-rendered diagrams, want 3", n)
+		}
+		if strings.Contains(build.html, "mermaid.initialize") || strings.Contains(build.html, "mermaid.min.js") {
+			t.Error("mermaid.js is loaded although every diagram was pre-rendered")
+		}
+	}
+}
+
+func TestMermaidUniqueIDs(t *testing.T) {
+	useVendoredMermaid(t)
+	b, err := convertDocs(t, map[string]string{
+		"01-a.md": "# A\n\n```mermaid\ngraph TD; A-->B\n```\n\n```mermaid\ngraph TD; A-->B\n```\n",
+		"02-b.md": "# B\n\n```mermaid\ngraph TD; A-->B\n```\n",
+	}, Options{Diagrams: &fakeDiagrams{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, m := range reSVGID.FindAllStringSubmatch(b.body(), -1) {
+		id := m[1]
+		if seen[id] {
+			t.Errorf("duplicate SVG ID %s", id)
+		}
+		seen[id] = true
+		// ID를 참조하는 내부 스타일과 마커도 같은 ID로 바뀜
+		if !strings.Contains(b.body(), "#"+id+" .node") || !strings.Contains(b.body(), `id="`+id+`_arrow"`) {
+			t.Errorf("styles or markers of %s still use another ID", id)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("%d SVG IDs, want 3", len(seen))
+	}
+}
+
+func TestMermaidSyntaxError(t *testing.T) {
+	useVendoredMermaid(t)
+	// 여는 펜스가 5번째 줄이고 Mermaid 오류가 다이어그램의 2번째 줄이면 7번째 줄
+	b, err := convertDocs(t, map[string]string{
+		"guide.md": "# Guide\n\nText.\n\n```mermaid\ngraph TD\n  A-->bad\n```\n",
+	}, Options{Diagrams: &fakeDiagrams{}})
+	if err == nil || !strings.Contains(err.Error(), "guide.md:7") {
+		t.Fatalf("error = %v, want guide.md:7", err)
+	}
+	if got := strings.Join(b.messages(logging.Error), "\n"); !strings.HasPrefix(got, "guide.md:7: Mermaid syntax error: Parse error on line 2") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestMermaidFallback(t *testing.T) {
+	doc := map[string]string{"guide.md": "# Guide\n\n```mermaid\ngraph TD; A-->B\n```\n"}
+	tests := []struct {
+		name     string
+		vendored bool
+		opts     Options
+		wantWarn string // 빈 값이면 경고 없음
+		wantErr  string
+	}{
+		{name: "renderer failure", vendored: true, opts: Options{Diagrams: &fakeDiagrams{err: errors.New("chrome not found")}},
+			wantWarn: "guide.md:3: Mermaid diagram was not pre-rendered (chrome not found); it is rendered by mermaid.js in the browser"},
+		// 렌더러를 주지 않은 호출(serve)은 의도한 브라우저 렌더링
+		{name: "no renderer", vendored: true},
+		{name: "offline without vendored Mermaid", opts: Options{Diagrams: &fakeDiagrams{}, Offline: true},
+			wantErr: "Mermaid is not vendored"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.vendored {
+				useVendoredMermaid(t)
+			} else {
+				useAssets(t, fstest.MapFS{})
+			}
+			b, err := convertDocs(t, doc, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(b.body(), `<div class="mermaid">graph TD; A--&gt;B`) {
+				t.Errorf("diagram is not left to mermaid.js:\n%s", b.body())
+			}
+			if !strings.Contains(b.html, "mermaid.initialize") {
+				t.Error("template does not load mermaid.js for the fallback")
+			}
+			got := strings.Join(b.messages(logging.Warn), "\n")
+			if got != tt.wantWarn {
+				t.Errorf("warnings = %q, want %q", got, tt.wantWarn)
+			}
+		})
+	}
+}
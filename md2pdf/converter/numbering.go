@@ -0,0 +1,231 @@
+package converter
+
+import (
+	gohtml "html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// 제목 번호(Options.NumberHeadings): 모든 파일에 걸쳐 문서 순서대로 H1-H3에
+// 1, 1.1, 1.1.1 번호를 붙인다. 제목 속성으로 조정한다:
+//
+//	# Preface {.unnumbered}   번호 없음. 파일의 제목 헤딩에 쓰면 파일 전체를
+//	                          번호 없이 둔다
+//	# Glossary {.appendix}    이 장과 뒤의 장은 A, A.1, A.1.1처럼 문자로
+//	                          번호를 붙인다
+
+const numberedDepth = 3
+
+var kindHeadingNumber = ast.NewNodeKind("HeadingNumber")
+
+// headingNumber는 제목 앞에 붙는 번호
+type headingNumber struct {
+	ast.BaseInline
+	number string
+}
+
+func (n *headingNumber) Kind() ast.NodeKind { return kindHeadingNumber }
+
+func (n *headingNumber) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Number": n.number}, nil)
+}
+
+// headingNumberExtension은 headingNumber 노드를 렌더링한다.
+type headingNumberExtension struct{}
+
+func (e *headingNumberExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&headingNumberRenderer{}, 500)))
+}
+
+type headingNumberRenderer struct{}
+
+func (r *headingNumberRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindHeadingNumber, r.render)
+}
+
+func (r *headingNumberRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span class="heading-number">` + gohtml.EscapeString(node.(*headingNumber).number) + `</span> `)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// numberedHeading은 번호가 붙은 제목. 이 제목을 가리키는 링크 텍스트에
+// 번호를 붙일 때 쓴다.
+type numberedHeading struct {
+	title, number string
+}
+
+// headingNumberer는 파일에서 다음 파일로 카운터를 이어 간다.
+type headingNumberer struct {
+	counters [numberedDepth]int
+	appendix bool
+	headings map[string]numberedHeading // 제목 ID와 섹션 ID별
+	ids      map[string]string          // 파일 ID별 섹션 ID (sectionIDs 참고)
+}
+
+func newHeadingNumberer(ids map[string]string) *headingNumberer {
+	return &headingNumberer{headings: make(map[string]numberedHeading), ids: ids}
+}
+
+// apply는 파일 하나의 최상위 제목에 번호를 붙인다.
+func (h *headingNumberer) apply(doc ast.Node, source []byte, sectionID string) {
+	var headings []*ast.Heading
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		if hd, ok := c.(*ast.Heading); ok {
+			headings = append(headings, hd)
+		}
+	}
+	if len(headings) == 0 || hasClass(headings[0], "unnumbered") {
+		return
+	}
+
+	for i, hd := range headings {
+		if hd.Level > numberedDepth || hasClass(hd, "unnumbered") {
+			continue
+		}
+		if hd.Level == 1 && hasClass(hd, "appendix") && !h.appendix {
+			h.appendix = true
+			h.counters[0] = 0
+		}
+		h.counters[hd.Level-1]++
+		for l := hd.Level; l < numberedDepth; l++ {
+			h.counters[l] = 0
+		}
+
+		number := h.format(hd.Level)
+		hd.InsertBefore(hd, hd.FirstChild(), &headingNumber{number: number})
+		ref := numberedHeading{title: strings.TrimSpace(string(hd.Text(source))), number: number}
+		if id, ok := hd.AttributeString("id"); ok {
+			h.headings[string(id.([]byte))] = ref
+		}
+		if i == 0 {
+			h.headings[sectionID] = ref
+		}
+	}
+}
+
+// format은 level 수준 제목의 번호를 반환한다. 문서의 첫 제목보다 위의
+// 수준(예: H1보다 먼저 나온 H2의 H1 수준)은 생략한다.
+func (h *headingNumberer) format(level int) string {
+	parts := h.counters[:level]
+	first := 0
+	for first < len(parts)-1 && parts[first] == 0 {
+		first++
+	}
+	var labels []string
+	for i := first; i < len(parts); i++ {
+		if i == 0 && h.appendix {
+			labels = append(labels, appendixLetter(parts[i]))
+		} else {
+			labels = append(labels, strconv.Itoa(parts[i]))
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// appendixLetter는 A-Z, 그다음 AA, AB, ...를 반환한다.
+func appendixLetter(n int) string {
+	var s string
+	for n > 0 {
+		n--
+		s = string(rune('A'+n%26)) + s
+		n /= 26
+	}
+	return s
+}
+
+var reInternalLink = regexp.MustCompile(`<a href="([^"]*)">([^<]+)</a>`)
+var reMarkdownHref = regexp.MustCompile(`^\.?/?([^#]*\.md)(#.*)?$`)
+
+// relabelLinks는 가리키는 번호 붙은 제목과 텍스트가 같은 내부 링크 앞에
+// 번호를 붙인다.
+func (h *headingNumberer) relabelLinks(htmlContent string) string {
+	return reInternalLink.ReplaceAllStringFunc(htmlContent, func(link string) string {
+		m := reInternalLink.FindStringSubmatch(link)
+		ref, ok := h.lookup(m[1])
+		if !ok || strings.TrimSpace(gohtml.UnescapeString(m[2])) != ref.title {
+			return link
+		}
+		return `<a href="` + m[1] + `">` + gohtml.EscapeString(ref.number) + " " + m[2] + `</a>`
+	})
+}
+
+// lookup은 내부 링크(#id, file.md, file.md#id)를 해석한다.
+func (h *headingNumberer) lookup(href string) (numberedHeading, bool) {
+	anchor := ""
+	if strings.HasPrefix(href, "#") {
+		anchor = href
+	} else if m := reMarkdownHref.FindStringSubmatch(href); m != nil {
+		if m[2] == "" {
+			ref, ok := h.headings[fileSectionID(m[1], h.ids)]
+			return ref, ok
+		}
+		anchor = m[2]
+	} else {
+		return numberedHeading{}, false
+	}
+	if id, err := url.PathUnescape(anchor[1:]); err == nil {
+		if ref, ok := h.headings[id]; ok {
+			return ref, true
+		}
+	}
+	ref, ok := h.headings[strings.TrimPrefix(normalizeAnchor(anchor), "#")]
+	return ref, ok
+}
+
+func hasClass(n ast.Node, class string) bool {
+	v, ok := n.AttributeString("class")
+	if !ok {
+		return false
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	for _, c := range strings.Fields(string(b)) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// documentTitle은 파일의 첫 H1(H1이 없으면 첫 H2)의 텍스트와 수준을
+// 반환한다. 번호가 붙은 제목은 번호를 포함한다.
+func documentTitle(doc ast.Node, source []byte) (title, number string, level int) {
+	var second *ast.Heading
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		hd, ok := c.(*ast.Heading)
+		if !ok {
+			continue
+		}
+		if hd.Level == 1 {
+			title, number = headingTitle(hd, source)
+			return title, number, 1
+		}
+		if hd.Level == 2 && second == nil {
+			second = hd
+		}
+	}
+	if second != nil {
+		title, number = headingTitle(second, source)
+		return title, number, 2
+	}
+	return "Untitled", "", 0
+}
+
+func headingTitle(hd *ast.Heading, source []byte) (string, string) {
+	title := strings.TrimSpace(string(hd.Text(source)))
+	if n, ok := hd.FirstChild().(*headingNumber); ok {
+		return n.number + " " + title, n.number
+	}
+	return title, ""
+}
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+// outline은 섹션과 하위 제목의 제목을 문서 순서대로 반환한다. 하위 제목은
+// 앞에 "- "를 붙인다.
+func outline(sections []Section) []string {
+	var titles []string
+	for _, s := range sections {
+		titles = append(titles, s.Title)
+		for _, sub := range s.SubHeadings {
+			titles = append(titles, "- "+sub.Title)
+		}
+	}
+	return titles
+}
+
+func TestHeadingNumbering(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{"document order", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n\n## Usage\n",
+			"02-ref.md":   "# Reference\n\n## Env\n",
+		}, []string{"1 Intro", "- 1.1 Setup", "- 1.2 Usage", "2 Reference", "- 2.1 Env"}},
+		// 파일 제목이 unnumbered이면 파일 전체를 번호 없이 두고 카운터도 그대로
+		{"unnumbered file", map[string]string{
+			"01-pre.md":   "# Preface {.unnumbered}\n\n## Notes\n",
+			"02-intro.md": "# Intro\n\n## Setup\n",
+		}, []string{"Preface", "- Notes", "1 Intro", "- 1.1 Setup"}},
+		{"unnumbered heading", map[string]string{
+			"01-ref.md": "# Reference\n\n## Flags {.unnumbered}\n\n## Env\n",
+		}, []string{"1 Reference", "- Flags", "- 1.1 Env"}},
+		// appendix부터 장 번호가 A로 다시 시작하고 하위 번호도 장마다 재설정
+		{"appendix lettering and reset", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n\n## Usage\n",
+			"02-app.md":   "# Glossary {.appendix}\n\n## Terms\n",
+			"03-app.md":   "# Tools\n\n## Lint\n\n## Format\n",
+		}, []string{"1 Intro", "- 1.1 Setup", "- 1.2 Usage", "A Glossary", "- A.1 Terms", "B Tools", "- B.1 Lint", "- B.2 Format"}},
+		// H2만 있는 파일은 앞 장에 합쳐지고 번호가 이어짐
+		{"H2-only file", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n",
+			"02-more.md":  "## More\n",
+		}, []string{"1 Intro", "- 1.1 Setup", "- 1.2 More"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, Options{NumberHeadings: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(outline(b.sections), " | "); got != strings.Join(tt.want, " | ") {
+				t.Errorf("outline:\n got %s\nwant %s", got, strings.Join(tt.want, " | "))
+			}
+		})
+	}
+}
+
+func TestHeadingNumberingBody(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		// H1이 나오기 전의 H2는 상위 수준을 생략
+		"00-start.md": "## Start\n",
+		"01-intro.md": "# Intro\n\n## Setup\n\n### Linux\n\n#### Details\n\n" +
+			"See [Setup](#setup), [the setup](#setup) and [Reference](02-ref.md).\n",
+		"02-ref.md": "# Reference\n\n# Glossary {.appendix}\n\n## Terms\n",
+	}, Options{NumberHeadings: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := b.body()
+	for _, want := range []string{
+		`<span class="heading-number">1</span> Start`,
+		`<span class="heading-number">1.1.1</span> Linux`,
+		// 파일 중간의 appendix도 그 장부터 문자 번호
+		`<span class="heading-number">A</span> Glossary`,
+		`<span class="heading-number">A.1</span> Terms`,
+		// 번호가 붙은 제목을 가리키고 제목과 같은 텍스트의 링크에만 번호를 붙임
+		`<a href="#setup">1.1 Setup</a>`,
+		`<a href="#setup">the setup</a>`,
+		`>2 Reference</a>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q", want)
+		}
+	}
+	if strings.Contains(body, "1.1.1.1") {
+		t.Error("H4 is numbered")
+	}
+}
+
+func TestAppendixLetter(t *testing.T) {
+	for n, want := range map[int]string{1: "A", 2: "B", 26: "Z", 27: "AA", 28: "AB", 52: "AZ", 53: "BA"} {
+		if got := appendixLetter(n); got != want {
+			t.Errorf("appendixLetter(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package converter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 콘텐츠 탭은 같은 내용의 여러 변형을 보여준다:
+//
+//	=== "Windows"          MkDocs. 내용은 4칸 들여 쓰고, 이어진 탭이 한
+//	    ...                묶음 (===+ 선택, ===! 새 묶음)
+//	<Tabs>                 Docusaurus. 파싱 전에 MkDocs 형식으로 바꿈
+//	<TabItem label="x">    ("default"는 선택한 탭)
+//	...
+//	</TabItem>
+//	</Tabs>
+//
+// 묶음은 HTML에서는 누를 수 있는 탭, PDF 모드에서는 이름을 붙여 차례로 쌓은
+// 패널이다. Options.Tab이 있으면 그 이름의 탭만 묶음 없이 남기고, 그 탭이
+// 없는 묶음은 그대로 렌더링한다.
+type tabsExtension struct {
+	pdf      bool
+	selected string
+}
+
+func (e *tabsExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&tabParser{}, 90)),
+		parser.WithASTTransformers(util.Prioritized(&tabsTransformer{selected: e.selected}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&tabsRenderer{pdf: e.pdf}, 500)))
+}
+
+var (
+	kindTab      = ast.NewNodeKind("Tab")
+	kindTabGroup = ast.NewNodeKind("TabGroup")
+)
+
+// tab은 변형 하나다. 자식은 내용 블록이다.
+type tab struct {
+	ast.BaseBlock
+	label    string
+	selected bool // ===+ 또는 Docusaurus default
+	newGroup bool // ===!
+}
+
+func (n *tab) Kind() ast.NodeKind { return kindTab }
+
+func (n *tab) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Label": n.label}, nil)
+}
+
+// tabGroup은 이어진 탭을 담는다.
+type tabGroup struct {
+	ast.BaseBlock
+}
+
+func (n *tabGroup) Kind() ast.NodeKind { return kindTabGroup }
+
+func (n *tabGroup) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+var reTabMarker = regexp.MustCompile(`^===([+!]{0,2})[ \t]+(?:"([^"]*)"|(\S.*?))[ \t]*$`)
+
+type tabParser struct{}
+
+func (b *tabParser) Trigger() []byte { return []byte{'='} }
+
+func (b *tabParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+	m := reTabMarker.FindSubmatch(util.TrimRightSpace(line[pos:]))
+	if m == nil {
+		return nil, parser.NoChildren
+	}
+	node := &tab{
+		label:    string(m[2]) + string(m[3]),
+		selected: bytes.IndexByte(m[1], '+') >= 0,
+		newGroup: bytes.IndexByte(m[1], '!') >= 0,
+	}
+	advanceLine(reader, line, segment)
+	return node, parser.HasChildren
+}
+
+func (b *tabParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if util.IsBlank(line) {
+		advanceLine(reader, line, segment)
+		return parser.Continue | parser.HasChildren
+	}
+	if indent, _ := util.IndentWidth(line, reader.LineOffset()); indent < 4 {
+		return parser.Close
+	}
+	pos, padding := util.IndentPosition(line, reader.LineOffset(), 4)
+	reader.AdvanceAndSetPadding(pos, padding)
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *tabParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (b *tabParser) CanInterruptParagraph() bool { return true }
+
+func (b *tabParser) CanAcceptIndentedLine() bool { return false }
+
+// tabsTransformer는 이어진 탭을 묶고 선택한 탭을 적용한다.
+type tabsTransformer struct {
+	selected string
+}
+
+func (t *tabsTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var tabs []*tab
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if n, ok := n.(*tab); ok && entering {
+			tabs = append(tabs, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var groups []*tabGroup
+	for _, first := range tabs {
+		if _, grouped := first.Parent().(*tabGroup); grouped {
+			continue
+		}
+		group := &tabGroup{}
+		group.SetBlankPreviousLines(first.HasBlankPreviousLines())
+		parent := first.Parent()
+		parent.InsertBefore(parent, first, group)
+		for n := ast.Node(first); n != nil; {
+			tb, ok := n.(*tab)
+			if !ok || tb.newGroup && tb != first {
+				break
+			}
+			next := n.NextSibling()
+			group.AppendChild(group, n)
+			n = next
+		}
+		groups = append(groups, group)
+	}
+
+	for _, group := range groups {
+		if tb := findTab(group, t.selected); tb != nil {
+			unwrapTab(group, tb)
+			continue
+		}
+		active := group.FirstChild()
+		for n := active; n != nil; n = n.NextSibling() {
+			if n.(*tab).selected {
+				active = n
+				break
+			}
+		}
+		for n := group.FirstChild(); n != nil; n = n.NextSibling() {
+			n.(*tab).selected = n == active
+		}
+	}
+}
+
+// findTab은 묶음에서 label 이름의 탭을 반환한다(없으면 nil).
+func findTab(group *tabGroup, label string) *tab {
+	if label == "" {
+		return nil
+	}
+	for n := group.FirstChild(); n != nil; n = n.NextSibling() {
+		if tb := n.(*tab); strings.EqualFold(tb.label, label) {
+			return tb
+		}
+	}
+	return nil
+}
+
+// unwrapTab은 묶음을 탭 하나의 내용으로 바꾼다.
+func unwrapTab(group *tabGroup, tb *tab) {
+	parent := group.Parent()
+	for c := tb.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		parent.InsertBefore(parent, group, c)
+		c = next
+	}
+	parent.RemoveChild(parent, group)
+}
+
+type tabsRenderer struct {
+	pdf bool
+}
+
+func (r *tabsRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindTabGroup, r.renderTabGroup)
+	reg.Register(kindTab, r.renderTab)
+}
+
+func (r *tabsRenderer) renderTabGroup(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+	if r.pdf {
+		_, _ = w.WriteString(`<div class="tabs tabs-stacked">` + "\n")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<div class="tabs"><div class="tab-labels" role="tablist">`)
+	for n := node.FirstChild(); n != nil; n = n.NextSibling() {
+		tb := n.(*tab)
+		class := "tab-label"
+		if tb.selected {
+			class += " active"
+		}
+		_, _ = w.WriteString(`<button type="button" class="` + class + `" role="tab">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(tb.label)))
+		_, _ = w.WriteString(`</button>`)
+	}
+	_, _ = w.WriteString("</div>\n")
+	return ast.WalkContinue, nil
+}
+
+func (r *tabsRenderer) renderTab(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+	tb := node.(*tab)
+	class := "tab-panel"
+	if tb.selected && !r.pdf {
+		class += " active"
+	}
+	_, _ = w.WriteString(`<div class="` + class + `" role="tabpanel"><div class="tab-title">`)
+	_, _ = w.Write(util.EscapeHTML([]byte(tb.label)))
+	_, _ = w.WriteString("</div>\n")
+	return ast.WalkContinue, nil
+}
+
+var (
+	reTabsOpen     = regexp.MustCompile(`^[ \t]*<Tabs\b[^>]*>[ \t]*$`)
+	reTabsClose    = regexp.MustCompile(`^[ \t]*</Tabs>[ \t]*$`)
+	reTabItemOpen  = regexp.MustCompile(`^[ \t]*<TabItem\b([^>]*?)/?>[ \t]*$`)
+	reTabItemClose = regexp.MustCompile(`^[ \t]*</TabItem>[ \t]*$`)
+	reTabsImport   = regexp.MustCompile(`^import\s+(Tabs|TabItem)\s+from\s+['"]@theme/(Tabs|TabItem)['"];?[ \t]*$`)
+	reTabItemAttr  = regexp.MustCompile(`(\w+)(?:=(?:"([^"]*)"|'([^']*)'|\{["']([^"']*)["']\}))?`)
+)
+
+// docusaurusTabs는 Docusaurus <Tabs>/<TabItem> 블록을 MkDocs 탭으로 바꾸고
+// import 줄을 없앤다. 항목 내용은 첫 줄의 들여쓰기만큼 들여쓰기를 줄이고,
+// 태그 줄은 비우거나 바꿔서 줄 번호가 바뀌지 않는다.
+func docusaurusTabs(content []byte) []byte {
+	if !bytes.Contains(content, []byte("<Tabs")) {
+		return content
+	}
+	var out bytes.Buffer
+	var fences fenceTracker
+	var items []int // 열린 <TabItem>마다 내용의 들여쓰기
+	depth := 0      // 열린 <Tabs> 수
+	newGroup := false
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	for i, line := range lines {
+		indent := strings.Repeat("    ", len(items))
+		if len(items) > 0 {
+			line = trimIndent(line, items[len(items)-1])
+		}
+		if fences.inCode(line) {
+			writeIndented(&out, indent, line)
+			continue
+		}
+		trimmed := bytes.TrimRight(line, "\r\n")
+		switch {
+		case reTabsImport.Match(trimmed):
+			blankLine(&out, line)
+		case reTabsOpen.Match(trimmed):
+			depth++
+			newGroup = true
+			blankLine(&out, line)
+		case reTabsClose.Match(trimmed) && depth > 0:
+			depth--
+			blankLine(&out, line)
+		case reTabItemOpen.Match(trimmed) && depth > 0:
+			attrs := make(map[string]string)
+			for _, a := range reTabItemAttr.FindAllSubmatch(reTabItemOpen.FindSubmatch(trimmed)[1], -1) {
+				attrs[string(a[1])] = string(a[2]) + string(a[3]) + string(a[4])
+			}
+			label := attrs["label"]
+			if label == "" {
+				label = attrs["value"]
+			}
+			marker := "==="
+			if newGroup {
+				marker += "!"
+			}
+			if _, ok := attrs["default"]; ok {
+				marker += "+"
+			}
+			newGroup = false
+			out.WriteString(indent + marker + ` "` + strings.ReplaceAll(label, `"`, "'") + `"`)
+			out.Write(line[len(trimmed):])
+			items = append(items, contentIndent(lines[i+1:]))
+		case reTabItemClose.Match(trimmed) && len(items) > 0:
+			items = items[:len(items)-1]
+			blankLine(&out, line)
+		default:
+			writeIndented(&out, indent, line)
+		}
+	}
+	return out.Bytes()
+}
+
+// contentIndent는 비어 있지 않은 첫 줄의 들여쓰기를 반환한다.
+func contentIndent(lines [][]byte) int {
+	for _, l := range lines {
+		if trimmed := bytes.TrimLeft(l, " \t"); len(bytes.TrimSpace(trimmed)) > 0 {
+			return len(l) - len(trimmed)
+		}
+	}
+	return 0
+}
+
+// trimIndent는 앞의 공백이나 탭을 n개까지 없앤다.
+func trimIndent(line []byte, n int) []byte {
+	i := 0
+	for i < n && i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return line[i:]
+}
+
+// writeIndented는 line을 쓰고, 빈 줄이 아니면 들여 쓴다.
+func writeIndented(out *bytes.Buffer, indent string, line []byte) {
+	if len(bytes.TrimSpace(line)) > 0 {
+		out.WriteString(indent)
+	}
+	out.Write(line)
+}
@@ -0,0 +1,159 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// tabGroups는 문서의 탭 묶음을 나타낸다: 묶음은 "|"로 나누고 선택한 탭은
+// "+"를 붙인다. 예: "A,+B|+C".
+func tabGroups(doc ast.Node) string {
+	var groups []string
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		g, ok := n.(*tabGroup)
+		if !ok {
+			continue
+		}
+		var labels []string
+		for c := g.FirstChild(); c != nil; c = c.NextSibling() {
+			tb := c.(*tab)
+			label := tb.label
+			if tb.selected {
+				label = "+" + label
+			}
+			labels = append(labels, label)
+		}
+		groups = append(groups, strings.Join(labels, ","))
+	}
+	return strings.Join(groups, "|")
+}
+
+func TestTabsGrouping(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"consecutive", "=== \"A\"\n    a\n\n=== \"B\"\n    b\n", "+A,B"},
+		{"selected", "=== \"A\"\n    a\n\n===+ \"B\"\n    b\n", "A,+B"},
+		{"new group", "=== \"A\"\n    a\n\n===! \"B\"\n    b\n", "+A|+B"},
+		{"split by paragraph", "=== \"A\"\n    a\n\ntext\n\n=== \"B\"\n    b\n", "+A|+B"},
+		{"unquoted label", "=== Linux\n    a\n", "+Linux"},
+		{"not indented", "=== \"A\"\na\n", "+A"},
+		{"marker at end of file", "=== \"A\"", "+A"},
+	}
+	md := goldmark.New(goldmark.WithExtensions(&tabsExtension{}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := md.Parser().Parse(text.NewReader([]byte(tt.in)))
+			if got := tabGroups(doc); got != tt.want {
+				t.Errorf("groups = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTabsRender(t *testing.T) {
+	src := "=== \"Windows\"\n    win\n\n=== \"Linux\"\n    linux\n"
+	tests := []struct {
+		name string
+		ext  *tabsExtension
+		in   string // 기본값: src
+		want []string
+		not  []string
+	}{
+		{"html", &tabsExtension{}, "",
+			[]string{`<button type="button" class="tab-label active" role="tab">Windows</button>`, `<div class="tab-panel active" role="tabpanel">`},
+			[]string{"tabs-stacked"}},
+		{"pdf", &tabsExtension{pdf: true}, "",
+			[]string{`<div class="tabs tabs-stacked">`, `<div class="tab-title">Linux</div>`},
+			[]string{"<button", "active"}},
+		{"selected tab", &tabsExtension{selected: "linux"}, "",
+			[]string{"<p>linux</p>"},
+			[]string{"win", `class="tabs`}},
+		{"marker at end of file", &tabsExtension{}, `=== "Linux"`,
+			[]string{`<div class="tab-title">Linux</div>`},
+			[]string{"<p>&quot;</p>"}},
+		{"selected tab missing", &tabsExtension{selected: "macOS"}, "",
+			[]string{`<div class="tabs">`, "<p>win</p>", "<p>linux</p>"},
+			nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			if in == "" {
+				in = src
+			}
+			var b bytes.Buffer
+			if err := goldmark.New(goldmark.WithExtensions(tt.ext)).Convert([]byte(in), &b); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("output lacks %q:\n%s", s, b.String())
+				}
+			}
+			for _, s := range tt.not {
+				if strings.Contains(b.String(), s) {
+					t.Errorf("output contains %q:\n%s", s, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDocusaurusTabs(t *testing.T) {
+	in := `import Tabs from '@theme/Tabs';
+
+<Tabs>
+  <TabItem value="win" label="Windows">
+    Install.
+
+    ` + "```" + `
+    <Tabs>
+    ` + "```" + `
+  </TabItem>
+  <TabItem value="linux" default>
+    apt
+  </TabItem>
+</Tabs>
+`
+	want := `
+
+
+===! "Windows"
+    Install.
+
+    ` + "```" + `
+    <Tabs>
+    ` + "```" + `
+
+===+ "linux"
+    apt
+
+
+`
+	wantGroups := "Windows,+linux"
+	// 연달아 있는 두 묶음은 따로 시작
+	twice := "<Tabs>\n<TabItem label=\"A\">\na\n</TabItem>\n</Tabs>\n<Tabs>\n<TabItem label=\"B\">\nb\n</TabItem>\n</Tabs>\n"
+
+	got := string(docusaurusTabs([]byte(in)))
+	if got != want {
+		t.Errorf("docusaurusTabs = %q, want %q", got, want)
+	}
+	if strings.Count(got, "\n") != strings.Count(in, "\n") {
+		t.Errorf("line count changed")
+	}
+	md := goldmark.New(goldmark.WithExtensions(&tabsExtension{}))
+	if g := tabGroups(md.Parser().Parse(text.NewReader([]byte(got)))); g != wantGroups {
+		t.Errorf("groups = %q, want %q", g, wantGroups)
+	}
+	if g := tabGroups(md.Parser().Parse(text.NewReader(docusaurusTabs([]byte(twice))))); g != "+A|+B" {
+		t.Errorf("consecutive <Tabs> groups = %q, want %q", g, "+A|+B")
+	}
+}
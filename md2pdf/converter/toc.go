@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+
+	"md2pdf/logging"
+)
+
+// 목차 항목: 파일마다 섹션 하나이고, 그 아래 H2(Options.TOCDepth에 따라
+// H4까지) 제목을 넣는다. 속성, front matter, 제목 패턴으로 제목을 뺀다:
+//
+//	## Internal notes {.unlisted}   이 제목만 제외. 파일의 제목 헤딩에 쓰면
+//	                                파일 전체
+//	toc: false                      (front matter) 파일 전체
+//	-toc-exclude '^Q[.\s]'          제목이 패턴과 맞는 제목
+
+// DefaultTOCExclude는 제외 패턴을 설정하지 않았을 때 Q&A 제목("Q. ...")을
+// 목차에서 뺀다.
+var DefaultTOCExclude = []string{`^Q[.\s]`}
+
+const (
+	minTOCDepth = 2
+	maxTOCDepth = 4
+)
+
+// tocRules는 파일의 어떤 제목을 목차에 넣을지 정한다.
+type tocRules struct {
+	depth   int
+	exclude []*regexp.Regexp
+}
+
+// newTOCRules는 목차 옵션을 정한다(CLI > 설정 > 기본값).
+func newTOCRules(opts Options, cfg AuthorsConfig, log logging.Printer) tocRules {
+	r := tocRules{depth: opts.TOCDepth}
+	if r.depth == 0 {
+		r.depth = cfg.TOC.Depth
+	}
+	if r.depth == 0 {
+		r.depth = minTOCDepth
+	}
+	if r.depth < minTOCDepth || r.depth > maxTOCDepth {
+		clamped := min(max(r.depth, minTOCDepth), maxTOCDepth)
+		log.Warnf("TOC depth %d out of range (%d-%d), using %d", r.depth, minTOCDepth, maxTOCDepth, clamped)
+		r.depth = clamped
+	}
+
+	patterns := opts.TOCExclude
+	if patterns == nil {
+		patterns = cfg.TOC.Exclude
+	}
+	if patterns == nil {
+		patterns = DefaultTOCExclude
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			log.Warnf("Ignoring invalid TOC exclude pattern %q: %v", p, err)
+			continue
+		}
+		r.exclude = append(r.exclude, re)
+	}
+	return r
+}
+
+// subHeadings는 문서에서 목차에 넣을 H2..depth 제목을 순서대로 반환한다.
+func (r tocRules) subHeadings(doc ast.Node, source []byte) []SubHeading {
+	var subs []SubHeading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		hd, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if hd.Level < 2 || hd.Level > r.depth || hasClass(hd, "unlisted") {
+			return ast.WalkSkipChildren, nil
+		}
+		id, ok := hd.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		title, number := headingTitle(hd, source)
+		if r.excluded(strings.TrimSpace(strings.TrimPrefix(title, number))) {
+			return ast.WalkSkipChildren, nil
+		}
+		subs = append(subs, SubHeading{
+			Title:  title,
+			ID:     string(id.([]byte)),
+			Number: number,
+			Level:  hd.Level,
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return subs
+}
+
+func (r tocRules) excluded(title string) bool {
+	for _, re := range r.exclude {
+		if re.MatchString(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// unlistedFile은 파일의 제목 헤딩(첫 제목)에 {.unlisted}가 있는지 보고한다.
+func unlistedFile(doc ast.Node) bool {
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		if hd, ok := c.(*ast.Heading); ok {
+			return hasClass(hd, "unlisted")
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2pdf/logging"
+)
+
+// tocEntries는 목차에 나오는 제목을 문서 순서대로 반환한다. 목차에서 빠진
+// 섹션은 건너뛰고, 하위 제목은 수준만큼 "-"를 붙인다(H2 "- ", H3 "-- ").
+func tocEntries(sections []Section) []string {
+	var titles []string
+	for _, s := range sections {
+		if s.Unlisted {
+			continue
+		}
+		titles = append(titles, s.Title)
+		for _, sub := range s.SubHeadings {
+			titles = append(titles, strings.Repeat("-", sub.Level-1)+" "+sub.Title)
+		}
+	}
+	return titles
+}
+
+func TestTOCDepth(t *testing.T) {
+	doc := map[string]string{
+		"01-guide.md": "# Guide\n\n## Setup\n\n### Linux\n\n#### Packages\n\n## Usage\n",
+	}
+	tests := []struct {
+		name   string
+		config string // AUTHORS.yml
+		depth  int
+		want   []string
+		warn   string
+	}{
+		{"default", "", 0, []string{"Guide", "- Setup", "- Usage"}, ""},
+		{"depth 3", "", 3, []string{"Guide", "- Setup", "-- Linux", "- Usage"}, ""},
+		{"depth 4", "", 4, []string{"Guide", "- Setup", "-- Linux", "--- Packages", "- Usage"}, ""},
+		{"config", "toc:\n  depth: 3\n", 0, []string{"Guide", "- Setup", "-- Linux", "- Usage"}, ""},
+		// 옵션이 설정보다 우선
+		{"option over config", "toc:\n  depth: 4\n", 2, []string{"Guide", "- Setup", "- Usage"}, ""},
+		// 범위를 벗어난 깊이는 경고하고 2-4로 제한
+		{"too deep", "", 6, []string{"Guide", "- Setup", "-- Linux", "--- Packages", "- Usage"},
+			"TOC depth 6 out of range (2-4), using 4"},
+		{"too shallow", "toc:\n  depth: 1\n", 0, []string{"Guide", "- Setup", "- Usage"},
+			"TOC depth 1 out of range (2-4), using 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{}
+			for name, content := range doc {
+				files[name] = content
+			}
+			if tt.config != "" {
+				files["AUTHORS.yml"] = tt.config
+			}
+			b, err := convertDocs(t, files, Options{TOCDepth: tt.depth})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(tocEntries(b.sections), " | "); got != strings.Join(tt.want, " | ") {
+				t.Errorf("TOC:\n got %s\nwant %s", got, strings.Join(tt.want, " | "))
+			}
+			warnings := strings.Join(b.messages(logging.Warn), "\n")
+			if tt.warn == "" && warnings != "" {
+				t.Errorf("unexpected warnings:\n%s", warnings)
+			}
+			if !strings.Contains(warnings, tt.warn) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warn)
+			}
+		})
+	}
+}
+
+func TestTOCExclusion(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		want  []string
+	}{
+		{"front matter toc: false", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n",
+			"02-notes.md": "---\ntoc: false\n---\n# Notes\n\n## Internal\n",
+			"03-ref.md":   "# Reference\n",
+		}, Options{}, []string{"Intro", "- Setup", "Reference"}},
+		{"front matter toc: true", map[string]string{
+			"01-intro.md": "---\ntoc: true\n---\n# Intro\n\n## Setup\n",
+		}, Options{}, []string{"Intro", "- Setup"}},
+		// 파일 제목의 unlisted는 파일 전체를 제외
+		{"unlisted file", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n",
+			"02-notes.md": "# Notes {.unlisted}\n\n## Internal\n",
+		}, Options{}, []string{"Intro", "- Setup"}},
+		{"unlisted heading", map[string]string{
+			"01-intro.md": "# Intro\n\n## Setup\n\n## Internal {.unlisted}\n\n## Usage\n",
+		}, Options{}, []string{"Intro", "- Setup", "- Usage"}},
+		// 하위 제목이 빠져도 그 아래 제목은 깊이에 따라 목록에 남음
+		{"unlisted parent", map[string]string{
+			"01-intro.md": "# Intro\n\n## Internal {.unlisted}\n\n### Detail\n",
+		}, Options{TOCDepth: 3}, []string{"Intro", "-- Detail"}},
+		{"default pattern", map[string]string{
+			"01-faq.md": "# FAQ\n\n## Q. Why?\n\n## Q Who?\n\n## Quick start\n",
+		}, Options{}, []string{"FAQ", "- Quick start"}},
+		{"option patterns", map[string]string{
+			"01-faq.md": "# FAQ\n\n## Q. Why?\n\n## Draft: later\n\n## Quick start\n",
+		}, Options{TOCExclude: []string{"^Draft:", "start$"}}, []string{"FAQ", "- Q. Why?"}},
+		{"config patterns", map[string]string{
+			"AUTHORS.yml": "toc:\n  exclude: ['^Draft:']\n",
+			"01-faq.md":   "# FAQ\n\n## Q. Why?\n\n## Draft: later\n",
+		}, Options{}, []string{"FAQ", "- Q. Why?"}},
+		// 빈 목록은 기본 패턴도 끔
+		{"no patterns", map[string]string{
+			"01-faq.md": "# FAQ\n\n## Q. Why?\n",
+		}, Options{TOCExclude: []string{}}, []string{"FAQ", "- Q. Why?"}},
+		// 패턴은 번호를 뺀 제목에 적용
+		{"numbered title", map[string]string{
+			"01-faq.md": "# FAQ\n\n## Q. Why?\n\n## Setup\n",
+		}, Options{NumberHeadings: true}, []string{"1 FAQ", "- 1.2 Setup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, tt.files, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(tocEntries(b.sections), " | "); got != strings.Join(tt.want, " | ") {
+				t.Errorf("TOC:\n got %s\nwant %s", got, strings.Join(tt.want, " | "))
+			}
+		})
+	}
+}
+
+func TestTOCUnlistedBody(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n",
+		"02-notes.md": "---\ntoc: false\n---\n# Notes\n\nHidden from the TOC.\n",
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := b.body()
+	// 목차에서는 빠지지만 본문에는 남음
+	if !strings.Contains(body, `<a href="#01-intro">`) {
+		t.Error("listed section missing from the TOC")
+	}
+	if strings.Contains(body, `<a href="#02-notes">`) {
+		t.Error("toc: false section listed in the TOC")
+	}
+	if !strings.Contains(body, "Hidden from the TOC.") {
+		t.Error("toc: false section missing from the body")
+	}
+}
+
+func TestTOCInvalidPattern(t *testing.T) {
+	b, err := convertDocs(t, map[string]string{
+		"01-faq.md": "# FAQ\n\n## Q. Why?\n\n## (draft)\n",
+	}, Options{TOCExclude: []string{"(draft", `^\(draft\)`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tocEntries(b.sections), " | "); got != "FAQ | - Q. Why?" {
+		t.Errorf("TOC = %s", got)
+	}
+	warnings := strings.Join(b.messages(logging.Warn), "\n")
+	if !strings.Contains(warnings, `Ignoring invalid TOC exclude pattern "(draft"`) {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
@@ -0,0 +1,160 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"md2pdf/logging"
+)
+
+// 변수는 파싱 전에 Markdown 원본에서 치환한다:
+//
+//	{{ var.product }}     설정 파일 "vars:" 맵, Options.Vars가 우선
+//	{{ env.HOME }}        환경 변수 (Options.EnvVars일 때)
+//	{{ .Version }}        내장: .Title .Subtitle .Version .Author .Date
+//	                      .Copyright .Organization .ProjectName
+//
+// 코드 블록과 인라인 코드는 Options.VarsInCode가 아니면 그대로 둔다.
+// 정의되지 않은 변수는 경고하고 남겨 두며, Options.StrictVars이면 빌드가
+// 실패한다. .Date는 DocumentInfo.Date(documentDate 참고)다.
+
+// dateFormat은 문서 발행일의 형식이다.
+const dateFormat = "2006년 01월 02일"
+
+var reVariable = regexp.MustCompile(`\{\{\s*((?:var|env)\.[\w.-]+|\.\w+)\s*\}\}`)
+
+// variables는 모든 파일의 자리 표시자를 치환한다.
+type variables struct {
+	values    map[string]string // 자리 표시자 이름별 (var.x, .Version)
+	env       bool
+	inCode    bool
+	strict    bool
+	undefined int
+}
+
+func newVariables(opts Options, cfg AuthorsConfig, info DocumentInfo) *variables {
+	v := &variables{
+		values: map[string]string{
+			".Title":        info.Title,
+			".Subtitle":     info.Subtitle,
+			".Version":      info.Version,
+			".Author":       info.Author,
+			".Copyright":    info.Copyright,
+			".Date":         info.Date.Format(dateFormat),
+			".Organization": cfg.Organization,
+			".ProjectName":  cfg.ProjectName,
+		},
+		env:    opts.EnvVars,
+		inCode: opts.VarsInCode,
+		strict: opts.StrictVars,
+	}
+	for k, val := range cfg.Vars {
+		v.values["var."+k] = val
+	}
+	for k, val := range opts.Vars {
+		v.values["var."+k] = val
+	}
+	return v
+}
+
+// expand는 파일 하나의 자리 표시자를 치환한다.
+func (v *variables) expand(file string, content []byte, log logging.Printer) []byte {
+	if !bytes.Contains(content, []byte("{{")) {
+		return content
+	}
+	var out bytes.Buffer
+	var fences fenceTracker
+	for i, line := range bytes.SplitAfter(content, []byte("\n")) {
+		replace := func(b []byte) []byte {
+			return reVariable.ReplaceAllFunc(b, func(m []byte) []byte {
+				name := string(reVariable.FindSubmatch(m)[1])
+				if val, ok := v.lookup(name); ok {
+					return []byte(val)
+				}
+				v.undefined++
+				if v.strict {
+					log.At(file, i+1).Errorf("Undefined variable %s", name)
+				} else {
+					log.At(file, i+1).Warnf("Undefined variable %s", name)
+				}
+				return m
+			})
+		}
+		switch {
+		case v.inCode:
+			out.Write(replace(line))
+		case fences.inCode(line):
+			out.Write(line)
+		default:
+			out.Write(outsideCodeSpans(line, replace))
+		}
+	}
+	return out.Bytes()
+}
+
+func (v *variables) lookup(name string) (string, bool) {
+	if val, ok := v.values[name]; ok {
+		return val, true
+	}
+	if v.env && len(name) > 4 && name[:4] == "env." {
+		return os.LookupEnv(name[4:])
+	}
+	return "", false
+}
+
+// check는 strict 모드에서 정의되지 않은 변수가 있었으면 실패한다.
+func (v *variables) check() error {
+	if v.strict && v.undefined > 0 {
+		return fmt.Errorf("%d undefined variables", v.undefined)
+	}
+	return nil
+}
+
+// outsideCodeSpans는 line에서 인라인 코드(같은 길이의 백틱으로 감싼 부분)
+// 밖에만 replace를 적용한다.
+func outsideCodeSpans(line []byte, replace func([]byte) []byte) []byte {
+	var out []byte
+	for len(line) > 0 {
+		i := bytes.IndexByte(line, '`')
+		if i < 0 {
+			break
+		}
+		n := i
+		for n < len(line) && line[n] == '`' {
+			n++
+		}
+		end := closingBackticks(line[n:], n-i)
+		if end < 0 {
+			// 인라인 코드가 아님
+			out = append(out, replace(line[:n])...)
+			line = line[n:]
+			continue
+		}
+		out = append(out, replace(line[:i])...)
+		out = append(out, line[i:n+end]...)
+		line = line[n+end:]
+	}
+	return append(out, replace(line)...)
+}
+
+// closingBackticks는 b에서 정확히 n개인 첫 백틱 연속의 끝을 반환한다
+// (없으면 -1).
+func closingBackticks(b []byte, n int) int {
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(b) && b[j] == '`' {
+			j++
+		}
+		if j-i == n {
+			return j
+		}
+		i = j
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"md2pdf/logging"
+)
+
+func TestVariablesExpand(t *testing.T) {
+	t.Setenv("MD2PDF_TEST_HOST", "example.com")
+	cfg := AuthorsConfig{Vars: map[string]string{"product": "md2pdf", "port": "8080"}}
+	info := DocumentInfo{Version: "1.2"}
+	tests := []struct {
+		name     string
+		opts     Options
+		in, want string
+	}{
+		{"text", Options{}, "{{ var.product }} {{.Version}}\n", "md2pdf 1.2\n"},
+		{"option overrides config", Options{Vars: map[string]string{"port": "9090"}}, "port {{ var.port }}\n", "port 9090\n"},
+		{"environment", Options{EnvVars: true}, "{{ env.MD2PDF_TEST_HOST }}\n", "example.com\n"},
+		{"environment disabled", Options{}, "{{ env.MD2PDF_TEST_HOST }}\n", "{{ env.MD2PDF_TEST_HOST }}\n"},
+		{"undefined kept", Options{}, "{{ var.nope }}\n", "{{ var.nope }}\n"},
+		{"code span", Options{}, "`{{ var.port }}` and {{ var.port }}\n", "`{{ var.port }}` and 8080\n"},
+		{"double backtick span", Options{}, "``a ` {{ var.port }}`` {{ var.port }}\n", "``a ` {{ var.port }}`` 8080\n"},
+		{"unmatched backtick", Options{}, "a ` {{ var.port }}\n", "a ` 8080\n"},
+		{"code block", Options{}, "```\n{{ var.port }}\n```\n{{ var.port }}\n", "```\n{{ var.port }}\n```\n8080\n"},
+		{"in code", Options{VarsInCode: true}, "`{{ var.port }}`\n```\n{{ var.port }}\n```\n", "`8080`\n```\n8080\n```\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVariables(tt.opts, cfg, info)
+			got := string(v.expand("test.md", []byte(tt.in), logging.Use(logging.Discard)))
+			if got != tt.want {
+				t.Errorf("expand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariablesStrict(t *testing.T) {
+	v := newVariables(Options{StrictVars: true}, AuthorsConfig{}, DocumentInfo{})
+	v.expand("test.md", []byte("`{{ var.a }}` {{ var.b }} {{ var.c }}\n"), logging.Use(logging.Discard))
+	if err := v.check(); err == nil || err.Error() != "2 undefined variables" {
+		t.Errorf("check() = %v, want 2 undefined variables", err)
+	}
+}
+
+func TestDocumentDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string // document.date
+		epoch  string // SOURCE_DATE_EPOCH
+		want   string // "" = 오늘
+		warn   string
+	}{
+		{"config", "2026-03-01", "", "2026년 03월 01일", ""},
+		{"source date epoch", "", "1767225600", "2026년 01월 01일", ""},
+		// 설정한 발행일이 SOURCE_DATE_EPOCH보다 우선
+		{"config over epoch", "2026-03-01", "1767225600", "2026년 03월 01일", ""},
+		{"invalid config", "March 1", "1767225600", "2026년 01월 01일", `config.yml:0: Invalid document date "March 1" (want YYYY-MM-DD)`},
+		{"invalid epoch", "", "yesterday", "", `Invalid SOURCE_DATE_EPOCH "yesterday"`},
+		{"now", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SOURCE_DATE_EPOCH", tt.epoch)
+			var warnings []string
+			log := logging.Use(logging.LoggerFunc(func(e logging.Entry) {
+				if e.File != "" {
+					e.Message = e.File + ":" + strconv.Itoa(e.Line) + ": " + e.Message
+				}
+				warnings = append(warnings, e.Message)
+			}))
+			want := tt.want
+			if want == "" {
+				want = time.Now().Format(dateFormat)
+			}
+			if got := documentDate(tt.config, "config.yml", log).Format(dateFormat); got != want {
+				t.Errorf("date = %s, want %s", got, want)
+			}
+			if got := strings.Join(warnings, "\n"); got != tt.warn {
+				t.Errorf("warnings = %q, want %q", got, tt.warn)
+			}
+		})
+	}
+}
+
+func TestDocumentDateVariable(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1767225600")
+	tests := []struct {
+		name, config, want string
+	}{
+		{"config", "document:\n  date: 2026-03-01\n", "2026년 03월 01일"},
+		{"source date epoch", "project_name: Manual\n", "2026년 01월 01일"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := convertDocs(t, map[string]string{
+				"AUTHORS.yml": tt.config,
+				"01-intro.md": "# Intro\n\nPublished {{ .Date }}.\n",
+			}, Options{Template: "default"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 본문 변수와 템플릿의 .Date가 같은 날짜
+			if want := "<p>Published " + tt.want + ".</p>"; !strings.Contains(b.sections[0].Content, want) {
+				t.Errorf("missing %s in:\n%s", want, b.sections[0].Content)
+			}
+			if want := "발행일: " + tt.want; !strings.Contains(b.html, want) {
+				t.Errorf("missing %s in the page", want)
+			}
+		})
+	}
+}
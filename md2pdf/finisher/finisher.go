@@ -0,0 +1,334 @@
+// Package finisher는 렌더링된 PDF를 후처리한다.
+// 변경 사항은 PDF 증분 업데이트로 파일 끝에 덧붙이므로 Chrome이 만든 바이트는
+// 그대로 남고 원래 객체도 계속 유효하다.
+package finisher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"md2pdf/logging"
+)
+
+// Options는 PDF 후처리 옵션
+type Options struct {
+	Outline  []Bookmark     // 문서 개요(사이드바 북마크), nil이면 생략
+	Metadata *Metadata      // Info 사전과 XMP 패킷, nil이면 생략
+	Logger   logging.Logger // 기본값: logging.Default
+}
+
+// Apply는 요청한 변경 사항을 담은 증분 업데이트를 pdfPath 파일에 덧붙인다.
+func Apply(pdfPath string, opts Options) error {
+	if len(opts.Outline) == 0 && opts.Metadata == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	updated, err := Update(data, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(pdfPath, updated, 0644); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	logging.Use(opts.Logger).Infof("Updated PDF: %s", pdfPath)
+	return nil
+}
+
+// Update는 요청한 변경을 담은 증분 업데이트를 data 뒤에 붙여 반환한다.
+// data 자체는 수정하지 않는다.
+func Update(data []byte, opts Options) ([]byte, error) {
+	if len(opts.Outline) == 0 && opts.Metadata == nil {
+		return data, nil
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := doc.catalog
+	if len(opts.Outline) > 0 {
+		outlineRef, err := doc.writeOutline(opts.Outline)
+		if err != nil {
+			return nil, err
+		}
+		catalog = setDictEntry(catalog, "Outlines", outlineRef)
+		catalog = setDictEntry(catalog, "PageMode", "/UseOutlines")
+	}
+	if opts.Metadata != nil {
+		if opts.Metadata.Date.IsZero() {
+			opts.Metadata.Date = time.Now()
+		}
+		doc.info = doc.writeInfo(opts.Metadata)
+		catalog = setDictEntry(catalog, "Metadata", doc.writeXMP(opts.Metadata))
+	}
+	doc.replace(doc.rootNum, doc.rootGen, catalog)
+	return doc.finish(), nil
+}
+
+// document는 원본 PDF 바이트와 작성 중인 증분 업데이트의 객체를 담는다.
+type document struct {
+	data      []byte
+	startXref int
+	size      int      // 다음에 쓸 수 있는 객체 번호
+	rootNum   int      // 카탈로그 객체 번호
+	rootGen   int      // 카탈로그 세대 번호
+	catalog   []byte   // 카탈로그 딕셔너리 (<< ... >>)
+	trailer   []byte   // 마지막 xref 구간의 트레일러 딕셔너리
+	info      string   // 교체할 Info 참조; 비어 있으면 원래 값 유지
+	objects   []object // 덧붙일 객체
+	xref      map[int]xrefEntry
+}
+
+type object struct {
+	num, gen int
+	body     []byte
+}
+
+var (
+	reStartXref = regexp.MustCompile(`startxref\s+(\d+)\s*%%EOF\s*$`)
+	reRootRef   = regexp.MustCompile(`/Root\s+(\d+)\s+(\d+)\s+R`)
+	reSize      = regexp.MustCompile(`/Size\s+(\d+)`)
+	reRefs      = regexp.MustCompile(`(\d+)\s+(\d+)\s+R`)
+	rePagesRef  = regexp.MustCompile(`/Pages\s+(\d+)\s+(\d+)\s+R`)
+	reKids      = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+)
+
+func parseDocument(data []byte) (*document, error) {
+	m := reStartXref.FindSubmatch(data)
+	if m == nil {
+		return nil, fmt.Errorf("PDF has no startxref")
+	}
+	startXref, _ := strconv.Atoi(string(m[1]))
+	if startXref <= 0 || startXref >= len(data) {
+		return nil, fmt.Errorf("invalid startxref offset: %d", startXref)
+	}
+
+	// 트레일러 키는 일반 xref 테이블의 트레일러 딕셔너리나 xref 스트림의
+	// 딕셔너리에 있다.
+	tail := data[startXref:]
+	if idx := bytes.Index(tail, []byte("trailer")); idx >= 0 {
+		tail = tail[idx+len("trailer"):]
+	}
+	trailer, ok := readDict(tail)
+	if !ok {
+		return nil, fmt.Errorf("PDF trailer not found")
+	}
+
+	rootMatch := reRootRef.FindSubmatch(trailer)
+	sizeMatch := reSize.FindSubmatch(trailer)
+	if rootMatch == nil || sizeMatch == nil {
+		return nil, fmt.Errorf("PDF trailer has no /Root or /Size")
+	}
+
+	doc := &document{data: data, startXref: startXref, trailer: trailer, xref: readXref(data, startXref)}
+	doc.rootNum, _ = strconv.Atoi(string(rootMatch[1]))
+	doc.rootGen, _ = strconv.Atoi(string(rootMatch[2]))
+	doc.size, _ = strconv.Atoi(string(sizeMatch[1]))
+
+	catalog, err := doc.object(doc.rootNum, doc.rootGen)
+	if err != nil {
+		return nil, fmt.Errorf("PDF catalog: %w", err)
+	}
+	doc.catalog = catalog
+	return doc, nil
+}
+
+// object는 일반 형태로 저장된 간접 객체의 딕셔너리를 반환한다.
+// 객체 스트림 안의 객체는 지원하지 않는다.
+func (d *document) object(num, gen int) ([]byte, error) {
+	e, ok := d.xref[num]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("object %d not found", num)
+	case e.stream != 0:
+		return nil, fmt.Errorf("object %d is stored in object stream %d, which is not supported", num, e.stream)
+	case e.gen != gen:
+		return nil, fmt.Errorf("object %d %d not found (generation %d)", num, gen, e.gen)
+	case e.offset < 0 || e.offset >= len(d.data):
+		return nil, fmt.Errorf("object %d has an invalid offset %d", num, e.offset)
+	}
+	body := d.data[e.offset:]
+	m := reObjHeader.FindSubmatch(body)
+	if m == nil || string(m[1]) != strconv.Itoa(num) {
+		return nil, fmt.Errorf("object %d not found at offset %d", num, e.offset)
+	}
+	dict, ok := readDict(body[len(m[0]):])
+	if !ok {
+		return nil, fmt.Errorf("object %d is not a dictionary", num)
+	}
+	return dict, nil
+}
+
+// pageRefs는 모든 쪽의 객체 참조를 문서 순서대로 반환한다.
+func (d *document) pageRefs() ([]string, error) {
+	pagesMatch := rePagesRef.FindSubmatch(d.catalog)
+	if pagesMatch == nil {
+		return nil, nil
+	}
+	var refs []string
+	var walk func(num, gen, depth int) error
+	walk = func(num, gen, depth int) error {
+		if depth > 32 {
+			return nil
+		}
+		node, err := d.object(num, gen)
+		if err != nil {
+			return fmt.Errorf("PDF page tree: %w", err)
+		}
+		kidsMatch := reKids.FindSubmatch(node)
+		if kidsMatch == nil {
+			refs = append(refs, fmt.Sprintf("%d %d R", num, gen))
+			return nil
+		}
+		for _, kid := range reRefs.FindAllSubmatch(kidsMatch[1], -1) {
+			kidNum, _ := strconv.Atoi(string(kid[1]))
+			kidGen, _ := strconv.Atoi(string(kid[2]))
+			if err := walk(kidNum, kidGen, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	num, _ := strconv.Atoi(string(pagesMatch[1]))
+	gen, _ := strconv.Atoi(string(pagesMatch[2]))
+	if err := walk(num, gen, 0); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// alloc은 새 객체 번호를 예약한다.
+func (d *document) alloc() int {
+	num := d.size
+	d.size++
+	return num
+}
+
+// add는 새 객체를 저장하고 그 참조를 반환한다.
+func (d *document) add(num int, body []byte) string {
+	d.objects = append(d.objects, object{num: num, body: body})
+	return fmt.Sprintf("%d 0 R", num)
+}
+
+// replace는 기존 객체의 새 버전을 저장한다.
+func (d *document) replace(num, gen int, body []byte) {
+	d.objects = append(d.objects, object{num: num, gen: gen, body: body})
+}
+
+// finish는 증분 업데이트를 직렬화해 전체 파일을 반환한다.
+func (d *document) finish() []byte {
+	var buf bytes.Buffer
+	buf.Write(d.data)
+	if d.data[len(d.data)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	offsets := make(map[int]int, len(d.objects))
+	for _, obj := range d.objects {
+		offsets[obj.num] = buf.Len()
+		fmt.Fprintf(&buf, "%d %d obj\n", obj.num, obj.gen)
+		buf.Write(obj.body)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefOffset := buf.Len()
+	buf.WriteString("xref\n")
+	for _, obj := range d.objects {
+		fmt.Fprintf(&buf, "%d 1\n%010d %05d n \n", obj.num, offsets[obj.num], obj.gen)
+	}
+
+	trailer := fmt.Sprintf("<< /Size %d /Root %d %d R /Prev %d", d.size, d.rootNum, d.rootGen, d.startXref)
+	info := d.info
+	if info == "" {
+		info = dictEntry(d.trailer, "Info")
+	}
+	if info != "" {
+		trailer += " /Info " + info
+	}
+	if id := dictEntry(d.trailer, "ID"); id != "" {
+		trailer += " /ID " + id
+	}
+	trailer += " >>"
+	fmt.Fprintf(&buf, "trailer\n%s\nstartxref\n%d\n%%%%EOF\n", trailer, xrefOffset)
+	return buf.Bytes()
+}
+
+// readDict는 data에서 짝이 맞는 첫 번째 << ... >> 딕셔너리를 반환한다.
+func readDict(data []byte) ([]byte, bool) {
+	start := bytes.Index(data, []byte("<<"))
+	if start < 0 {
+		return nil, false
+	}
+	depth := 0
+	for i := start; i < len(data)-1; i++ {
+		switch {
+		case data[i] == '(':
+			i = skipString(data, i)
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return data[start : i+1], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// skipString은 data[i]에서 시작하는 리터럴 문자열의 닫는 괄호 위치를
+// 반환한다.
+func skipString(data []byte, i int) int {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// dictEntry는 참조·이름·숫자·배열 값을 가진 최상위 키의 원본 값을 반환한다.
+// 중첩 딕셔너리 값은 지원하지 않는다.
+func dictEntry(dict []byte, key string) string {
+	re := regexp.MustCompile(`/` + key + `\s*(\d+\s+\d+\s+R|/[^\s/<>\[\]()]+|\[[^\]]*\]|[\d.+-]+)`)
+	m := re.FindSubmatch(dict)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+// setDictEntry는 딕셔너리의 최상위 키를 설정하며, 기존의 참조·이름·숫자·배열
+// 값은 교체한다.
+func setDictEntry(dict []byte, key, value string) []byte {
+	re := regexp.MustCompile(`\s*/` + key + `\s*(\d+\s+\d+\s+R|/[^\s/<>\[\]()]+|\[[^\]]*\]|[\d.+-]+)`)
+	dict = re.ReplaceAll(dict, nil)
+	end := bytes.LastIndex(dict, []byte(">>"))
+	out := make([]byte, 0, len(dict)+len(key)+len(value)+4)
+	out = append(out, dict[:end]...)
+	out = append(out, fmt.Sprintf(" /%s %s ", key, value)...)
+	out = append(out, dict[end:]...)
+	return out
+}
@@ -0,0 +1,160 @@
+package finisher_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+
+	"md2pdf/analyzer"
+	"md2pdf/finisher"
+	"md2pdf/logging"
+)
+
+// 세 쪽(표지, 소개, 사용법)짜리 PDF의 객체. Chrome과 같은 방식(카탈로그의
+// /Dests)으로 Named Destination "intro"와 "usage"를 담는다.
+var testObjects = []string{
+	"<< /Type /Catalog /Pages 2 0 R /Dests 6 0 R >>",
+	"<< /Type /Pages /Kids [3 0 R 4 0 R 8 0 R] /Count 3 >>",
+	"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R >>",
+	"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 7 0 R >>",
+	"<< /Length 0 >>\nstream\n\nendstream",
+	"<< /intro [4 0 R /XYZ 0 792 0] /usage [8 0 R /XYZ 0 792 0] >>",
+	"<< /Length 0 >>\nstream\n\nendstream",
+	"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 9 0 R >>",
+	"<< /Length 0 >>\nstream\n\nendstream",
+}
+
+// buildPDF는 objs(1번부터 번호 매김)와 일반 xref 테이블로 된 PDF를 반환한다.
+func buildPDF(objs []string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+// buildXrefStreamPDF는 objs와 압축하지 않은 xref 스트림으로 된 PDF를
+// 반환한다. compressed에 있는 객체는 99번 객체 스트림에 저장된 것으로 표시한다.
+func buildXrefStreamPDF(objs []string, compressed map[int]bool) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	var rows []byte
+	rows = append(rows, 0, 0, 0, 0)
+	for i, obj := range objs {
+		if compressed[i+1] {
+			rows = append(rows, 2, 0, 99, 0)
+			continue
+		}
+		off := b.Len()
+		rows = append(rows, 1, byte(off>>8), byte(off), 0)
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	num := len(objs) + 1
+	rows = append(rows, 1, byte(xref>>8), byte(xref), 0)
+	fmt.Fprintf(&b, "%d 0 obj\n<< /Type /XRef /Size %d /W [1 2 1] /Root 1 0 R /Length %d >>\nstream\n", num, num+1, len(rows))
+	b.Write(rows)
+	fmt.Fprintf(&b, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xref)
+	return b.Bytes()
+}
+
+func TestUpdateRoundTrip(t *testing.T) {
+	opts := finisher.Options{
+		Outline: []finisher.Bookmark{
+			{Title: "소개", Dest: "intro", Children: []finisher.Bookmark{{Title: "Page two", Page: 2}}},
+			{Title: "Usage", Dest: "usage"},
+		},
+		Metadata: &finisher.Metadata{
+			Title:    "설치 안내서",
+			Author:   "Tools Team",
+			Keywords: []string{"pdf", "md2pdf"},
+			Date:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Logger: logging.Discard,
+	}
+	updated, err := finisher.Update(buildPDF(testObjects), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 두 번째 업데이트는 첫 번째 업데이트를 거쳐 /Prev 체인을 따라간다
+	updated, err = finisher.Update(updated, finisher.Options{Metadata: &finisher.Metadata{Title: "v2", Date: opts.Metadata.Date}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := pdf.NewReader(bytes.NewReader(updated), int64(len(updated)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	var walk func(o pdf.Outline, depth int)
+	walk = func(o pdf.Outline, depth int) {
+		for _, c := range o.Child {
+			titles = append(titles, strings.Repeat("-", depth)+c.Title)
+			walk(c, depth+1)
+		}
+	}
+	walk(r.Outline(), 0)
+	if got, want := strings.Join(titles, "|"), "소개|-Page two|Usage"; got != want {
+		t.Errorf("outline = %q, want %q", got, want)
+	}
+	info := r.Trailer().Key("Info")
+	if got := info.Key("Title").Text(); got != "v2" {
+		t.Errorf("Info /Title = %q, want %q", got, "v2")
+	}
+	if got := r.Trailer().Key("Root").Key("Metadata").Key("Subtype").Name(); got != "XML" {
+		t.Errorf("catalog /Metadata subtype = %q", got)
+	}
+
+	result, err := analyzer.Analyze(bytes.NewReader(updated), int64(len(updated)),
+		[]analyzer.SectionInput{{ID: "intro", Title: "Intro", Level: 1}, {ID: "usage", Title: "Usage", Level: 1}},
+		analyzer.Options{Logger: logging.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalPages != 3 || result.SkipPages != 1 {
+		t.Errorf("pages = %d, skipped = %d, want 3, 1", result.TotalPages, result.SkipPages)
+	}
+	for i, want := range []int{1, 2} {
+		if s := result.Sections[i]; s.Page != want || s.Method != analyzer.MethodDestination {
+			t.Errorf("section %s: page %d (%s), want %d (destination)", s.ID, s.Page, s.Method, want)
+		}
+	}
+}
+
+func TestUpdateXrefStream(t *testing.T) {
+	opts := finisher.Options{Outline: []finisher.Bookmark{{Title: "Usage", Page: 2}}, Logger: logging.Discard}
+	tests := []struct {
+		name       string
+		compressed map[int]bool
+		wantErr    string
+	}{
+		{"plain objects", nil, ""},
+		{"catalog in object stream", map[int]bool{1: true}, "object 1 is stored in object stream 99"},
+		{"page in object stream", map[int]bool{4: true}, "object 4 is stored in object stream 99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := finisher.Update(buildXrefStreamPDF(testObjects, tt.compressed), opts)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package finisher
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Metadata는 PDF Info 딕셔너리와 XMP 패킷으로 기록되는 문서 정보
+type Metadata struct {
+	Title     string
+	Subtitle  string
+	Author    string
+	Subject   string
+	Keywords  []string
+	Version   string
+	Copyright string
+	Creator   string // 원본 문서를 만든 응용 프로그램
+	Producer  string // PDF를 만든 응용 프로그램
+	Date      time.Time
+}
+
+// writeInfo는 새 Info 딕셔너리를 덧붙이고 그 참조를 반환한다.
+func (d *document) writeInfo(m *Metadata) string {
+	var b strings.Builder
+	b.WriteString("<<")
+	entry := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, " /%s %s", key, encodeText(value))
+		}
+	}
+	entry("Title", m.Title)
+	entry("Author", m.Author)
+	entry("Subject", m.Subject)
+	entry("Keywords", strings.Join(m.Keywords, ", "))
+	entry("Creator", m.Creator)
+	entry("Producer", m.Producer)
+	entry("CreationDate", pdfDate(m.Date))
+	entry("ModDate", pdfDate(m.Date))
+	// 사용자 정의 키 (PDF 32000-1 14.3.3은 임의의 Info 항목을 허용)
+	entry("Subtitle", m.Subtitle)
+	entry("Version", m.Version)
+	entry("Copyright", m.Copyright)
+	b.WriteString(" >>")
+	return d.add(d.alloc(), []byte(b.String()))
+}
+
+// writeXMP는 XMP 메타데이터 스트림을 덧붙이고 그 참조를 반환한다.
+func (d *document) writeXMP(m *Metadata) string {
+	packet := xmpPacket(m)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n", len(packet))
+	b.Write(packet)
+	b.WriteString("\nendstream")
+	return d.add(d.alloc(), b.Bytes())
+}
+
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
+
+func xmpPacket(m *Metadata) []byte {
+	esc := func(s string) string {
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(s))
+		return buf.String()
+	}
+	langAlt := func(tag, value string) string {
+		if value == "" {
+			return ""
+		}
+		return fmt.Sprintf("   <%s><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></%s>\n", tag, esc(value), tag)
+	}
+	simple := func(tag, value string) string {
+		if value == "" {
+			return ""
+		}
+		return fmt.Sprintf("   <%s>%s</%s>\n", tag, esc(value), tag)
+	}
+
+	date := m.Date.Format(time.RFC3339)
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\xef\xbb\xbf\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("  <rdf:Description rdf:about=\"\"\n")
+	b.WriteString("    xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	b.WriteString("    xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	b.WriteString("    xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	b.WriteString("    xmlns:xmpMM=\"http://ns.adobe.com/xap/1.0/mm/\">\n")
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	b.WriteString(langAlt("dc:title", m.Title))
+	b.WriteString(langAlt("dc:description", m.Subject))
+	b.WriteString(langAlt("dc:rights", m.Copyright))
+	if m.Author != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", esc(m.Author))
+	}
+	if len(m.Keywords) > 0 {
+		b.WriteString("   <dc:subject><rdf:Bag>")
+		for _, k := range m.Keywords {
+			fmt.Fprintf(&b, "<rdf:li>%s</rdf:li>", esc(k))
+		}
+		b.WriteString("</rdf:Bag></dc:subject>\n")
+	}
+	b.WriteString(simple("pdf:Keywords", strings.Join(m.Keywords, ", ")))
+	b.WriteString(simple("pdf:Producer", m.Producer))
+	b.WriteString(simple("xmp:CreatorTool", m.Creator))
+	b.WriteString(simple("xmp:CreateDate", date))
+	b.WriteString(simple("xmp:ModifyDate", date))
+	b.WriteString(simple("xmp:MetadataDate", date))
+	b.WriteString(simple("xmpMM:VersionID", m.Version))
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString("<?xpacket end=\"w\"?>")
+	return b.Bytes()
+}
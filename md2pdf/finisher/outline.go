@@ -0,0 +1,161 @@
+package finisher
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Bookmark는 PDF 문서 개요(북마크)의 항목
+type Bookmark struct {
+	Title    string
+	Dest     string // Named Destination(요소 id); PDF에 있으면 우선 사용
+	Page     int    // 물리 쪽 번호(1부터); Dest가 없을 때 사용
+	Children []Bookmark
+}
+
+// writeOutline은 개요 트리 객체를 덧붙이고 /Outlines 딕셔너리의 참조를
+// 반환한다.
+func (d *document) writeOutline(bookmarks []Bookmark) (string, error) {
+	pages, err := d.pageRefs()
+	if err != nil {
+		return "", err
+	}
+	if len(pages) == 0 {
+		return "", fmt.Errorf("PDF page tree not found")
+	}
+	dests := d.namedDestinations()
+
+	rootNum := d.alloc()
+	first, last := d.writeBookmarks(bookmarks, rootNum, pages, dests)
+	d.add(rootNum, []byte(fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>",
+		first, last, len(bookmarks))))
+	return fmt.Sprintf("%d 0 R", rootNum), nil
+}
+
+// writeBookmarks는 한 단계의 형제 항목을 기록하고 첫 항목과 마지막 항목의
+// 객체 번호를 반환한다. 하위 항목이 있는 항목은 접힌 상태(음수 /Count)로
+// 기록한다.
+func (d *document) writeBookmarks(items []Bookmark, parent int, pages []string, dests destinationSet) (int, int) {
+	nums := make([]int, len(items))
+	for i := range items {
+		nums[i] = d.alloc()
+	}
+
+	for i, item := range items {
+		var b strings.Builder
+		fmt.Fprintf(&b, "<< /Title %s /Parent %d 0 R", encodeText(item.Title), parent)
+		if i > 0 {
+			fmt.Fprintf(&b, " /Prev %d 0 R", nums[i-1])
+		}
+		if i < len(items)-1 {
+			fmt.Fprintf(&b, " /Next %d 0 R", nums[i+1])
+		}
+		if len(item.Children) > 0 {
+			first, last := d.writeBookmarks(item.Children, nums[i], pages, dests)
+			fmt.Fprintf(&b, " /First %d 0 R /Last %d 0 R /Count -%d", first, last, len(item.Children))
+		}
+		if dest := dests.lookup(item.Dest); dest != "" {
+			fmt.Fprintf(&b, " /Dest %s", dest)
+		} else if item.Page >= 1 && item.Page <= len(pages) {
+			fmt.Fprintf(&b, " /Dest [%s /Fit]", pages[item.Page-1])
+		}
+		b.WriteString(" >>")
+		d.add(nums[i], []byte(b.String()))
+	}
+
+	if len(nums) == 0 {
+		return 0, 0
+	}
+	return nums[0], nums[len(nums)-1]
+}
+
+// destinationSet은 PDF에 있는 Named Destination과 그 참조 방식을 기록한다.
+// 카탈로그의 /Dests 딕셔너리(Chrome 방식)는 이름 객체로, /Names 이름 트리는
+// 문자열로 참조해야 한다.
+type destinationSet struct {
+	names   map[string]bool
+	strings map[string]bool
+}
+
+func (s destinationSet) lookup(id string) string {
+	switch {
+	case id == "":
+		return ""
+	case s.names[id]:
+		return encodeName(id)
+	case s.strings[id]:
+		return fmt.Sprintf("<%X>", id)
+	}
+	return ""
+}
+
+func (d *document) namedDestinations() destinationSet {
+	set := destinationSet{names: map[string]bool{}, strings: map[string]bool{}}
+	r, err := pdf.NewReader(bytes.NewReader(d.data), int64(len(d.data)))
+	if err != nil {
+		return set
+	}
+	root := r.Trailer().Key("Root")
+	for _, key := range root.Key("Dests").Keys() {
+		set.names[key] = true
+	}
+	var walk func(node pdf.Value, depth int)
+	walk = func(node pdf.Value, depth int) {
+		if node.IsNull() || depth > 32 {
+			return
+		}
+		names := node.Key("Names")
+		for i := 0; i < names.Len(); i += 2 {
+			set.strings[names.Index(i).RawString()] = true
+		}
+		kids := node.Key("Kids")
+		for i := 0; i < kids.Len(); i++ {
+			walk(kids.Index(i), depth+1)
+		}
+	}
+	walk(root.Key("Names").Key("Dests"), 0)
+	return set
+}
+
+// encodeText는 PDF 텍스트 문자열을 인코딩한다. ASCII 텍스트는 리터럴
+// 문자열로, 그 밖에는 BOM을 붙인 UTF-16BE로 기록한다.
+func encodeText(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 0x7e || r < 0x20 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+		return "(" + r.Replace(s) + ")"
+	}
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// encodeName은 PDF 이름 객체를 인코딩하며, 구분 문자와 ASCII가 아닌 바이트는
+// #xx로 이스케이프한다.
+func encodeName(s string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || strings.IndexByte("#/%()<>[]{}", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,272 @@
+package finisher
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// xrefEntry는 객체의 위치로, 바이트 오프셋 또는 객체를 담은 객체 스트림이다.
+type xrefEntry struct {
+	offset int
+	gen    int
+	stream int // 객체 스트림 번호; 일반 객체는 0
+}
+
+var (
+	reObjHeader = regexp.MustCompile(`^\s*(\d+)\s+(\d+)\s+obj\b`)
+	reObjScan   = regexp.MustCompile(`(?:^|[\r\n\s])(\d+)\s+(\d+)\s+obj\b`)
+	reXrefW     = regexp.MustCompile(`/W\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+	reXrefIndex = regexp.MustCompile(`/Index\s*\[([^\]]*)\]`)
+	rePrev      = regexp.MustCompile(`/Prev\s+(\d+)`)
+	reLength    = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	rePredictor = regexp.MustCompile(`/Predictor\s+(\d+)`)
+	reColumns   = regexp.MustCompile(`/Columns\s+(\d+)`)
+)
+
+// readXref는 마지막 xref 구간부터 /Prev를 따라가며 객체 오프셋 맵을 만든다.
+// 새 항목이 우선한다. xref 데이터가 손상되었으면 파일 전체에서 객체 머리를
+// 한 번 검색한다.
+func readXref(data []byte, startXref int) map[int]xrefEntry {
+	xref := make(map[int]xrefEntry)
+	seen := make(map[int]bool)
+	for offset := startXref; offset > 0 && offset < len(data) && !seen[offset]; {
+		seen[offset] = true
+		var trailer []byte
+		var err error
+		if bytes.HasPrefix(bytes.TrimLeft(data[offset:], " \t\r\n"), []byte("xref")) {
+			trailer, err = readXrefTable(data, offset, xref)
+		} else {
+			trailer, err = readXrefStream(data, offset, xref)
+		}
+		if err != nil {
+			return scanObjects(data)
+		}
+		m := rePrev.FindSubmatch(trailer)
+		if m == nil {
+			break
+		}
+		offset, _ = strconv.Atoi(string(m[1]))
+	}
+	if len(xref) == 0 {
+		return scanObjects(data)
+	}
+	return xref
+}
+
+// readXrefTable은 일반 xref 테이블을 읽고 트레일러를 반환한다.
+func readXrefTable(data []byte, offset int, xref map[int]xrefEntry) ([]byte, error) {
+	p := bytes.Index(data[offset:], []byte("xref")) + offset + len("xref")
+	end := bytes.Index(data[p:], []byte("trailer"))
+	if end < 0 {
+		return nil, fmt.Errorf("xref table at %d has no trailer", offset)
+	}
+	fields := bytes.Fields(data[p : p+end])
+	for i := 0; i+1 < len(fields); {
+		first, err1 := strconv.Atoi(string(fields[i]))
+		count, err2 := strconv.Atoi(string(fields[i+1]))
+		if err1 != nil || err2 != nil || i+2+3*count > len(fields) {
+			return nil, fmt.Errorf("invalid xref subsection at %d", offset)
+		}
+		i += 2
+		for n := 0; n < count; n, i = n+1, i+3 {
+			num := first + n
+			if _, ok := xref[num]; ok || string(fields[i+2]) != "n" {
+				continue
+			}
+			off, _ := strconv.Atoi(string(fields[i]))
+			gen, _ := strconv.Atoi(string(fields[i+1]))
+			xref[num] = xrefEntry{offset: off, gen: gen}
+		}
+	}
+	trailer, ok := readDict(data[p+end:])
+	if !ok {
+		return nil, fmt.Errorf("xref table at %d has no trailer", offset)
+	}
+	return trailer, nil
+}
+
+// readXrefStream은 상호 참조 스트림을 읽고 그 딕셔너리를 반환한다.
+func readXrefStream(data []byte, offset int, xref map[int]xrefEntry) ([]byte, error) {
+	if reObjHeader.Find(data[offset:]) == nil {
+		return nil, fmt.Errorf("no xref at %d", offset)
+	}
+	dict, ok := readDict(data[offset:])
+	if !ok || !bytes.Contains(dict, []byte("/XRef")) {
+		return nil, fmt.Errorf("no xref stream at %d", offset)
+	}
+	w := reXrefW.FindSubmatch(dict)
+	if w == nil {
+		return nil, fmt.Errorf("xref stream at %d has no /W", offset)
+	}
+	var widths [3]int
+	for i := range widths {
+		widths[i], _ = strconv.Atoi(string(w[i+1]))
+	}
+	raw, err := streamData(data, offset, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	index := []int{0, -1}
+	if m := reXrefIndex.FindSubmatch(dict); m != nil {
+		index = index[:0]
+		for _, f := range bytes.Fields(m[1]) {
+			v, _ := strconv.Atoi(string(f))
+			index = append(index, v)
+		}
+	}
+	if index[1] < 0 {
+		size, _ := strconv.Atoi(dictEntry(dict, "Size"))
+		index[1] = size
+	}
+
+	rowLen := widths[0] + widths[1] + widths[2]
+	row := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		for n := 0; n < index[i+1]; n, row = n+1, row+1 {
+			if (row+1)*rowLen > len(raw) {
+				return nil, fmt.Errorf("xref stream at %d is truncated", offset)
+			}
+			fields := raw[row*rowLen : (row+1)*rowLen]
+			typ := 1
+			if widths[0] > 0 {
+				typ = readInt(fields[:widths[0]])
+			}
+			f2 := readInt(fields[widths[0] : widths[0]+widths[1]])
+			f3 := readInt(fields[widths[0]+widths[1]:])
+			num := index[i] + n
+			if _, ok := xref[num]; ok {
+				continue
+			}
+			switch typ {
+			case 1:
+				xref[num] = xrefEntry{offset: f2, gen: f3}
+			case 2:
+				xref[num] = xrefEntry{stream: f2}
+			}
+		}
+	}
+	return dict, nil
+}
+
+// streamData는 offset에서 딕셔너리가 시작하는 스트림 객체의 디코딩된 데이터를
+// 반환한다.
+func streamData(data []byte, offset int, dict []byte) ([]byte, error) {
+	start := offset + bytes.Index(data[offset:], dict) + len(dict)
+	k := bytes.Index(data[start:], []byte("stream"))
+	if k < 0 {
+		return nil, fmt.Errorf("object at %d has no stream", offset)
+	}
+	start += k + len("stream")
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+	end := bytes.Index(data[start:], []byte("endstream"))
+	if m := reLength.FindSubmatch(dict); m != nil && len(m[2]) == 0 {
+		if n, _ := strconv.Atoi(string(m[1])); start+n <= len(data) {
+			end = n
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("stream at %d has no end", offset)
+	}
+	raw := data[start : start+end]
+
+	if bytes.Contains(dict, []byte("/FlateDecode")) {
+		r, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("stream at %d: %w", offset, err)
+		}
+		if raw, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("stream at %d: %w", offset, err)
+		}
+	}
+	if m := rePredictor.FindSubmatch(dict); m != nil {
+		if p, _ := strconv.Atoi(string(m[1])); p >= 10 {
+			columns := 1
+			if c := reColumns.FindSubmatch(dict); c != nil {
+				columns, _ = strconv.Atoi(string(c[1]))
+			}
+			return unpredictPNG(raw, columns)
+		}
+	}
+	return raw, nil
+}
+
+// unpredictPNG는 xref 스트림 데이터의 PNG 행 예측(predictor)을 되돌린다.
+func unpredictPNG(data []byte, columns int) ([]byte, error) {
+	var out []byte
+	prev := make([]byte, columns)
+	for len(data) >= columns+1 {
+		filter, row := data[0], append([]byte(nil), data[1:columns+1]...)
+		data = data[columns+1:]
+		for i := range row {
+			var left, upLeft byte
+			if i > 0 {
+				left, upLeft = row[i-1], prev[i-1]
+			}
+			switch filter {
+			case 0:
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += prev[i]
+			case 3:
+				row[i] += byte((int(left) + int(prev[i])) / 2)
+			case 4:
+				row[i] += paeth(left, prev[i], upLeft)
+			default:
+				return nil, fmt.Errorf("unknown PNG predictor %d", filter)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func readInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// scanObjects는 파일 전체의 객체 머리를 매핑한다. 증분 업데이트된 파일처럼
+// 나중 정의가 우선한다.
+func scanObjects(data []byte) map[int]xrefEntry {
+	xref := make(map[int]xrefEntry)
+	for _, m := range reObjScan.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		gen, _ := strconv.Atoi(string(data[m[4]:m[5]]))
+		xref[num] = xrefEntry{offset: m[2], gen: gen}
+	}
+	return xref
+}
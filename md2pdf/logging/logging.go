@@ -0,0 +1,276 @@
+// Package logging은 md2pdf 패키지들이 함께 쓰는 교체 가능한 로거를 제공한다.
+// 메시지는 콘솔 출력의 대괄호 태그([INFO], [WARN], [FOUND], ...)를 그대로
+// 유지하므로 기본 텍스트 로거는 기존 도구와 똑같이 출력하고, 라이브러리
+// 사용자는 자신의 Logger를 연결할 수 있다.
+// JSON은 같은 항목을 CI용 NDJSON 이벤트 스트림으로 쓴다.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level은 로그 항목의 심각도
+type Level int
+
+// 로그 수준
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLevel은 수준 이름(debug, info, warn, error)을 해석한다.
+func ParseLevel(name string) (Level, error) {
+	for l := Debug; l <= Error; l++ {
+		if strings.EqualFold(name, l.String()) {
+			return l, nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level: %s", name)
+}
+
+// Entry의 이벤트 종류
+const (
+	EventLog        = "log"
+	EventPhaseStart = "phase_start"
+	EventPhaseEnd   = "phase_end"
+)
+
+// Entry는 로그 메시지 하나 또는 진행 이벤트
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Tag     string // 대괄호를 뺀 콘솔 태그, 예: "INFO", "FOUND"
+	Message string
+	Event   string        // EventLog(또는 빈 값), EventPhaseStart, EventPhaseEnd
+	Phase   string        // 빌드 단계, 예: "pass1"
+	File    string        // 메시지가 가리키는 원본 파일
+	Line    int           // File의 줄 번호, 1부터 (0 = 알 수 없음)
+	Elapsed time.Duration // 단계 소요 시간 (EventPhaseEnd)
+}
+
+// Logger는 로그 항목을 받는다. 구현은 동시에 호출해도 안전해야 한다.
+type Logger interface {
+	Log(e Entry)
+}
+
+// LoggerFunc는 함수를 Logger 인터페이스로 바꾼다.
+type LoggerFunc func(e Entry)
+
+// Log는 f(e)를 호출한다.
+func (f LoggerFunc) Log(e Entry) { f(e) }
+
+// Discard는 모든 항목을 버린다.
+var Discard Logger = LoggerFunc(func(Entry) {})
+
+// Default는 로거를 지정하지 않았을 때 쓰인다.
+var Default Logger = NewText(os.Stdout, os.Stderr)
+
+// Text는 "[TAG] message" 줄을 쓴다. debug와 info 항목은 out으로,
+// 경고와 오류는 errOut으로 보낸다.
+type Text struct {
+	mu     sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+}
+
+// NewText는 콘솔 로거를 반환한다.
+func NewText(out, errOut io.Writer) *Text {
+	return &Text{out: out, errOut: errOut}
+}
+
+// Log는 항목을 쓴다.
+func (t *Text) Log(e Entry) {
+	w := t.out
+	if e.Level >= Warn {
+		w = t.errOut
+	}
+	msg := e.Message
+	if e.File != "" {
+		if e.Line > 0 {
+			msg = fmt.Sprintf("%s:%d: %s", e.File, e.Line, msg)
+		} else {
+			msg = e.File + ": " + msg
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(w, "[%s] %s\n", e.Tag, msg)
+}
+
+// JSON은 항목마다 JSON 객체 한 줄을 쓴다(NDJSON).
+type JSON struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSON은 NDJSON 이벤트 로거를 반환한다.
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{w: w}
+}
+
+type jsonEntry struct {
+	Time      string `json:"time"`
+	Event     string `json:"event"`
+	Level     string `json:"level"`
+	Tag       string `json:"tag,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Message   string `json:"message,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	ElapsedMS int64  `json:"elapsed_ms,omitempty"`
+}
+
+// Log는 항목을 JSON 한 줄로 쓴다.
+func (j *JSON) Log(e Entry) {
+	event := e.Event
+	if event == "" {
+		event = EventLog
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(jsonEntry{
+		Time:      e.Time.Format(time.RFC3339Nano),
+		Event:     event,
+		Level:     e.Level.String(),
+		Tag:       e.Tag,
+		Phase:     e.Phase,
+		Message:   e.Message,
+		File:      e.File,
+		Line:      e.Line,
+		ElapsedMS: e.Elapsed.Milliseconds(),
+	})
+	if err != nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.w.Write(append(data, '\n'))
+}
+
+// Filter는 min보다 낮은 수준의 항목을 버린다.
+func Filter(l Logger, min Level) Logger {
+	return LoggerFunc(func(e Entry) {
+		if e.Level >= min {
+			l.Log(e)
+		}
+	})
+}
+
+// Counter는 항목을 전달하면서 경고와 오류 수를 센다.
+type Counter struct {
+	next     Logger
+	warnings atomic.Int64
+	errors   atomic.Int64
+}
+
+// NewCounter는 next로 전달하는 Counter를 반환한다.
+func NewCounter(next Logger) *Counter {
+	return &Counter{next: next}
+}
+
+// Log는 항목을 세고 전달한다.
+func (c *Counter) Log(e Entry) {
+	switch {
+	case e.Level == Warn:
+		c.warnings.Add(1)
+	case e.Level >= Error:
+		c.errors.Add(1)
+	}
+	c.next.Log(e)
+}
+
+// Warnings는 지금까지 기록된 경고 수를 반환한다.
+func (c *Counter) Warnings() int { return int(c.warnings.Load()) }
+
+// Errors는 지금까지 기록된 오류 수를 반환한다.
+func (c *Counter) Errors() int { return int(c.errors.Load()) }
+
+// Printer는 패키지 내부에서 쓰는 서식 지정 프런트엔드
+type Printer struct {
+	l    Logger
+	file string
+	line int
+}
+
+// Use는 l에 대한 Printer를 반환한다. l이 nil이면 Default를 쓴다.
+func Use(l Logger) Printer {
+	if l == nil {
+		l = Default
+	}
+	return Printer{l: l}
+}
+
+// Logger는 내부 로거를 반환한다.
+func (p Printer) Logger() Logger { return p.l }
+
+// At은 메시지에 원본 위치를 붙이는 Printer를 반환한다.
+func (p Printer) At(file string, line int) Printer {
+	p.file, p.line = file, line
+	return p
+}
+
+// Tagf는 지정한 태그로 메시지를 기록한다.
+func (p Printer) Tagf(level Level, tag, format string, args ...interface{}) {
+	p.l.Log(Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Tag:     tag,
+		Message: fmt.Sprintf(format, args...),
+		File:    p.file,
+		Line:    p.line,
+	})
+}
+
+// Phase는 빌드 단계 시작을 기록하고, 소요 시간과 함께 종료를 기록하는
+// 함수를 반환한다.
+func (p Printer) Phase(name string) func() {
+	start := time.Now()
+	p.l.Log(Entry{Time: start, Level: Debug, Tag: "PHASE", Event: EventPhaseStart, Phase: name,
+		Message: name + " started"})
+	return func() {
+		elapsed := time.Since(start)
+		p.l.Log(Entry{Time: time.Now(), Level: Debug, Tag: "PHASE", Event: EventPhaseEnd, Phase: name,
+			Message: fmt.Sprintf("%s finished in %.2fs", name, elapsed.Seconds()), Elapsed: elapsed})
+	}
+}
+
+// Debugf는 [DEBUG] 메시지를 기록한다.
+func (p Printer) Debugf(format string, args ...interface{}) { p.Tagf(Debug, "DEBUG", format, args...) }
+
+// Infof는 [INFO] 메시지를 기록한다.
+func (p Printer) Infof(format string, args ...interface{}) { p.Tagf(Info, "INFO", format, args...) }
+
+// Successf는 [SUCCESS] 메시지를 기록한다.
+func (p Printer) Successf(format string, args ...interface{}) {
+	p.Tagf(Info, "SUCCESS", format, args...)
+}
+
+// Warnf는 [WARN] 메시지를 기록한다.
+func (p Printer) Warnf(format string, args ...interface{}) { p.Tagf(Warn, "WARN", format, args...) }
+
+// Errorf는 [ERROR] 메시지를 기록한다.
+func (p Printer) Errorf(format string, args ...interface{}) { p.Tagf(Error, "ERROR", format, args...) }
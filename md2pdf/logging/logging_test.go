@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// collect는 받은 항목을 모으는 로거를 반환한다.
+func collect(entries *[]Entry) Logger {
+	return LoggerFunc(func(e Entry) { *entries = append(*entries, e) })
+}
+
+func TestCounter(t *testing.T) {
+	var entries []Entry
+	c := NewCounter(collect(&entries))
+	log := Use(c)
+	log.Infof("info")
+	log.Warnf("first")
+	log.Tagf(Warn, "MISSING", "second")
+	log.Errorf("failed")
+	log.Debugf("debug")
+	if c.Warnings() != 2 || c.Errors() != 1 {
+		t.Errorf("Warnings() = %d, Errors() = %d, want 2, 1", c.Warnings(), c.Errors())
+	}
+	if len(entries) != 5 {
+		t.Errorf("Counter forwarded %d entries, want 5", len(entries))
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		min  Level
+		want []string
+	}{
+		{Debug, []string{"DEBUG", "INFO", "WARN", "ERROR"}},
+		{Info, []string{"INFO", "WARN", "ERROR"}},
+		{Warn, []string{"WARN", "ERROR"}},
+		{Error, []string{"ERROR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.min.String(), func(t *testing.T) {
+			var entries []Entry
+			log := Use(Filter(collect(&entries), tt.min))
+			log.Debugf("d")
+			log.Infof("i")
+			log.Warnf("w")
+			log.Errorf("e")
+			var tags []string
+			for _, e := range entries {
+				tags = append(tags, e.Tag)
+			}
+			if !reflect.DeepEqual(tags, tt.want) {
+				t.Errorf("tags = %v, want %v", tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	var out, errOut bytes.Buffer
+	log := Use(NewText(&out, &errOut))
+	log.Tagf(Info, "FOUND", "Intro -> page 3")
+	log.At("docs/intro.md", 12).Warnf("Undefined variable %s", ".Missing")
+	log.At("docs/intro.md", 0).Errorf("Could not read")
+
+	if got := out.String(); got != "[FOUND] Intro -> page 3\n" {
+		t.Errorf("out = %q", got)
+	}
+	want := "[WARN] docs/intro.md:12: Undefined variable .Missing\n[ERROR] docs/intro.md: Could not read\n"
+	if got := errOut.String(); got != want {
+		t.Errorf("errOut = %q, want %q", got, want)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var out bytes.Buffer
+	log := Use(NewJSON(&out))
+	end := log.Phase("pass1")
+	log.At("docs/intro.md", 12).Warnf("Undefined variable .Missing")
+	end()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	var events []map[string]interface{}
+	for _, line := range lines {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, m["time"].(string)); err != nil {
+			t.Errorf("time: %v", err)
+		}
+		delete(m, "time")
+		events = append(events, m)
+	}
+
+	// 단계 종료 이벤트의 elapsed_ms는 실행 시간에 따라 달라지므로 존재 여부만 확인
+	delete(events[2], "elapsed_ms")
+	want := []map[string]interface{}{
+		{"event": "phase_start", "level": "debug", "tag": "PHASE", "phase": "pass1", "message": "pass1 started"},
+		{"event": "log", "level": "warn", "tag": "WARN", "message": "Undefined variable .Missing", "file": "docs/intro.md", "line": float64(12)},
+		{"event": "phase_end", "level": "debug", "tag": "PHASE", "phase": "pass1", "message": events[2]["message"]},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v\nwant %v", events, want)
+	}
+	if msg, _ := events[2]["message"].(string); !strings.HasPrefix(msg, "pass1 finished in ") {
+		t.Errorf("phase end message = %q", msg)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"debug", "INFO", "Warn", "error"} {
+		if _, err := ParseLevel(name); err != nil {
+			t.Errorf("ParseLevel(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(\"loud\") succeeded")
+	}
+}
@@ -2,19 +2,30 @@
 // Combines md2html (converter), html2pdf (renderer), and pdf_analyzer (analyzer)
 // into a single binary with 2-Pass TOC page number injection.
 //
-// Usage: md2pdf -i <input_dir> -o <output.pdf> [options]
+// 사용법: md2pdf <command> [options]
+//
+//	md2pdf build -i <input_dir> -o <output.pdf>       2-Pass PDF 빌드
+//	md2pdf html -i <input_dir> -o <output.html>       HTML만 생성
+//	md2pdf render <input.html> [-o output.pdf]        HTML을 PDF로 변환
+//	md2pdf analyze <input.pdf> --sections <json>      섹션별 쪽 번호 분석
+//	md2pdf verify <input.pdf> --sections <json>       목차 앵커/쪽 번호 검증
+//	md2pdf templates list                             내장 템플릿 목록
+//	md2pdf serve -i <input_dir>                       실시간 미리보기
+//
+// 명령 없이 호출하면 md2pdf_v2.sh의 기존 플래그 형식을 받는다
+// (md2pdf -i <input_dir> -o <output.pdf> [-html-only], 이 형식의 -v는 버전 출력).
 package main
 
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
-	"md2pdf/analyzer"
 	"md2pdf/converter"
-	"md2pdf/renderer"
+	"md2pdf/logging"
 )
 
 var (
@@ -22,199 +33,324 @@ var (
 	BuildTime    = ""
 )
 
-func main() {
-	// CLI flags (Same as md2pdf_v2.sh)
-	inputDir := flag.String("i", "", "Input directory containing markdown files (required)")
-	outputFile := flag.String("o", "", "Output PDF file path (required)")
+// 종료 코드. 잘못된 플래그는 2로 종료한다(flag.ExitOnError).
+const (
+	exitFailed   = 1
+	exitWarnings = 3 // 출력은 만들었지만 경고가 있음
+)
 
-	// Document metadata
-	title := flag.String("title", "", "Main title (overrides config)")
-	subtitle := flag.String("subtitle", "", "Subtitle (overrides config)")
-	version := flag.String("version", "", "Document version")
-	author := flag.String("author", "", "Author/Company name (overrides config)")
-	header := flag.String("header", "", "Header text for printed pages (overrides config)")
-	footer := flag.String("footer", "", "Footer text for printed pages (overrides config)")
+// legacyInvocation은 md2pdf_v2.sh의 플래그 전용 형식일 때 설정된다. 이 형식은
+// 경고가 있어도 빌드에 성공하면 예전처럼 0으로 종료한다.
+var legacyInvocation bool
+
+// commands는 서브커맨드 이름과 실행 함수의 매핑
+var commands = map[string]func(args []string){
+	"build":     runBuild,
+	"html":      runHTML,
+	"render":    runRender,
+	"analyze":   runAnalyze,
+	"verify":    runVerify,
+	"templates": runTemplates,
+	"serve":     runServe,
+}
 
-	// Config file (GNU-style: -c / --config)
-	var configFile string
-	flag.StringVar(&configFile, "c", "", "Config file path (AUTHORS.yml)")
-	flag.StringVar(&configFile, "config", "", "Config file path (AUTHORS.yml)")
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
 
-	// Template
-	templateName := flag.String("template", "report", "Template name (default: report)")
+	name := os.Args[1]
+	if run, ok := commands[name]; ok {
+		run(os.Args[2:])
+		return
+	}
 
-	// Output mode
-	htmlOnly := flag.Bool("html-only", false, "Generate HTML only (no PDF conversion)")
+	switch {
+	case name == "version":
+		printVersion()
+	case (name == "-version" || name == "--version") && len(os.Args) == 2:
+		// 기존 형식에서 -version은 문서 버전 플래그이므로 단독으로 쓸 때만 버전 출력
+		printVersion()
+	case name == "help" || name == "-h" || name == "-help" || name == "--help":
+		usage()
+	case strings.HasPrefix(name, "-"):
+		// 기존 형식: md2pdf -i ... -o ... [-html-only] (-v는 버전 출력)
+		legacyInvocation = true
+		runBuild(os.Args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "[ERROR] Unknown command: %s\n\n", name)
+		usage()
+		os.Exit(1)
+	}
+}
 
-	// PDF options
-	skipPages := flag.Int("skip", 0, "Number of pages to skip for TOC analysis (0 = auto-detect)")
-	// offset is accepted for compatibility but we use skip internally
-	_ = flag.Int("offset", 0, "Page number offset (for compatibility)")
+func usage() {
+	fmt.Fprintf(os.Stderr, "md2pdf - Unified Markdown to PDF converter\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: md2pdf <command> [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  build      Build a PDF with TOC page numbers (2-Pass)\n")
+	fmt.Fprintf(os.Stderr, "  html       Generate HTML only\n")
+	fmt.Fprintf(os.Stderr, "  render     Convert an HTML file to PDF\n")
+	fmt.Fprintf(os.Stderr, "  analyze    Find section page numbers in a PDF\n")
+	fmt.Fprintf(os.Stderr, "  verify     Check that every TOC entry resolves in a PDF\n")
+	fmt.Fprintf(os.Stderr, "  templates  List embedded templates\n")
+	fmt.Fprintf(os.Stderr, "  serve      Live preview server\n")
+	fmt.Fprintf(os.Stderr, "  version    Show version\n\n")
+	fmt.Fprintf(os.Stderr, "Run 'md2pdf <command> -h' for command options.\n")
+	fmt.Fprintf(os.Stderr, "Output: -q (warnings only), -v (debug), -log-format json (NDJSON events).\n")
+	fmt.Fprintf(os.Stderr, "Exit codes: 0 success, 1 failure, 2 invalid flags, 3 built with warnings.\n")
+	fmt.Fprintf(os.Stderr, "Legacy form 'md2pdf -i <input_dir> -o <output> [-html-only]' runs build (-v shows the version there; use -verbose).\n")
+}
 
-	// Version flag
-	showVersion := flag.Bool("v", false, "Show version")
+func printVersion() {
+	fmt.Printf("md2pdf v%s (%s)\n", BuildVersion, BuildTime)
+}
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "md2pdf - Unified Markdown to PDF converter\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: md2pdf -i <input_dir> -o <output.pdf|.html> [options]\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
+// parseArgs는 위치 인자 앞뒤 어디에나 올 수 있는 플래그를 해석하고
+// (예: `analyze out.pdf --sections s.json`) 위치 인자를 반환한다.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		_ = fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
+}
 
-	flag.Parse()
+// docFlags는 build, html, serve가 함께 쓰는 문서 옵션
+type docFlags struct {
+	inputDir     *string
+	outputFile   *string
+	configFile   string
+	title        *string
+	subtitle     *string
+	version      *string
+	author       *string
+	header       *string
+	footer       *string
+	templateName *string
+	offline      *bool
+	mermaidCache *string
+	numbering    *bool
+	tocDepth     *int
+	tocExclude   stringList
+	figNumbering *string
+	lof          *bool
+	lot          *bool
+	index        *bool
+	indexTerms   *string
+	glossary     *string
+	drafts       *bool
+	vars         keyValues
+	envVars      *bool
+	varsInCode   *bool
+	strictVars   *bool
+	profiles     stringList
+	tab          *string
+}
 
-	if *showVersion {
-		fmt.Printf("md2pdf v%s (%s)\n", BuildVersion, BuildTime)
-		return
+// addDocFlags는 문서 플래그를 등록한다. outputHelp가 비어 있으면 -o는
+// 등록하지 않는다.
+func addDocFlags(fs *flag.FlagSet, outputHelp string) *docFlags {
+	d := &docFlags{outputFile: new(string)}
+	// CLI flags (Same as md2pdf_v2.sh)
+	d.inputDir = fs.String("i", "", "Input directory containing markdown files (required)")
+	if outputHelp != "" {
+		fs.StringVar(d.outputFile, "o", "", outputHelp)
 	}
 
-	if *inputDir == "" || *outputFile == "" {
-		flag.Usage()
-		os.Exit(1)
+	// Document metadata
+	d.title = fs.String("title", "", "Main title (overrides config)")
+	d.subtitle = fs.String("subtitle", "", "Subtitle (overrides config)")
+	d.version = fs.String("version", "", "Document version")
+	d.author = fs.String("author", "", "Author/Company name (overrides config)")
+	d.header = fs.String("header", "", "Header text for printed pages (overrides config)")
+	d.footer = fs.String("footer", "", "Footer text for printed pages (overrides config)")
+
+	// Config file (GNU-style: -c / --config)
+	fs.StringVar(&d.configFile, "c", "", "Config file path (AUTHORS.yml)")
+	fs.StringVar(&d.configFile, "config", "", "Config file path (AUTHORS.yml)")
+
+	// Template
+	d.templateName = fs.String("template", "report", "Template name (see 'md2pdf templates list')")
+
+	d.offline = fs.Bool("offline", false, "Use only vendored assets; fail if any network request is attempted")
+	d.tocDepth = fs.Int("toc-depth", 0, "Deepest heading level in the TOC: 2=H2, 3=+H3, 4=+H4 (default: config or 2)")
+	fs.Var(&d.tocExclude, "toc-exclude", "Regular expression (`pattern`) for heading titles to leave out of the TOC (repeatable; default: config or '^Q[.\\s]')")
+	d.figNumbering = fs.String("figure-numbering", "global", "Figure/table numbering: global (Figure 3) or chapter (Figure 2.1)")
+	d.lof = fs.Bool("lof", false, "Add a List of Figures after the TOC (report, modern templates)")
+	d.lot = fs.Bool("lot", false, "Add a List of Tables after the TOC (report, modern templates)")
+	d.index = fs.Bool("index", false, "Append a back-of-book index of the {{< index \"...\" >}} entries")
+	d.indexTerms = fs.String("index-terms", "", "YAML `file` of terms to index automatically (implies -index)")
+	d.glossary = fs.String("glossary", "", "Glossary `file` (YAML or Markdown definition list): link terms and add a Glossary appendix")
+	d.drafts = fs.Bool("drafts", false, "Include files marked 'draft: true' in their front matter")
+	fs.Var(&d.vars, "var", "Document variable `key=value` for {{ var.key }} (repeatable; overrides config vars)")
+	d.envVars = fs.Bool("env-vars", false, "Resolve {{ env.NAME }} from environment variables")
+	d.varsInCode = fs.Bool("vars-in-code", false, "Also replace variables in code blocks and code spans")
+	d.strictVars = fs.Bool("strict-vars", false, "Fail the build on undefined variables")
+	fs.Var(&d.profiles, "profile", "Build profile `tags` for '::: only' content, e.g. admin (repeatable or comma-separated; pdf/html is added)")
+	d.tab = fs.String("tab", "", "Keep only the content tab with this `label` (e.g. Linux) in tab groups that have it")
+	d.numbering = fs.Bool("number-headings", false, "Number H1-H3 across the document (1, 1.1, 1.1.1)")
+	d.mermaidCache = fs.String("mermaid-cache", "", "Cache directory for pre-rendered Mermaid SVGs (default: user cache dir)")
+	return d
+}
+
+// converterOptions는 해석한 플래그로 변환 옵션을 만든다.
+func (d *docFlags) converterOptions() converter.Options {
+	return converter.Options{
+		InputDir:        *d.inputDir,
+		OutputFile:      *d.outputFile,
+		ConfigFile:      d.configFile,
+		Title:           *d.title,
+		Subtitle:        *d.subtitle,
+		Version:         *d.version,
+		Author:          *d.author,
+		Header:          *d.header,
+		Footer:          *d.footer,
+		Template:        *d.templateName,
+		EmbedImages:     true,
+		Offline:         *d.offline,
+		DiagramCache:    *d.mermaidCache,
+		NumberHeadings:  *d.numbering,
+		TOCDepth:        *d.tocDepth,
+		TOCExclude:      d.tocExclude,
+		FigureNumbering: *d.figNumbering,
+		ListOfFigures:   *d.lof,
+		ListOfTables:    *d.lot,
+		Index:           *d.index,
+		IndexTerms:      *d.indexTerms,
+		Glossary:        *d.glossary,
+		Drafts:          *d.drafts,
+		Vars:            d.vars,
+		EnvVars:         *d.envVars,
+		VarsInCode:      *d.varsInCode,
+		StrictVars:      *d.strictVars,
+		Profiles:        d.profiles,
+		Tab:             *d.tab,
 	}
+}
 
-	// === HTML-only mode ===
-	if *htmlOnly {
-		if !strings.HasSuffix(strings.ToLower(*outputFile), ".html") {
-			*outputFile = strings.TrimSuffix(*outputFile, filepath.Ext(*outputFile)) + ".html"
-		}
+// stringList는 여러 번 지정할 수 있는 문자열 플래그
+type stringList []string
 
-		fmt.Println("==============================================================================")
-		fmt.Println(" md2pdf - HTML Generation (html-only mode)")
-		fmt.Println("==============================================================================")
-		fmt.Println()
-
-		opts := converter.Options{
-			InputDir:    *inputDir,
-			OutputFile:  *outputFile,
-			ConfigFile:  configFile,
-			Title:       *title,
-			Subtitle:    *subtitle,
-			Version:     *version,
-			Author:      *author,
-			Header:      *header,
-			Footer:      *footer,
-			Template:    *templateName,
-			EmbedImages: true,
-			PDFMode:     false,
-		}
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
 
-		_, err := converter.ConvertToHTML(opts)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "[ERROR] HTML generation failed: %v\n", err)
-			os.Exit(1)
-		}
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
 
-		fmt.Println()
-		fmt.Println("==============================================================================")
-		fmt.Printf("[SUCCESS] HTML generated: %s\n", *outputFile)
-		fmt.Println("==============================================================================")
-		return
+// keyValues는 반복할 수 있는 key=value 플래그다.
+type keyValues map[string]string
+
+func (kv *keyValues) String() string {
+	pairs := make([]string, 0, len(*kv))
+	for k, v := range *kv {
+		pairs = append(pairs, k+"="+v)
 	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
 
-	// === PDF mode (default) ===
-	if !strings.HasSuffix(strings.ToLower(*outputFile), ".pdf") {
-		*outputFile += ".pdf"
+func (kv *keyValues) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(k) == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
 	}
+	if *kv == nil {
+		*kv = make(keyValues)
+	}
+	(*kv)[strings.TrimSpace(k)] = v
+	return nil
+}
 
-	fmt.Println("==============================================================================")
-	fmt.Println(" md2pdf - 2-Pass PDF Generation with Accurate TOC Page Numbers")
-	fmt.Println("==============================================================================")
-	fmt.Println()
+// logFlags는 명령들이 함께 쓰는 출력 옵션
+type logFlags struct {
+	out     io.Writer
+	quiet   *bool
+	verbose *bool
+	format  *string
+	counter *logging.Counter
+}
 
-	// Create temp directory for intermediate files
-	tmpDir, err := os.MkdirTemp("", "md2pdf-*")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Failed to create temp directory: %v\n", err)
-		os.Exit(1)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	htmlPass1 := filepath.Join(tmpDir, "pass1.html")
-	pdfPass1 := filepath.Join(tmpDir, "pass1.pdf")
-	sectionsJSON := filepath.Join(tmpDir, "sections.json")
-	pagesJSON := filepath.Join(tmpDir, "pages.json")
-	htmlPass2 := filepath.Join(tmpDir, "pass2.html")
-
-	// ======================================================================
-	// PASS 1: Generate HTML without page numbers, then render to PDF
-	// ======================================================================
-	fmt.Println("[PASS 1] Generating HTML (without page numbers)...")
-
-	baseOpts := converter.Options{
-		InputDir:     *inputDir,
-		OutputFile:   htmlPass1,
-		ConfigFile:   configFile,
-		Title:        *title,
-		Subtitle:     *subtitle,
-		Version:      *version,
-		Author:       *author,
-		Header:       *header,
-		Footer:       *footer,
-		Template:     *templateName,
-		EmbedImages:  true,
-		PDFMode:      true,
-		SectionsJSON: sectionsJSON,
-	}
-
-	_, err = converter.ConvertToHTML(baseOpts)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Pass 1 HTML generation failed: %v\n", err)
-		os.Exit(1)
+// addLogFlags는 -q, -v, -log-format을 등록한다. 로그는 out으로 출력한다
+// (텍스트 형식에서 경고와 오류는 stderr).
+func addLogFlags(fs *flag.FlagSet, out io.Writer) *logFlags {
+	l := &logFlags{out: out, verbose: new(bool)}
+	l.quiet = fs.Bool("q", false, "Quiet: print warnings and errors only")
+	if fs.Lookup("v") == nil { // 기존 형식의 build는 -v를 버전 출력에 사용
+		fs.BoolVar(l.verbose, "v", false, "Verbose: include debug messages and phase timings")
 	}
+	fs.BoolVar(l.verbose, "verbose", false, "Verbose: include debug messages and phase timings")
+	l.format = fs.String("log-format", "text", "Log format: text or json (NDJSON events)")
+	return l
+}
 
-	fmt.Println("[PASS 1] Converting HTML to PDF...")
-	err = renderer.RenderToPDF(htmlPass1, pdfPass1, renderer.Options{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Pass 1 PDF generation failed: %v\n", err)
-		os.Exit(1)
+// setup은 설정한 로거를 logging.Default로 지정한다. 플래그 해석 후에
+// 호출한다.
+func (l *logFlags) setup() {
+	var out logging.Logger
+	level := logging.Info
+	switch *l.format {
+	case "text":
+		out = logging.NewText(l.out, os.Stderr)
+	case "json":
+		out = logging.NewJSON(l.out)
+		level = logging.Debug
+	default:
+		fmt.Fprintf(os.Stderr, "[ERROR] Unknown log format: %s (text or json)\n", *l.format)
+		os.Exit(2)
+	}
+	if *l.verbose {
+		level = logging.Debug
 	}
+	if *l.quiet {
+		level = logging.Warn
+	}
+	l.counter = logging.NewCounter(logging.Filter(out, level))
+	logging.Default = l.counter
+}
 
-	// ======================================================================
-	// ANALYSIS: Extract page numbers from Pass 1 PDF
-	// ======================================================================
-	fmt.Println("[ANALYSIS] Analyzing PDF for page numbers...")
+// console은 배너와 요약을 출력할지 알려준다.
+func (l *logFlags) console() bool {
+	return *l.format == "text" && !*l.quiet
+}
 
-	result, err := analyzer.AnalyzePDF(pdfPass1, sectionsJSON, *skipPages)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] PDF analysis failed: %v\n", err)
-		os.Exit(1)
+// banner는 콘솔 모드에서 구역 배너를 출력한다.
+func (l *logFlags) banner(lines ...string) {
+	if !l.console() {
+		return
 	}
-
-	err = analyzer.SaveResult(result, pagesJSON)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Failed to save analysis result: %v\n", err)
-		os.Exit(1)
+	fmt.Println("==============================================================================")
+	for _, line := range lines {
+		fmt.Println(line)
 	}
+	fmt.Println("==============================================================================")
+}
 
-	// ======================================================================
-	// PASS 2: Regenerate HTML with page numbers, then render final PDF
-	// ======================================================================
-	fmt.Println("[PASS 2] Regenerating HTML (with page numbers)...")
-
-	pass2Opts := baseOpts
-	pass2Opts.OutputFile = htmlPass2
-	pass2Opts.SectionsJSON = "" // Don't regenerate sections
-	pass2Opts.PagesJSON = pagesJSON
+// fail은 오류를 기록하고 exitFailed로 종료한다.
+func (l *logFlags) fail(format string, args ...interface{}) {
+	logging.Use(nil).Errorf(format, args...)
+	os.Exit(exitFailed)
+}
 
-	_, err = converter.ConvertToHTML(pass2Opts)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Pass 2 HTML generation failed: %v\n", err)
-		os.Exit(1)
+// exit는 성공한 명령을 끝낸다. 경고가 있었으면 exitWarnings로 종료한다.
+func (l *logFlags) exit() {
+	if l.counter != nil && l.counter.Warnings() > 0 && !legacyInvocation {
+		os.Exit(exitWarnings)
 	}
+}
 
-	fmt.Println("[PASS 2] Converting to final PDF...")
-	err = renderer.RenderToPDF(htmlPass2, *outputFile, renderer.Options{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[ERROR] Final PDF generation failed: %v\n", err)
-		os.Exit(1)
+func commandUsage(fs *flag.FlagSet, title, usageLine string) func() {
+	return func() {
+		fmt.Fprintf(os.Stderr, "md2pdf %s - %s\n\n", fs.Name(), title)
+		fmt.Fprintf(os.Stderr, "Usage: %s\n\n", usageLine)
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
 	}
-
-	// ======================================================================
-	// SUCCESS
-	// ======================================================================
-	fmt.Println()
-	fmt.Println("==============================================================================")
-	fmt.Printf("[SUCCESS] PDF generated: %s\n", *outputFile)
-	fmt.Println("==============================================================================")
 }
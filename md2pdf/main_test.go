@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// MD2PDF_TEST_ARGS가 설정되면 테스트 바이너리가 그 인자로 md2pdf main을 실행한다.
+func init() {
+	if args, ok := os.LookupEnv("MD2PDF_TEST_ARGS"); ok {
+		os.Args = append([]string{"md2pdf"}, strings.Fields(args)...)
+		main()
+		os.Exit(0)
+	}
+}
+
+// runMain은 args로 md2pdf를 실행하고 표준 출력과 종료 코드를 반환한다.
+func runMain(t *testing.T, args string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), "MD2PDF_TEST_ARGS="+args)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return string(out), exitErr.ExitCode()
+	case err != nil:
+		t.Fatal(err)
+	}
+	return string(out), 0
+}
+
+func TestVersionFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        string
+		wantVersion bool
+		wantCode    int
+	}{
+		{"version command", "version", true, 0},
+		{"legacy -v alone", "-v", true, 0},
+		{"legacy -v with build flags", "-i docs -o out.pdf -v", true, 0},
+		{"--version alone", "--version", true, 0},
+		// 기존 형식의 -version은 문서 버전이므로 빌드로 처리 (-o가 없어 사용법 출력)
+		{"legacy -version document version", "-version 1.2 -i docs", false, 1},
+		// 서브커맨드 형식의 -v는 상세 출력
+		{"build -v is verbose", "build -v", false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, code := runMain(t, tt.args)
+			if got := strings.HasPrefix(out, "md2pdf v"); got != tt.wantVersion || code != tt.wantCode {
+				t.Errorf("md2pdf %s: version printed = %v, exit %d; want %v, exit %d\n%s",
+					tt.args, got, code, tt.wantVersion, tt.wantCode, out)
+			}
+		})
+	}
+}
+
+func TestWarningExitCode(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "intro.md"), []byte("# Intro\n\n{{ .Missing }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clean := t.TempDir()
+	if err := os.WriteFile(filepath.Join(clean, "intro.md"), []byte("# Intro\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := t.TempDir()
+	if err := os.WriteFile(filepath.Join(broken, "intro.md"), []byte("# Intro\n\nSee @fig:missing.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "out.html")
+
+	tests := []struct {
+		name     string
+		args     string
+		wantCode int
+	}{
+		{"html with warnings", "html -i " + dir + " -o " + out, exitWarnings},
+		{"build -html-only with warnings", "build -html-only -i " + dir + " -o " + out, exitWarnings},
+		{"quiet still counts warnings", "html -q -i " + dir + " -o " + out, exitWarnings},
+		{"html without warnings", "html -i " + clean + " -o " + out, 0},
+		// 기존 플래그 형식은 경고가 있어도 예전처럼 0으로 종료
+		{"legacy form with warnings", "-html-only -i " + dir + " -o " + out, 0},
+		{"failure", "html -i " + filepath.Join(dir, "missing") + " -o " + out, exitFailed},
+		// 없는 라벨 참조는 경고가 아니라 빌드 실패
+		{"unresolved reference", "html -i " + broken + " -o " + out, exitFailed},
+		{"build -html-only unresolved reference", "build -html-only -i " + broken + " -o " + out, exitFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, code := runMain(t, tt.args); code != tt.wantCode {
+				t.Errorf("md2pdf %s: exit %d, want %d", tt.args, code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJSONLogFormat(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "intro.md"), []byte("# Intro\n\n{{ .Missing }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, code := runMain(t, "html -log-format json -i "+dir+" -o "+filepath.Join(t.TempDir(), "out.html"))
+	if code != exitWarnings {
+		t.Errorf("exit %d, want %d", code, exitWarnings)
+	}
+	// 표준 출력은 배너 없이 NDJSON 이벤트만 담음
+	var warned bool
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var e struct {
+			Event, Level, Message, File string
+			Line                        int
+		}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("stdout line is not JSON: %q", line)
+		}
+		if e.Level == "warn" && e.Message == "Undefined variable .Missing" && e.Line == 3 && strings.HasSuffix(e.File, "intro.md") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("no warning event with file and line:\n%s", out)
+	}
+}
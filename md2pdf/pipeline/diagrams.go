@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"md2pdf/converter"
+	"md2pdf/logging"
+	"md2pdf/renderer"
+)
+
+// Diagrams는 converter(converter.Options.Diagrams)를 위해 브라우저 세션에서
+// Mermaid 다이어그램을 미리 렌더링한다. 세션이 없으면 처음 쓸 때 Chrome을
+// 띄우므로, 다이어그램이 모두 캐시된 문서는 Chrome을 띄우지 않는다.
+type Diagrams struct {
+	ctx     context.Context
+	logger  logging.Logger
+	mu      sync.Mutex
+	session *renderer.Session
+	owned   bool // Diagrams가 띄운 세션
+}
+
+// NewDiagrams는 session을 쓰는 다이어그램 렌더러를 반환한다. session이 nil이면
+// 자체 브라우저를 쓴다. 다 쓰면 Close를 호출한다.
+func NewDiagrams(ctx context.Context, session *renderer.Session, logger logging.Logger) *Diagrams {
+	return &Diagrams{ctx: ctx, session: session, logger: logger}
+}
+
+// RenderMermaid는 converter.DiagramRenderer를 구현한다.
+func (d *Diagrams) RenderMermaid(script, url string, diagrams []converter.Diagram) ([]converter.DiagramResult, error) {
+	d.mu.Lock()
+	if d.session == nil {
+		session, err := renderer.NewSession(d.ctx, renderer.SessionOptions{MaxTabs: 1, Logger: d.logger})
+		if err != nil {
+			d.mu.Unlock()
+			return nil, err
+		}
+		d.session, d.owned = session, true
+	}
+	session := d.session
+	d.mu.Unlock()
+
+	input := make([]renderer.MermaidDiagram, len(diagrams))
+	for i, dg := range diagrams {
+		input[i] = renderer.MermaidDiagram{ID: dg.ID, Source: dg.Source}
+	}
+	out, err := session.RenderMermaid(d.ctx, script, url, input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]converter.DiagramResult, len(out))
+	for i, r := range out {
+		results[i] = converter.DiagramResult{SVG: r.SVG, Error: r.Error}
+	}
+	return results, nil
+}
+
+// Close는 Diagrams가 띄운 브라우저를 종료한다.
+func (d *Diagrams) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.owned {
+		d.session.Close()
+		d.session, d.owned = nil, false
+	}
+}
@@ -0,0 +1,76 @@
+package pipeline
+
+import (
+	"md2pdf/converter"
+	"md2pdf/finisher"
+)
+
+// Outline은 섹션 트리를 PDF 북마크로 바꾼다.
+// 쪽 번호는 analyzer가 찾은 문서 쪽 번호이고, 앵커가 없는 항목은
+// skipPages로 실제 PDF 쪽으로 되돌린다.
+func Outline(sections []converter.Section, skipPages int, opts OutlineOptions) []finisher.Bookmark {
+	var bookmarks []finisher.Bookmark
+	if opts.Cover {
+		bookmarks = append(bookmarks, finisher.Bookmark{Title: "표지", Page: 1})
+	}
+	if opts.TOC && skipPages >= 2 {
+		bookmarks = append(bookmarks, finisher.Bookmark{Title: "목차", Page: 2})
+	}
+
+	physical := func(page int) int {
+		if page <= 0 {
+			return 0
+		}
+		return page + skipPages
+	}
+
+	for _, s := range sections {
+		if s.Unlisted {
+			continue
+		}
+		item := finisher.Bookmark{Title: s.Title, Dest: s.ID, Page: physical(s.PageNumber)}
+		for _, sub := range s.SubHeadings {
+			if sub.Level > opts.Depth {
+				continue
+			}
+			child := finisher.Bookmark{Title: sub.Title, Dest: sub.ID, Page: physical(sub.PageNumber)}
+			item.Children = nestBookmark(item.Children, child, sub.Level-2)
+		}
+		bookmarks = append(bookmarks, item)
+	}
+	return bookmarks
+}
+
+// nestBookmark는 child를 list의 마지막 항목을 따라 depth 수준 아래에, 즉
+// 앞의 더 얕은 제목 아래에 추가한다(없으면 더 위 수준에 추가).
+func nestBookmark(list []finisher.Bookmark, child finisher.Bookmark, depth int) []finisher.Bookmark {
+	if depth > 0 && len(list) > 0 {
+		last := &list[len(list)-1]
+		last.Children = nestBookmark(last.Children, child, depth-1)
+		return list
+	}
+	return append(list, child)
+}
+
+// Metadata는 확정된 문서 정보를 PDF 메타데이터로 옮긴다.
+func Metadata(info converter.DocumentInfo, producer string) *finisher.Metadata {
+	subject := info.Subject
+	if subject == "" {
+		subject = info.Subtitle
+	}
+	if producer == "" {
+		producer = "md2pdf (Chrome/Skia)"
+	}
+	return &finisher.Metadata{
+		Title:     info.Title,
+		Subtitle:  info.Subtitle,
+		Author:    info.Author,
+		Subject:   subject,
+		Keywords:  info.Keywords,
+		Version:   info.Version,
+		Copyright: info.Copyright,
+		Date:      info.Date,
+		Creator:   "md2pdf",
+		Producer:  producer,
+	}
+}
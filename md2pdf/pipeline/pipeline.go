@@ -0,0 +1,280 @@
+// Package pipeline은 md2pdf 빌드 전체를 라이브러리로 실행한다:
+// Markdown -> HTML -> PDF -> 쪽 분석 -> 쪽 번호가 들어간 HTML ->
+// 최종 PDF -> 북마크와 메타데이터.
+//
+//	var pdf bytes.Buffer
+//	res, err := pipeline.Build(ctx, pipeline.Options{
+//		Document: converter.Options{InputDir: "docs", ConfigFile: "AUTHORS.yml"},
+//		PDF:      &pdf,
+//	})
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"md2pdf/analyzer"
+	"md2pdf/converter"
+	"md2pdf/finisher"
+	"md2pdf/logging"
+	"md2pdf/renderer"
+)
+
+// 빌드 단계. Warning.Stage와 로그 항목의 단계로 보고된다
+const (
+	StageHTML     = "html" // HTML만 빌드
+	StagePass1    = "pass1"
+	StageAnalysis = "analysis"
+	StagePass2    = "pass2"
+	StageFinish   = "finish"
+)
+
+// OutlineOptions는 PDF 북마크 설정
+type OutlineOptions struct {
+	Depth int  // 1=섹션, 2=+H2, 3=+H3, 4=+H4 (0 = 북마크 없음, 목차 깊이까지)
+	Cover bool // 표지 북마크 추가
+	TOC   bool // 목차 북마크 추가
+}
+
+// Options는 Build 옵션
+type Options struct {
+	// Document는 입력, 설정, 메타데이터, 템플릿 옵션이다.
+	// Document.Diagrams를 지정하지 않으면 Mermaid 다이어그램은 빌드의
+	// 브라우저에서 미리 렌더링한다.
+	// OutputFile, Output, SectionsJSON, PagesJSON, Pages, PDFMode,
+	// Logger는 Build가 관리한다.
+	Document converter.Options
+
+	PDF  io.Writer // 최종 PDF, nil이면 HTML만 빌드
+	HTML io.Writer // 최종 HTML (PDF가 있으면 인쇄 레이아웃, 없으면 웹 레이아웃)
+
+	SkipPages    int // 본문 1쪽 앞의 쪽 수 (0 = 자동 감지)
+	ReadyTimeout int // 글꼴, 이미지, 다이어그램을 기다릴 초 (기본값: 30)
+	Outline      OutlineOptions
+	Producer     string // PDF Producer (기본값: "md2pdf (Chrome/Skia)")
+
+	// Session은 실행 중인 브라우저를 재사용한다(예: 여러 문서 빌드).
+	// nil이면 이 빌드용 브라우저를 띄운다.
+	Session *renderer.Session
+	Logger  logging.Logger // 기본값: logging.Default
+}
+
+// Warning은 빌드 중 보고된 치명적이지 않은 문제
+type Warning struct {
+	Stage   string `json:"stage"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// Result는 빌드 결과
+type Result struct {
+	Sections []converter.Section `json:"sections"`        // 쪽 번호가 들어간 최종 섹션
+	Pages    *analyzer.Result    `json:"pages,omitempty"` // 쪽 분석 결과 (HTML만 빌드하면 nil)
+	Warnings []Warning           `json:"warnings,omitempty"`
+}
+
+// Build는 opts에 따라 빌드한다. 경고는 기록하고 결과에도 담으며,
+// 출력을 만들지 못한 경우에만 오류를 반환한다.
+func Build(ctx context.Context, opts Options) (*Result, error) {
+	if opts.PDF == nil && opts.HTML == nil {
+		return nil, fmt.Errorf("no output: set Options.PDF and/or Options.HTML")
+	}
+
+	rec := &recorder{next: opts.Logger}
+	if rec.next == nil {
+		rec.next = logging.Default
+	}
+	log := logging.Use(rec)
+	result := &Result{}
+
+	docOpts := opts.Document
+	docOpts.Logger = rec
+	docOpts.SectionsJSON = ""
+	docOpts.PagesJSON = ""
+	docOpts.Pages = nil
+
+	if opts.PDF == nil {
+		defer rec.stage(log, StageHTML)()
+		if docOpts.Diagrams == nil {
+			diagrams := NewDiagrams(ctx, nil, rec)
+			defer diagrams.Close()
+			docOpts.Diagrams = diagrams
+		}
+		docOpts.Output = opts.HTML
+		sections, err := converter.ConvertToHTML(docOpts)
+		if err != nil {
+			return nil, fmt.Errorf("HTML generation failed: %w", err)
+		}
+		result.Sections = sections
+		result.Warnings = rec.warnings
+		return result, nil
+	}
+
+	// 중간 HTML은 Chrome이 최종 문서와 같은 방식으로 해석하도록 파일로 쓴다
+	tmpDir, err := os.MkdirTemp("", "md2pdf-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	session := opts.Session
+	if session == nil {
+		session, err = renderer.NewSession(ctx, renderer.SessionOptions{Logger: rec})
+		if err != nil {
+			return nil, err
+		}
+		defer session.Close()
+	}
+	if docOpts.Diagrams == nil {
+		docOpts.Diagrams = NewDiagrams(ctx, session, rec)
+	}
+
+	docOpts.PDFMode = true
+	docOpts.InlineAssets = true
+	renderOpts := renderer.Options{ReadyTimeout: opts.ReadyTimeout, Offline: docOpts.Offline, Logger: rec}
+
+	// PASS 1: 쪽 번호 없는 HTML을 분석용으로 렌더링
+	end := rec.stage(log, StagePass1)
+	log.Tagf(logging.Info, "PASS 1", "Generating HTML (without page numbers)...")
+	pass1Opts := docOpts
+	pass1Opts.OutputFile = filepath.Join(tmpDir, "pass1.html")
+	sections, err := converter.ConvertToHTML(pass1Opts)
+	if err != nil {
+		return nil, fmt.Errorf("pass 1 HTML generation failed: %w", err)
+	}
+
+	log.Tagf(logging.Info, "PASS 1", "Converting HTML to PDF...")
+	pdf1, err := session.RenderPDF(ctx, pass1Opts.OutputFile, renderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("pass 1 PDF generation failed: %w", err)
+	}
+	end()
+
+	// ANALYSIS: pass 1 PDF에서 쪽 번호 추출
+	end = rec.stage(log, StageAnalysis)
+	log.Tagf(logging.Info, "ANALYSIS", "Analyzing PDF for page numbers...")
+	pages, err := analyzer.Analyze(bytes.NewReader(pdf1), int64(len(pdf1)), analyzerSections(sections),
+		analyzer.Options{SkipPages: opts.SkipPages, Logger: rec})
+	if err != nil {
+		return nil, fmt.Errorf("PDF analysis failed: %w", err)
+	}
+	result.Pages = pages
+	end()
+
+	// PASS 2: 쪽 번호가 들어간 HTML을 최종 PDF로 렌더링
+	end = rec.stage(log, StagePass2)
+	log.Tagf(logging.Info, "PASS 2", "Regenerating HTML (with page numbers)...")
+	pass2Opts := docOpts
+	pass2Opts.OutputFile = filepath.Join(tmpDir, "pass2.html")
+	pass2Opts.Pages = make(map[string]int, len(pages.Sections))
+	for _, p := range pages.Sections {
+		pass2Opts.Pages[p.ID] = p.Page
+	}
+	result.Sections, err = converter.ConvertToHTML(pass2Opts)
+	if err != nil {
+		return nil, fmt.Errorf("pass 2 HTML generation failed: %w", err)
+	}
+
+	log.Tagf(logging.Info, "PASS 2", "Converting to final PDF...")
+	pdf2, err := session.RenderPDF(ctx, pass2Opts.OutputFile, renderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("final PDF generation failed: %w", err)
+	}
+	end()
+
+	// FINISH: 문서 개요(북마크)와 메타데이터
+	end = rec.stage(log, StageFinish)
+	log.Tagf(logging.Info, "FINISH", "Writing PDF metadata and bookmarks...")
+	finishOpts := finisher.Options{Metadata: Metadata(converter.ResolveInfo(docOpts), opts.Producer), Logger: rec}
+	if opts.Outline.Depth > 0 {
+		finishOpts.Outline = Outline(result.Sections, pages.SkipPages, opts.Outline)
+	}
+	if updated, err := finisher.Update(pdf2, finishOpts); err != nil {
+		log.Warnf("Could not update PDF metadata/bookmarks: %v", err)
+	} else {
+		pdf2 = updated
+	}
+
+	end()
+
+	if _, err := opts.PDF.Write(pdf2); err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	if opts.HTML != nil {
+		htmlData, err := os.ReadFile(pass2Opts.OutputFile)
+		if err == nil {
+			_, err = opts.HTML.Write(htmlData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write HTML: %w", err)
+		}
+	}
+
+	result.Warnings = rec.warnings
+	return result, nil
+}
+
+// analyzerSections는 converter 섹션을 analyzer 입력으로 바꾼다.
+func analyzerSections(sections []converter.Section) []analyzer.SectionInput {
+	inputs := make([]analyzer.SectionInput, 0, len(sections))
+	for _, s := range sections {
+		input := analyzer.SectionInput{ID: s.ID, Title: s.Title, Level: s.Level}
+		for _, sub := range s.SubHeadings {
+			input.SubHeadings = append(input.SubHeadings, analyzer.SubHeading{ID: sub.ID, Title: sub.Title, Level: sub.Level})
+		}
+		// 참조되거나 목록에 나오는 라벨은 링크 대상이므로 PDF 위치가 필요함
+		for _, l := range s.Labels {
+			if !l.Referenced && !l.Listed {
+				continue
+			}
+			title := l.Title
+			if title == "" {
+				title = l.ID
+			}
+			input.SubHeadings = append(input.SubHeadings, analyzer.SubHeading{ID: l.ID, Title: title})
+		}
+		// 찾아보기 앵커는 찾아보기에서 링크됨
+		for _, id := range s.Anchors {
+			input.SubHeadings = append(input.SubHeadings, analyzer.SubHeading{ID: id, Title: id})
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+// recorder는 로그 항목에 현재 단계를 붙여 전달하고 결과에 담을 경고를
+// 모은다.
+type recorder struct {
+	next logging.Logger
+
+	mu       sync.Mutex
+	current  string
+	warnings []Warning
+}
+
+// stage는 새 빌드 단계로 바꾸고 시작을 보고한다. 반환한 함수는 종료를
+// 보고한다.
+func (r *recorder) stage(log logging.Printer, name string) func() {
+	r.mu.Lock()
+	r.current = name
+	r.mu.Unlock()
+	return log.Phase(name)
+}
+
+func (r *recorder) Log(e logging.Entry) {
+	r.mu.Lock()
+	if e.Phase == "" {
+		e.Phase = r.current
+	}
+	if e.Level >= logging.Warn {
+		r.warnings = append(r.warnings, Warning{Stage: e.Phase, Message: e.Message, File: e.File, Line: e.Line})
+	}
+	r.mu.Unlock()
+	r.next.Log(e)
+}
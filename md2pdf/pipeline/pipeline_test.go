@@ -0,0 +1,206 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"md2pdf/converter"
+	"md2pdf/finisher"
+	"md2pdf/logging"
+)
+
+// writeDocs는 입력 디렉터리에 Markdown 파일들을 만든다.
+func writeDocs(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuildWithoutOutput(t *testing.T) {
+	if _, err := Build(context.Background(), Options{Logger: logging.Discard}); err == nil {
+		t.Error("Build without PDF and HTML writers succeeded")
+	}
+}
+
+func TestBuildHTML(t *testing.T) {
+	dir := writeDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\n## Setup\n\n{{ .Missing }}\n",
+		"02-usage.md": "# Usage\n",
+	})
+	var phases []string
+	logger := logging.LoggerFunc(func(e logging.Entry) {
+		if e.Event == logging.EventPhaseStart {
+			phases = append(phases, e.Phase)
+		}
+	})
+
+	var html bytes.Buffer
+	res, err := Build(context.Background(), Options{
+		Document: converter.Options{InputDir: dir},
+		HTML:     &html,
+		Logger:   logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<h2") || !strings.Contains(html.String(), "Setup") {
+		t.Errorf("HTML output lacks the document body:\n%s", html.String())
+	}
+	if len(res.Sections) != 2 || res.Pages != nil {
+		t.Errorf("Sections = %d, Pages = %v; want 2 sections and no page analysis", len(res.Sections), res.Pages)
+	}
+	if !reflect.DeepEqual(phases, []string{StageHTML}) {
+		t.Errorf("phases = %v, want [%s]", phases, StageHTML)
+	}
+
+	// 경고는 로그와 함께 단계, 파일, 줄 정보를 담아 결과에 남음
+	want := []Warning{{Stage: StageHTML, Message: "Undefined variable .Missing", File: filepath.Join(dir, "01-intro.md"), Line: 5}}
+	if !reflect.DeepEqual(res.Warnings, want) {
+		t.Errorf("Warnings = %+v, want %+v", res.Warnings, want)
+	}
+}
+
+func TestBuildPDF(t *testing.T) {
+	found := false
+	for _, name := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell"} {
+		if _, err := exec.LookPath(name); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Skip("Chrome/Chromium not installed")
+	}
+	dir := writeDocs(t, map[string]string{
+		"01-intro.md": "# Intro\n\n## Setup\n",
+		"02-usage.md": "# Usage\n",
+	})
+
+	var pdf, html bytes.Buffer
+	res, err := Build(context.Background(), Options{
+		Document: converter.Options{InputDir: dir, Title: "Manual"},
+		PDF:      &pdf,
+		HTML:     &html,
+		Outline:  OutlineOptions{Depth: 2},
+		Logger:   logging.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) || html.Len() == 0 {
+		t.Fatal("Build did not write the PDF and HTML outputs")
+	}
+	if res.Pages == nil {
+		t.Fatal("PDF build has no page analysis")
+	}
+	for _, s := range res.Sections {
+		if s.PageNumber <= 0 {
+			t.Errorf("section %s has no page number", s.ID)
+		}
+	}
+	if !bytes.Contains(pdf.Bytes(), []byte("/Outlines")) {
+		t.Error("final PDF has no bookmarks")
+	}
+}
+
+func TestOutline(t *testing.T) {
+	sections := []converter.Section{
+		{Title: "Intro", ID: "intro", PageNumber: 1, SubHeadings: []converter.SubHeading{
+			{Title: "Setup", ID: "setup", Level: 2, PageNumber: 1},
+			{Title: "Linux", ID: "linux", Level: 3, PageNumber: 2},
+			{Title: "Details", ID: "details", Level: 4, PageNumber: 2},
+		}},
+		{Title: "Hidden", ID: "hidden", Unlisted: true, PageNumber: 3},
+		// H2 없이 시작하는 H3는 섹션 바로 아래에 둠
+		{Title: "Usage", ID: "usage", PageNumber: 3, SubHeadings: []converter.SubHeading{
+			{Title: "Flags", ID: "flags", Level: 3},
+		}},
+	}
+	tests := []struct {
+		name      string
+		skipPages int
+		opts      OutlineOptions
+		want      []finisher.Bookmark
+	}{
+		{"sections and H2", 2, OutlineOptions{Depth: 2}, []finisher.Bookmark{
+			{Title: "Intro", Dest: "intro", Page: 3, Children: []finisher.Bookmark{
+				{Title: "Setup", Dest: "setup", Page: 3},
+			}},
+			{Title: "Usage", Dest: "usage", Page: 5},
+		}},
+		{"nested H3 with cover and TOC", 2, OutlineOptions{Depth: 3, Cover: true, TOC: true}, []finisher.Bookmark{
+			{Title: "표지", Page: 1},
+			{Title: "목차", Page: 2},
+			{Title: "Intro", Dest: "intro", Page: 3, Children: []finisher.Bookmark{
+				{Title: "Setup", Dest: "setup", Page: 3, Children: []finisher.Bookmark{
+					{Title: "Linux", Dest: "linux", Page: 4},
+				}},
+			}},
+			{Title: "Usage", Dest: "usage", Page: 5, Children: []finisher.Bookmark{
+				{Title: "Flags", Dest: "flags"},
+			}},
+		}},
+		// 앞쪽 페이지가 하나뿐이면 목차 북마크를 만들지 않음
+		{"no TOC page", 1, OutlineOptions{Depth: 1, TOC: true}, []finisher.Bookmark{
+			{Title: "Intro", Dest: "intro", Page: 2},
+			{Title: "Usage", Dest: "usage", Page: 4},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Outline(sections, tt.skipPages, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Outline() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	m := Metadata(converter.DocumentInfo{Title: "Manual", Subtitle: "User Guide", Version: "1.2"}, "")
+	if m.Subject != "User Guide" || m.Producer != "md2pdf (Chrome/Skia)" || m.Creator != "md2pdf" {
+		t.Errorf("Metadata() = %+v", m)
+	}
+	m = Metadata(converter.DocumentInfo{Subtitle: "User Guide", Subject: "Setup"}, "custom")
+	if m.Subject != "Setup" || m.Producer != "custom" {
+		t.Errorf("Metadata() with subject and producer = %+v", m)
+	}
+	// 문서 발행일이 PDF 생성일
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if m = Metadata(converter.DocumentInfo{Date: date}, ""); !m.Date.Equal(date) {
+		t.Errorf("Metadata() date = %v, want %v", m.Date, date)
+	}
+}
+
+func TestAnalyzerSections(t *testing.T) {
+	got := analyzerSections([]converter.Section{{
+		Title: "Intro", ID: "intro", Level: 1,
+		SubHeadings: []converter.SubHeading{{Title: "Setup", ID: "setup", Level: 2}},
+		Labels: []converter.Label{
+			{ID: "fig:arch", Title: "Architecture", Referenced: true},
+			{ID: "tbl:flags", Listed: true},
+			{ID: "lst:unused"},
+		},
+		Anchors: []string{"idx-1"},
+	}})
+	var ids []string
+	for _, sub := range got[0].SubHeadings {
+		ids = append(ids, sub.ID+"="+sub.Title)
+	}
+	// 참조되지 않고 목록에도 없는 레이블은 목적지가 없으므로 제외
+	want := []string{"setup=Setup", "fig:arch=Architecture", "tbl:flags=tbl:flags", "idx-1=idx-1"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("sub headings = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,338 @@
+// Package preview는 Markdown 매뉴얼의 실시간 미리보기를 제공한다.
+// 입력 디렉터리를 감시하다가 바뀌면 문서를 다시 빌드하고, 연결된 브라우저
+// 탭은 서버 전송 이벤트(SSE)를 받아 스스로 새로 고친다.
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"md2pdf/converter"
+	"md2pdf/logging"
+	"md2pdf/renderer"
+)
+
+// Options는 미리보기 서버 옵션
+type Options struct {
+	Convert      converter.Options // 기본 변환 옵션 (OutputFile은 서버가 관리, Logger는 서버 메시지에도 사용)
+	Addr         string            // 수신 주소 (기본값: 127.0.0.1:8000)
+	PrintPreview bool              // PDF로 렌더링해 쪽 배치를 보여줌
+	Interval     time.Duration     // 파일 변경 폴링 간격 (기본값: 500ms)
+}
+
+// Server는 변경 시 문서를 다시 빌드하고 최신 빌드 결과를 제공한다.
+type Server struct {
+	opts    Options
+	tmpDir  string
+	session *renderer.Session
+	log     logging.Printer
+
+	mu      sync.RWMutex
+	html    []byte
+	pdf     []byte
+	version int
+	lastErr error
+
+	clientsMu sync.Mutex
+	clients   map[chan int]bool
+}
+
+// Serve는 문서를 빌드하고 HTTP 서버를 시작한 뒤, ctx가 취소될 때까지 변경될
+// 때마다 다시 빌드한다.
+func Serve(ctx context.Context, opts Options) error {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:8000"
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	opts.Convert.EmbedImages = true
+	opts.Convert.PDFMode = opts.PrintPreview
+	opts.Convert.SectionsJSON = ""
+	opts.Convert.PagesJSON = ""
+
+	tmpDir, err := os.MkdirTemp("", "md2pdf-serve-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s := &Server{opts: opts, tmpDir: tmpDir, log: logging.Use(opts.Convert.Logger), clients: make(map[chan int]bool)}
+	if opts.PrintPreview {
+		s.session, err = renderer.NewSession(ctx, renderer.SessionOptions{MaxTabs: 1, Logger: opts.Convert.Logger})
+		if err != nil {
+			return err
+		}
+		defer s.session.Close()
+	}
+
+	s.rebuild()
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+	httpServer := &http.Server{Handler: s.routes()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	go s.watch(ctx)
+
+	s.log.Infof("Preview server: http://%s/ (Ctrl+C to stop)", listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// rebuild는 Markdown을 변환하고(인쇄 미리보기 모드에서는 PDF도 렌더링)
+// 연결된 브라우저에 알린다.
+func (s *Server) rebuild() {
+	start := time.Now()
+	htmlPath := filepath.Join(s.tmpDir, "preview.html")
+	pdfPath := filepath.Join(s.tmpDir, "preview.pdf")
+
+	opts := s.opts.Convert
+	opts.OutputFile = htmlPath
+
+	var htmlData, pdfData []byte
+	_, err := converter.ConvertToHTML(opts)
+	if err == nil {
+		htmlData, err = os.ReadFile(htmlPath)
+	}
+	if err == nil && s.session != nil {
+		if err = s.session.Render(htmlPath, pdfPath, renderer.Options{}); err == nil {
+			pdfData, err = os.ReadFile(pdfPath)
+		}
+	}
+
+	s.mu.Lock()
+	s.version++
+	s.lastErr = err
+	if err == nil {
+		s.html = htmlData
+		s.pdf = pdfData
+	}
+	version := s.version
+	s.mu.Unlock()
+
+	if err != nil {
+		s.log.Errorf("Rebuild failed: %v", err)
+	} else {
+		s.log.Infof("Rebuilt in %.1fs (version %d)", time.Since(start).Seconds(), version)
+	}
+	s.broadcast(version)
+}
+
+// watch는 입력 디렉터리와 설정 파일을 폴링해 바뀌면 다시 빌드한다.
+// 폴링 방식이라 외부 의존성이 없고 네트워크 드라이브에서도 동작한다.
+func (s *Server) watch(ctx context.Context) {
+	last := s.snapshot()
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := s.snapshot()
+			if current != last {
+				last = current
+				s.log.Infof("Change detected, rebuilding...")
+				s.rebuild()
+			}
+		}
+	}
+}
+
+// snapshot은 감시 대상 파일(Markdown, _sidebar.md, 자산, 설정 파일)을 경로,
+// 크기, 수정 시각으로 요약한다.
+func (s *Server) snapshot() string {
+	var b strings.Builder
+	add := func(path string, info fs.FileInfo) {
+		fmt.Fprintf(&b, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	_ = filepath.Walk(s.opts.Convert.InputDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && strings.HasPrefix(info.Name(), ".") && path != s.opts.Convert.InputDir {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			add(path, info)
+		}
+		return nil
+	})
+	if s.opts.Convert.ConfigFile != "" {
+		if info, err := os.Stat(s.opts.Convert.ConfigFile); err == nil {
+			add(s.opts.Convert.ConfigFile, info)
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/__md2pdf/events", s.handleEvents)
+	mux.HandleFunc("/__md2pdf/preview.pdf", s.handlePDF)
+
+	// 내장하지 않은 자산은 입력 디렉터리에서 제공
+	static := http.FileServer(http.Dir(s.opts.Convert.InputDir))
+	mux.HandleFunc("/assets/", func(w http.ResponseWriter, r *http.Request) {
+		static.ServeHTTP(w, r)
+	})
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	htmlData, version, lastErr := s.html, s.version, s.lastErr
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	var page string
+	switch {
+	case s.opts.PrintPreview:
+		page = fmt.Sprintf(printPreviewPage, version)
+	case htmlData == nil:
+		page = "<!DOCTYPE html><html><body></body></html>"
+	default:
+		page = string(htmlData)
+	}
+	page = injectBeforeBodyEnd(page, errorBanner(lastErr)+fmt.Sprintf(reloadScript, version))
+	_, _ = w.Write([]byte(page))
+}
+
+func (s *Server) handlePDF(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	pdfData := s.pdf
+	s.mu.RUnlock()
+	if pdfData == nil {
+		http.Error(w, "PDF not available", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(pdfData)
+}
+
+// handleEvents는 빌드 버전을 브라우저에 스트리밍한다 (서버 전송 이벤트).
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+
+	ch := make(chan int, 1)
+	s.clientsMu.Lock()
+	s.clients[ch] = true
+	s.clientsMu.Unlock()
+	defer func() {
+		s.clientsMu.Lock()
+		delete(s.clients, ch)
+		s.clientsMu.Unlock()
+	}()
+
+	s.mu.RLock()
+	fmt.Fprintf(w, "event: hello\ndata: %d\n\n", s.version)
+	s.mu.RUnlock()
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case version := <-ch:
+			fmt.Fprintf(w, "event: reload\ndata: %d\n\n", version)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) broadcast(version int) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- version:
+		default:
+		}
+	}
+}
+
+func injectBeforeBodyEnd(page, snippet string) string {
+	idx := strings.LastIndex(strings.ToLower(page), "</body>")
+	if idx < 0 {
+		return page + snippet
+	}
+	return page[:idx] + snippet + page[idx:]
+}
+
+func errorBanner(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(err.Error())
+	return `<div style="position:fixed;top:0;left:0;right:0;z-index:99999;padding:12px 16px;` +
+		`background:#b91c1c;color:#fff;font:14px/1.4 monospace;white-space:pre-wrap">` +
+		`md2pdf: rebuild failed (showing last good build)` + "\n" + msg + `</div>`
+}
+
+// reloadScript는 페이지를 받았을 때보다 새 빌드가 알려지면 페이지를 새로
+// 고친다 (hello 이벤트는 페이지를 읽는 동안 끝난 빌드를 처리).
+const reloadScript = `<script>
+(() => {
+	const loaded = %d;
+	const source = new EventSource('/__md2pdf/events');
+	const reload = (e) => {
+		if (Number(e.data) === loaded) return;
+		sessionStorage.setItem('md2pdf-scroll', String(window.scrollY));
+		location.reload();
+	};
+	source.addEventListener('hello', reload);
+	source.addEventListener('reload', reload);
+	const y = sessionStorage.getItem('md2pdf-scroll');
+	if (y !== null) {
+		sessionStorage.removeItem('md2pdf-scroll');
+		window.addEventListener('load', () => window.scrollTo(0, Number(y)));
+	}
+})();
+</script>`
+
+// printPreviewPage는 렌더링한 PDF를 포함해 편집 중에도 쪽 배치(쪽 나눔,
+// 머리글, 바닥글)를 볼 수 있게 한다.
+const printPreviewPage = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="UTF-8">
+<title>md2pdf print preview</title>
+<style>html, body { margin: 0; height: 100%%; background: #525659; } iframe { border: 0; width: 100%%; height: 100%%; }</style>
+</head>
+<body>
+<iframe src="/__md2pdf/preview.pdf?v=%d"></iframe>
+</body>
+</html>`
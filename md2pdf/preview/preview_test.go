@@ -0,0 +1,161 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"md2pdf/converter"
+	"md2pdf/logging"
+)
+
+// newTestServer는 Markdown 파일 하나가 있는 입력 디렉터리로 서버를 만들고
+// 첫 빌드를 마친다. 반환하는 경로는 그 Markdown 파일이다.
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	md := filepath.Join(dir, "intro.md")
+	if err := os.WriteFile(md, []byte("# Intro\n\nfirst build\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		opts: Options{
+			Convert:  converter.Options{InputDir: dir, Logger: logging.Discard},
+			Interval: 10 * time.Millisecond,
+		},
+		tmpDir:  t.TempDir(),
+		log:     logging.Use(logging.Discard),
+		clients: make(map[chan int]bool),
+	}
+	s.rebuild()
+	return s, md
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestServeLatestBuild(t *testing.T) {
+	s, md := newTestServer(t)
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	page := get(t, srv.URL+"/")
+	for _, want := range []string{"first build", "const loaded = 1;", "/__md2pdf/events"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+	if i, j := strings.Index(page, "EventSource"), strings.LastIndex(page, "</body>"); i < 0 || i > j {
+		t.Error("reload script is not injected before </body>")
+	}
+
+	// 재빌드하면 새 내용과 새 버전을 제공
+	if err := os.WriteFile(md, []byte("# Intro\n\nsecond build\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.rebuild()
+	page = get(t, srv.URL+"/")
+	if !strings.Contains(page, "second build") || !strings.Contains(page, "const loaded = 2;") {
+		t.Errorf("page does not show the second build:\n%s", page)
+	}
+
+	// 빌드가 실패하면 마지막 성공 빌드와 오류 배너를 제공
+	s.opts.Convert.InputDir = filepath.Join(t.TempDir(), "missing")
+	s.rebuild()
+	page = get(t, srv.URL+"/")
+	if !strings.Contains(page, "second build") || !strings.Contains(page, "rebuild failed (showing last good build)") {
+		t.Errorf("page does not keep the last good build with an error banner:\n%s", page)
+	}
+
+	resp, err := http.Get(srv.URL + "/__md2pdf/preview.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("PDF without print preview: status %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestEventsAnnounceRebuilds(t *testing.T) {
+	s, md := newTestServer(t)
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/__md2pdf/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	events := bufio.NewReader(resp.Body)
+	next := func() string {
+		var lines []string
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading events: %v", err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "|")
+			}
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	if got := next(); got != "event: hello|data: 1" {
+		t.Errorf("first event = %q", got)
+	}
+
+	// 파일 변경을 감지해 재빌드하고 연결된 브라우저에 알림
+	go s.watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(md, []byte("# Intro\n\nchanged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); got != "event: reload|data: 2" {
+		t.Errorf("event after change = %q", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s, md := newTestServer(t)
+	before := s.snapshot()
+	if err := os.MkdirAll(filepath.Join(s.opts.Convert.InputDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.opts.Convert.InputDir, ".git", "HEAD"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s.snapshot() != before {
+		t.Error("hidden directories must not trigger a rebuild")
+	}
+	if err := os.WriteFile(md, []byte("# Intro\n\nlonger content\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s.snapshot() == before {
+		t.Error("a changed markdown file must trigger a rebuild")
+	}
+}
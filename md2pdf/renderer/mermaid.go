@@ -0,0 +1,99 @@
+package renderer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// MermaidDiagram은 RenderMermaid로 렌더링할 다이어그램
+type MermaidDiagram struct {
+	ID     string `json:"id"` // SVG 요소 ID (요청 안에서 고유)
+	Source string `json:"source"`
+}
+
+// MermaidResult는 다이어그램 하나의 SVG, 또는 해석이나 렌더링에 실패했을
+// 때의 Mermaid 오류 메시지
+type MermaidResult struct {
+	SVG   string `json:"svg"`
+	Error string `json:"error"`
+}
+
+// mermaidLoadScript는 URL에서 Mermaid 라이브러리를 읽는다.
+const mermaidLoadScript = `new Promise((resolve, reject) => {
+	const s = document.createElement('script');
+	s.src = %s;
+	s.onload = () => resolve(true);
+	s.onerror = () => reject(new Error('failed to load ' + s.src));
+	document.head.appendChild(s);
+})`
+
+// mermaidRenderScript는 다이어그램을 하나씩 렌더링한다. 한 다이어그램의
+// 구문 오류가 다른 다이어그램을 막지 않는다.
+const mermaidRenderScript = `(async (diagrams) => {
+	mermaid.initialize({ startOnLoad: false, theme: 'default', securityLevel: 'loose' });
+	const results = [];
+	for (const d of diagrams) {
+		try {
+			const out = await mermaid.render(d.id, d.source);
+			results.push({ svg: typeof out === 'string' ? out : out.svg, error: '' });
+		} catch (e) {
+			results.push({ svg: '', error: String((e && e.message) || e) });
+		}
+		const leftover = document.getElementById('d' + d.id);
+		if (leftover) leftover.remove();
+	}
+	return results;
+})(%s)`
+
+// RenderMermaid는 세션의 새 탭에서 Mermaid 다이어그램을 SVG로 렌더링한다.
+// Mermaid 라이브러리는 JavaScript 원본(script)으로 주거나, script가 비어
+// 있으면 url에서 읽는다. 다이어그램별 오류는 결과에 담고, 아무것도 렌더링할
+// 수 없을 때만 오류를 반환한다.
+func (s *Session) RenderMermaid(ctx context.Context, script, url string, diagrams []MermaidDiagram) ([]MermaidResult, error) {
+	if len(diagrams) == 0 {
+		return nil, nil
+	}
+
+	select {
+	case s.tabs <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.tabs }()
+
+	tabCtx, cancel := chromedp.NewContext(s.browserCtx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	tabCtx, cancelTimeout := context.WithTimeout(tabCtx, 2*time.Minute)
+	defer cancelTimeout()
+
+	awaitPromise := func(p *runtime.EvaluateParams) *runtime.EvaluateParams { return p.WithAwaitPromise(true) }
+	load := chromedp.Evaluate(script+"\n;true", nil)
+	if script == "" {
+		src, _ := json.Marshal(url)
+		var ok bool
+		load = chromedp.Evaluate(fmt.Sprintf(mermaidLoadScript, src), &ok, awaitPromise)
+	}
+	if err := chromedp.Run(tabCtx, chromedp.Navigate("about:blank"), load); err != nil {
+		return nil, fmt.Errorf("failed to load Mermaid: %w", err)
+	}
+
+	input, err := json.Marshal(diagrams)
+	if err != nil {
+		return nil, err
+	}
+	var results []MermaidResult
+	if err := chromedp.Run(tabCtx, chromedp.Evaluate(fmt.Sprintf(mermaidRenderScript, input), &results, awaitPromise)); err != nil {
+		return nil, fmt.Errorf("failed to render Mermaid diagrams: %w", err)
+	}
+	if len(results) != len(diagrams) {
+		return nil, fmt.Errorf("failed to render Mermaid diagrams: got %d results for %d diagrams", len(results), len(diagrams))
+	}
+	return results, nil
+}
@@ -0,0 +1,60 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// networkGuard는 탭이 보내는 모든 네트워크 요청을 막고 기록한다.
+// 오프라인 빌드에서 쓰며, 요청이 있다는 것은 자산이 내장되거나 인라인되지
+// 않았다는 뜻이다.
+type networkGuard struct {
+	mu   sync.Mutex
+	urls map[string]bool
+}
+
+func isNetworkURL(u string) bool {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://", "ftp://"} {
+		if strings.HasPrefix(u, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// install은 페이지 이동 전에 실행해야 한다.
+func (g *networkGuard) install(ctx context.Context) error {
+	g.urls = make(map[string]bool)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if e, ok := ev.(*network.EventRequestWillBeSent); ok && isNetworkURL(e.Request.URL) {
+			g.mu.Lock()
+			g.urls[e.Request.URL] = true
+			g.mu.Unlock()
+		}
+	})
+	return chromedp.Run(ctx,
+		network.Enable(),
+		network.SetBlockedURLS([]string{"http://*", "https://*", "ws://*", "wss://*", "ftp://*"}),
+	)
+}
+
+// err는 시도된 요청이 있으면 그 목록을 담은 오류를 반환한다.
+func (g *networkGuard) err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.urls) == 0 {
+		return nil
+	}
+	var urls []string
+	for u := range g.urls {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	return fmt.Errorf("offline build: %d network requests attempted:\n  %s", len(urls), strings.Join(urls, "\n  "))
+}
@@ -0,0 +1,108 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"md2pdf/logging"
+)
+
+// readyScript는 페이지마다 한 번 준비 상태 감시를 설치한다. 글꼴과 이미지는
+// Promise로 추적하고, Mermaid 다이어그램과 템플릿 플래그는 폴링할 때마다
+// 확인한다.
+const readyScript = `(() => {
+	if (window.__md2pdfProbe) return true;
+	const probe = window.__md2pdfProbe = { fonts: false, images: false };
+	document.fonts.ready.then(() => { probe.fonts = true; });
+	Promise.all(Array.from(document.images).map(img =>
+		img.complete ? Promise.resolve() : img.decode().catch(() => {})
+	)).then(() => { probe.images = true; });
+	return true;
+})()`
+
+// readyStatus는 준비 신호별 상태를 보고한다.
+const readyStatus = `(() => {
+	const probe = window.__md2pdfProbe || {};
+	const diagrams = Array.from(document.querySelectorAll('.mermaid'));
+	const rendered = diagrams.filter(el =>
+		el.getAttribute('data-processed') === 'true' || el.querySelector('svg') !== null
+	).length;
+	return {
+		fonts: probe.fonts === true,
+		images: probe.images === true,
+		mermaidTotal: diagrams.length,
+		mermaidDone: rendered,
+		flag: !('md2pdfReady' in window) || window.md2pdfReady === true,
+	};
+})()`
+
+// pageStatus는 readyStatus가 반환하는 객체와 같은 구조다.
+type pageStatus struct {
+	Fonts        bool `json:"fonts"`
+	Images       bool `json:"images"`
+	MermaidTotal int  `json:"mermaidTotal"`
+	MermaidDone  int  `json:"mermaidDone"`
+	Flag         bool `json:"flag"`
+}
+
+// pending은 아직 준비되지 않은 신호 목록을 반환한다.
+func (st pageStatus) pending() []string {
+	var names []string
+	if !st.Fonts {
+		names = append(names, "fonts (document.fonts.ready)")
+	}
+	if !st.Images {
+		names = append(names, "images (decode)")
+	}
+	if st.MermaidDone < st.MermaidTotal {
+		names = append(names, fmt.Sprintf("mermaid (%d/%d diagrams rendered)", st.MermaidDone, st.MermaidTotal))
+	}
+	if !st.Flag {
+		names = append(names, "template flag (window.md2pdfReady)")
+	}
+	return names
+}
+
+// waitForReady는 모든 준비 신호가 켜지거나 상한 시간에 이를 때까지 페이지를
+// 폴링한다. 시간 초과는 경고로 알리고 렌더링을 실패시키지는 않는다.
+func waitForReady(ctx context.Context, ceiling time.Duration, logger logging.Printer) error {
+	var ok bool
+	if err := chromedp.Run(ctx, chromedp.Evaluate(readyScript, &ok)); err != nil {
+		return fmt.Errorf("failed to install readiness probes: %w", err)
+	}
+
+	start := time.Now()
+	deadline := start.Add(ceiling)
+	for {
+		var st pageStatus
+		if err := chromedp.Run(ctx, chromedp.Evaluate(readyStatus, &st)); err != nil {
+			return fmt.Errorf("failed to query page readiness: %w", err)
+		}
+
+		pending := st.pending()
+		if len(pending) == 0 {
+			if st.MermaidTotal > 0 {
+				logger.Infof("Page ready in %.1fs (%d Mermaid diagrams)", time.Since(start).Seconds(), st.MermaidTotal)
+			} else {
+				logger.Infof("Page ready in %.1fs", time.Since(start).Seconds())
+			}
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			logger.Warnf("Page not ready after %s, printing anyway. Timed out waiting for: %s",
+				ceiling, strings.Join(pending, ", "))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -5,7 +5,6 @@ package renderer
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +12,8 @@ import (
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
+
+	"md2pdf/logging"
 )
 
 // Options for PDF rendering
@@ -20,10 +21,100 @@ type Options struct {
 	Landscape bool
 	Scale     float64
 	Timeout   int // seconds
+	// ReadyTimeout은 준비 신호(글꼴, 이미지, Mermaid 다이어그램, 템플릿
+	// 플래그)를 기다리는 상한 시간(초). 기본값: 30
+	ReadyTimeout int
+	// Offline은 모든 네트워크 접근을 막고, 페이지가 요청을 시도하면 렌더링을
+	// 실패시킨다.
+	Offline bool
+	Logger  logging.Logger // 기본값: logging.Default
+}
+
+// Session은 여러 HTML 문서를 렌더링하는 실행 중인 Chrome 브라우저다.
+// Render 호출마다 별도 탭을 쓰므로 브라우저를 한 번 띄워 빌드의 두 패스와
+// 여러 문서를 모두 처리한다. 여러 고루틴에서 동시에 사용할 수 있으며,
+// 동시에 실행되는 렌더링은 최대 MaxTabs개다.
+type Session struct {
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	tabs          chan struct{}
+	log           logging.Printer
+}
+
+// SessionOptions는 브라우저 세션 시작 옵션
+type SessionOptions struct {
+	MaxTabs int            // 동시에 쓰는 탭 수 (기본값: 4)
+	Logger  logging.Logger // 기본값: logging.Default
 }
 
-// RenderToPDF converts an HTML file to a PDF file using Chrome/Chromium.
+// NewSession은 Chrome을 실행하고, Close를 호출하거나 ctx가 취소될 때까지
+// 문서를 렌더링하는 세션을 반환한다.
+func NewSession(ctx context.Context, opts SessionOptions) (*Session, error) {
+	maxTabs := opts.MaxTabs
+	if maxTabs <= 0 {
+		maxTabs = 4
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(
+		ctx,
+		append(
+			chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.Flag("disable-gpu", true),
+			chromedp.Flag("no-sandbox", true),
+			chromedp.Flag("disable-dev-shm-usage", true),
+			chromedp.Flag("disable-extensions", true),
+			chromedp.Flag("disable-background-networking", true),
+		)...,
+	)
+
+	// 실패는 오류로 반환되고, chromedp 자체 메시지(대부분 최신 Chrome의
+	// 알 수 없는 프로토콜 이벤트)는 디버그 출력으로 보낸다.
+	logger := logging.Use(opts.Logger)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx,
+		chromedp.WithLogf(logger.Debugf), chromedp.WithErrorf(logger.Debugf))
+
+	// 실행 실패가 여기서 드러나도록 브라우저를 바로 시작
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return nil, fmt.Errorf("failed to start Chrome: %w", err)
+	}
+	logger.Infof("Chrome session started")
+
+	return &Session{
+		allocCancel:   allocCancel,
+		browserCtx:    browserCtx,
+		browserCancel: browserCancel,
+		tabs:          make(chan struct{}, maxTabs),
+		log:           logger,
+	}, nil
+}
+
+// Close는 브라우저를 종료한다. 여러 번 호출해도 안전하다.
+func (s *Session) Close() {
+	if s == nil || s.browserCancel == nil {
+		return
+	}
+	s.browserCancel()
+	s.allocCancel()
+	s.browserCancel = nil
+}
+
+// RenderToPDF는 Chrome/Chromium으로 HTML 파일을 PDF 파일로 변환한다.
+// 이 변환 하나를 위해 브라우저를 띄우므로, 여러 문서를 브라우저 하나로
+// 렌더링하려면 Session을 사용한다.
 func RenderToPDF(inputHTML, outputPDF string, opts Options) error {
+	session, err := NewSession(context.Background(), SessionOptions{MaxTabs: 1, Logger: opts.Logger})
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return session.Render(inputHTML, outputPDF, opts)
+}
+
+// Render는 세션의 새 탭에서 HTML 파일을 PDF 파일로 변환한다.
+func (s *Session) Render(inputHTML, outputPDF string, opts Options) error {
 	// Validate input
 	if inputHTML == "" {
 		return fmt.Errorf("input HTML file path is required")
@@ -33,11 +124,42 @@ func RenderToPDF(inputHTML, outputPDF string, opts Options) error {
 	if outputPDF == "" {
 		outputPDF = strings.TrimSuffix(inputHTML, filepath.Ext(inputHTML)) + ".pdf"
 	}
+	logger := s.logger(opts)
+	logger.Infof("Output: %s", outputPDF)
+
+	buf, err := s.RenderPDF(context.Background(), inputHTML, opts)
+	if err != nil {
+		return err
+	}
+
+	// 출력 디렉터리 생성
+	if dir := filepath.Dir(outputPDF); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	// PDF 쓰기
+	if err := os.WriteFile(outputPDF, buf, 0644); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	logger.Successf("Generated PDF: %s (%.1f MB)", outputPDF, float64(len(buf))/(1024*1024))
+	return nil
+}
+
+// RenderPDF는 세션의 새 탭에서 HTML 파일을 변환해 PDF 바이트를 반환한다.
+// ctx를 취소하면 렌더링을 중단한다.
+func (s *Session) RenderPDF(ctx context.Context, inputHTML string, opts Options) ([]byte, error) {
+	if inputHTML == "" {
+		return nil, fmt.Errorf("input HTML file path is required")
+	}
+	logger := s.logger(opts)
 
 	// Resolve absolute path for file:// URL
 	absInput, err := filepath.Abs(inputHTML)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
 	// Default scale
@@ -53,47 +175,51 @@ func RenderToPDF(inputHTML, outputPDF string, opts Options) error {
 	}
 
 	fileURL := "file://" + absInput
-	fmt.Printf("[INFO] Converting: %s\n", absInput)
-	fmt.Printf("[INFO] Output: %s\n", outputPDF)
+	logger.Infof("Converting: %s", absInput)
 
-	// Create Chrome context
-	allocCtx, allocCancel := chromedp.NewExecAllocator(
-		context.Background(),
-		append(
-			chromedp.DefaultExecAllocatorOptions[:],
-			chromedp.Flag("disable-gpu", true),
-			chromedp.Flag("no-sandbox", true),
-			chromedp.Flag("disable-dev-shm-usage", true),
-			chromedp.Flag("disable-extensions", true),
-			chromedp.Flag("disable-background-networking", true),
-		)...,
-	)
-	defer allocCancel()
+	// 빈 탭 자리가 날 때까지 대기
+	select {
+	case s.tabs <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.tabs }()
 
-	ctx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	// 실행 중인 브라우저에 새 탭 열기
+	tabCtx, cancel := chromedp.NewContext(s.browserCtx)
 	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
 
 	// Set timeout
-	ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
-	defer cancel()
+	tabCtx, cancelTimeout := context.WithTimeout(tabCtx, time.Duration(timeout)*time.Second)
+	defer cancelTimeout()
+	ctx = tabCtx
+
+	// 오프라인 모드에서는 네트워크 요청을 막고 기록
+	var guard *networkGuard
+	if opts.Offline {
+		guard = &networkGuard{}
+		if err := guard.install(ctx); err != nil {
+			return nil, fmt.Errorf("failed to enable offline mode: %w", err)
+		}
+	}
 
 	// Navigate and wait
 	if err := chromedp.Run(ctx,
 		chromedp.Navigate(fileURL),
 		chromedp.WaitReady("body"),
-		chromedp.Sleep(3*time.Second),
 	); err != nil {
-		return fmt.Errorf("failed to load page: %w", err)
+		return nil, fmt.Errorf("failed to load page: %w", err)
 	}
 
-	// Wait for Mermaid rendering
-	mermaidDone := false
-	_ = chromedp.Run(ctx,
-		chromedp.Evaluate(`document.querySelector('.mermaid') !== null`, &mermaidDone),
-	)
-	if mermaidDone {
-		fmt.Println("[INFO] Waiting for Mermaid diagrams...")
-		_ = chromedp.Run(ctx, chromedp.Sleep(5*time.Second))
+	// 글꼴, 이미지, Mermaid 다이어그램, 템플릿 플래그가 준비될 때까지 대기
+	readyTimeout := opts.ReadyTimeout
+	if readyTimeout <= 0 {
+		readyTimeout = 30
+	}
+	if err := waitForReady(ctx, time.Duration(readyTimeout)*time.Second, logger); err != nil {
+		return nil, err
 	}
 
 	// Print to PDF
@@ -115,21 +241,21 @@ func RenderToPDF(inputHTML, outputPDF string, opts Options) error {
 			return err
 		}),
 	); err != nil {
-		return fmt.Errorf("failed to generate PDF: %w", err)
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
 	}
 
-	// Ensure output directory exists
-	if dir := filepath.Dir(outputPDF); dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+	if guard != nil {
+		if err := guard.err(); err != nil {
+			return nil, err
 		}
 	}
+	return buf, nil
+}
 
-	// Write PDF
-	if err := os.WriteFile(outputPDF, buf, 0644); err != nil {
-		return fmt.Errorf("failed to write PDF: %w", err)
+// logger는 렌더링별 로거를 반환하고, 없으면 세션의 로거를 쓴다.
+func (s *Session) logger(opts Options) logging.Printer {
+	if opts.Logger != nil {
+		return logging.Use(opts.Logger)
 	}
-
-	fmt.Printf("[SUCCESS] Generated PDF: %s (%.1f MB)\n", outputPDF, float64(len(buf))/(1024*1024))
-	return nil
+	return s.log
 }
@@ -0,0 +1,144 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"md2pdf/logging"
+)
+
+func TestPending(t *testing.T) {
+	tests := []struct {
+		name string
+		st   pageStatus
+		want string
+	}{
+		{"all ready", pageStatus{Fonts: true, Images: true, MermaidTotal: 2, MermaidDone: 2, Flag: true}, ""},
+		{"fonts and images", pageStatus{MermaidTotal: 0, Flag: true}, "fonts (document.fonts.ready), images (decode)"},
+		{"mermaid", pageStatus{Fonts: true, Images: true, MermaidTotal: 3, MermaidDone: 1, Flag: true}, "mermaid (1/3 diagrams rendered)"},
+		{"template flag", pageStatus{Fonts: true, Images: true}, "template flag (window.md2pdfReady)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(tt.st.pending(), ", "); got != tt.want {
+				t.Errorf("pending() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestSession은 Chrome이 설치되어 있을 때만 브라우저 세션을 띄운다.
+func newTestSession(t *testing.T, logger logging.Logger) *Session {
+	t.Helper()
+	found := false
+	for _, name := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell"} {
+		if _, err := exec.LookPath(name); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Skip("Chrome/Chromium not installed")
+	}
+	s, err := NewSession(context.Background(), SessionOptions{MaxTabs: 2, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// writeHTML은 body와 script를 담은 HTML 파일을 임시 디렉터리에 만든다.
+func writeHTML(t *testing.T, name, body, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	html := fmt.Sprintf("<!DOCTYPE html><html><body>%s<script>%s</script></body></html>", body, script)
+	if err := os.WriteFile(path, []byte(html), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSessionRendersManyDocuments(t *testing.T) {
+	s := newTestSession(t, logging.Discard)
+
+	// 한 브라우저에서 탭 수(2)보다 많은 문서를 동시에 렌더링
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		path := writeHTML(t, fmt.Sprintf("doc%d.html", i), fmt.Sprintf("<h1>Document %d</h1>", i), "")
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			buf, err := s.RenderPDF(context.Background(), path, Options{Timeout: 60})
+			if err == nil && !bytes.HasPrefix(buf, []byte("%PDF-")) {
+				err = fmt.Errorf("output is not a PDF")
+			}
+			errs[i] = err
+		}(i, path)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("document %d: %v", i, err)
+		}
+	}
+}
+
+func TestRenderWaitsForReadySignals(t *testing.T) {
+	var mu sync.Mutex
+	var warnings []string
+	s := newTestSession(t, logging.LoggerFunc(func(e logging.Entry) {
+		if e.Level == logging.Warn {
+			mu.Lock()
+			warnings = append(warnings, e.Message)
+			mu.Unlock()
+		}
+	}))
+
+	tests := []struct {
+		name     string
+		script   string
+		wantWarn string
+	}{
+		// 템플릿 플래그가 늦게 켜지면 고정 대기 없이 그때까지 기다림
+		{"flag set late", "window.md2pdfReady = false; setTimeout(() => { window.md2pdfReady = true; }, 300);", ""},
+		// 플래그가 끝내 켜지지 않으면 상한 시간 뒤 경고와 함께 인쇄
+		{"flag never set", "window.md2pdfReady = false;", "template flag (window.md2pdfReady)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			warnings = nil
+			mu.Unlock()
+			path := writeHTML(t, "doc.html", "<p>body</p>", tt.script)
+			start := time.Now()
+			buf, err := s.RenderPDF(context.Background(), path, Options{Timeout: 60, ReadyTimeout: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(buf, []byte("%PDF-")) {
+				t.Fatal("output is not a PDF")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			got := strings.Join(warnings, "\n")
+			switch {
+			case tt.wantWarn == "" && got != "":
+				t.Errorf("unexpected warning: %s", got)
+			case tt.wantWarn != "" && !strings.Contains(got, tt.wantWarn):
+				t.Errorf("warnings = %q, want %q", got, tt.wantWarn)
+			case tt.wantWarn == "" && time.Since(start) > 2*time.Second:
+				t.Errorf("render took %s, want less than the ready timeout", time.Since(start))
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+//go:build ignore
+
+// fetch_assets는 템플릿이 참조하는 CDN 자산을 converter/assets/cdn에 내려받아
+// 오프라인 렌더링용으로 내장할 수 있게 한다.
+//
+// 사용법 (md2pdf 디렉터리에서): go run ./scripts/fetch_assets.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	templateGlob = "converter/templates/*.html"
+	vendorDir    = "converter/assets/cdn"
+
+	// Google Fonts는 최신 브라우저에만 woff2를 제공
+	userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	reTemplateURL = regexp.MustCompile(`(?:href|src)="(https?://[^"]+)"|@import\s+url\(\s*['"]?(https?://[^'")]+)`)
+	reCSSURL      = regexp.MustCompile(`url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+	reQueryChars  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+func main() {
+	templates, err := filepath.Glob(templateGlob)
+	if err != nil || len(templates) == 0 {
+		fmt.Fprintf(os.Stderr, "[ERROR] No templates found (run from the md2pdf directory)\n")
+		os.Exit(1)
+	}
+
+	seen := make(map[string]bool)
+	var queue []string
+	for _, tmpl := range templates {
+		data, err := os.ReadFile(tmpl)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %v\n", err)
+			os.Exit(1)
+		}
+		for _, m := range reTemplateURL.FindAllStringSubmatch(string(data), -1) {
+			u := m[1] + m[2]
+			if !seen[u] {
+				seen[u] = true
+				queue = append(queue, u)
+			}
+		}
+	}
+
+	failed := 0
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		data, err := download(u)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] %s: %v\n", u, err)
+			failed++
+			continue
+		}
+
+		dest := localPath(u)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("[INFO] %s -> %s (%d KB)\n", u, dest, len(data)/1024)
+
+		// 스타일시트가 참조하는 글꼴과 이미지도 내려받음
+		if !strings.HasSuffix(path.Base(mustParse(u).Path), ".css") && !strings.Contains(u, "fonts.googleapis.com") {
+			continue
+		}
+		base := mustParse(u)
+		for _, m := range reCSSURL.FindAllStringSubmatch(string(data), -1) {
+			ref := strings.TrimSpace(m[1])
+			if strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") {
+				continue
+			}
+			rel, err := url.Parse(ref)
+			if err != nil {
+				continue
+			}
+			abs := base.ResolveReference(rel)
+			abs.Fragment = ""
+			if s := abs.String(); !seen[s] {
+				seen[s] = true
+				queue = append(queue, s)
+			}
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "[ERROR] %d downloads failed\n", failed)
+		os.Exit(1)
+	}
+	fmt.Printf("[SUCCESS] Vendored %d assets into %s\n", len(seen), vendorDir)
+}
+
+// localPath는 converter.assetPath와 같은 규칙을 따라야 한다.
+func localPath(rawURL string) string {
+	u := mustParse(rawURL)
+	p := path.Join(u.Host, u.Path)
+	if u.RawQuery != "" {
+		p += "_" + reQueryChars.ReplaceAllString(u.RawQuery, "_")
+	}
+	return filepath.Join(vendorDir, filepath.FromSlash(p))
+}
+
+func mustParse(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Invalid URL %s: %v\n", rawURL, err)
+		os.Exit(1)
+	}
+	return u
+}
+
+func download(u string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+
+	"md2pdf/preview"
+)
+
+// runServe는 `md2pdf serve`를 구현한다: 변경 시 다시 빌드하고 브라우저를
+// 새로 고치는 실시간 미리보기 서버
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	doc := addDocFlags(fs, "")
+	addr := fs.String("addr", "127.0.0.1:8000", "Listen address")
+	printPreview := fs.Bool("print", false, "Print-preview mode: render to PDF and show the page layout")
+	logs := addLogFlags(fs, os.Stdout)
+	fs.Usage = commandUsage(fs, "Live preview server", "md2pdf serve -i <input_dir> [options]")
+	_ = fs.Parse(args)
+
+	if *doc.inputDir == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	logs.setup()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	convertOpts := doc.converterOptions()
+	convertOpts.InlineAssets = *doc.offline
+
+	err := preview.Serve(ctx, preview.Options{
+		Convert:      convertOpts,
+		Addr:         *addr,
+		PrintPreview: *printPreview,
+	})
+	if err != nil {
+		logs.fail("%v", err)
+	}
+}